@@ -2,9 +2,11 @@ package http
 
 import (
 	"errors"
+	"math/rand/v2"
 	"net"
 	"net/http"
-	"strings"
+	"net/url"
+	"slices"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,6 +18,13 @@ type RetryConfig struct {
 	InitialBackoff    time.Duration
 	MaxBackoff        time.Duration
 	BackoffMultiplier float64
+	// Jitter is the fraction of each backoff that is randomly taken off.
+	Jitter float64
+	// RetryOnStatusCodes are the response statuses to retry, any 5xx when
+	// empty. Failed requests without a response are always retryable.
+	RetryOnStatusCodes []int
+	// IdempotentOnly retries GET, HEAD, OPTIONS, TRACE, PUT and DELETE only.
+	IdempotentOnly bool
 }
 
 func DefaultRetryConfig() RetryConfig {
@@ -24,19 +33,59 @@ func DefaultRetryConfig() RetryConfig {
 		InitialBackoff:    100 * time.Millisecond,
 		MaxBackoff:        2 * time.Second,
 		BackoffMultiplier: 2.0,
+		IdempotentOnly:    true,
 	}
 }
 
+// DefaultTimeout bounds every request that does not set a timeout of its own.
+const DefaultTimeout = 10 * time.Second
+
+// RequestOptions override the client settings for a single request. Zero
+// values keep the client settings.
+type RequestOptions struct {
+	// Timeout bounds one attempt of the request, DefaultTimeout when zero.
+	Timeout time.Duration
+	Retry   *RetryConfig
+	// KeepServerErrors returns 5xx responses instead of failing with an error.
+	KeepServerErrors bool
+	// Retarget, when set, is called before every retry with the error or the
+	// status of the failed attempt. It returns the URI of the next attempt, or
+	// false to stop retrying.
+	Retarget func(err error, statusCode int) (string, bool)
+}
+
+var idempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+const (
+	UpstreamOutcomeSuccess = "success"
+	UpstreamOutcomeFailure = "failure"
+	UpstreamOutcomeRetry   = "retry"
+)
+
+// UpstreamObserver is told the outcome of every request to an upstream,
+// given as scheme and host: one retry per repeated attempt, then a single
+// success or failure.
+type UpstreamObserver func(upstream, outcome string)
+
 type Client struct {
 	client      *fasthttp.Client
 	retryConfig RetryConfig
+	observe     UpstreamObserver
 }
 
 func NewHttpClient() *Client {
 	return &Client{
+		// Requests are bounded by their own timeout and retried by the
+		// retry config only, so the client sets neither.
 		client: &fasthttp.Client{
-			ReadTimeout:                   10 * time.Second,
-			WriteTimeout:                  10 * time.Second,
+			MaxIdemponentCallAttempts:     1,
 			MaxConnsPerHost:               1000,
 			MaxIdleConnDuration:           time.Minute,
 			MaxConnDuration:               time.Minute * 5,
@@ -56,11 +105,34 @@ func (httpClient *Client) SetRetryConfig(config RetryConfig) {
 	httpClient.retryConfig = config
 }
 
+func (httpClient *Client) SetUpstreamObserver(observer UpstreamObserver) {
+	httpClient.observe = observer
+}
+
+func (httpClient *Client) Do(
+	method string,
+	uri string,
+	requestHeader http.Header,
+	requestBody []byte,
+) (*Response, error) {
+	return httpClient.makeRequest(uri, method, requestHeader, requestBody, RequestOptions{})
+}
+
+func (httpClient *Client) DoWithOptions(
+	method string,
+	uri string,
+	requestHeader http.Header,
+	requestBody []byte,
+	options RequestOptions,
+) (*Response, error) {
+	return httpClient.makeRequest(uri, method, requestHeader, requestBody, options)
+}
+
 func (httpClient *Client) Get(
 	uri string,
 	requestHeader http.Header,
 ) (*Response, error) {
-	return httpClient.makeRequest(uri, http.MethodGet, requestHeader, nil)
+	return httpClient.makeRequest(uri, http.MethodGet, requestHeader, nil, RequestOptions{})
 }
 
 func (httpClient *Client) Post(
@@ -68,7 +140,7 @@ func (httpClient *Client) Post(
 	requestHeader http.Header,
 	requestBody []byte,
 ) (*Response, error) {
-	return httpClient.makeRequest(uri, http.MethodPost, requestHeader, requestBody)
+	return httpClient.makeRequest(uri, http.MethodPost, requestHeader, requestBody, RequestOptions{})
 }
 
 func (httpClient *Client) Put(
@@ -76,7 +148,7 @@ func (httpClient *Client) Put(
 	requestHeader http.Header,
 	requestBody []byte,
 ) (*Response, error) {
-	return httpClient.makeRequest(uri, http.MethodPut, requestHeader, requestBody)
+	return httpClient.makeRequest(uri, http.MethodPut, requestHeader, requestBody, RequestOptions{})
 }
 
 func (httpClient *Client) Patch(
@@ -84,7 +156,7 @@ func (httpClient *Client) Patch(
 	requestHeader http.Header,
 	requestBody []byte,
 ) (*Response, error) {
-	return httpClient.makeRequest(uri, http.MethodPatch, requestHeader, requestBody)
+	return httpClient.makeRequest(uri, http.MethodPatch, requestHeader, requestBody, RequestOptions{})
 }
 
 func (httpClient *Client) Delete(
@@ -92,7 +164,7 @@ func (httpClient *Client) Delete(
 	requestHeader http.Header,
 	requestBody []byte,
 ) (*Response, error) {
-	return httpClient.makeRequest(uri, http.MethodDelete, requestHeader, requestBody)
+	return httpClient.makeRequest(uri, http.MethodDelete, requestHeader, requestBody, RequestOptions{})
 }
 
 func isRetryableError(err error, statusCode int) bool {
@@ -102,11 +174,14 @@ func isRetryableError(err error, statusCode int) bool {
 	return statusCode >= http.StatusInternalServerError && statusCode < 600
 }
 
-func (httpClient *Client) calculateBackoff(attempt int) time.Duration {
-	backoff := time.Duration(float64(httpClient.retryConfig.InitialBackoff) *
-		pow(httpClient.retryConfig.BackoffMultiplier, float64(attempt)))
-	if backoff > httpClient.retryConfig.MaxBackoff {
-		backoff = httpClient.retryConfig.MaxBackoff
+func (retryConfig RetryConfig) backoff(attempt int) time.Duration {
+	backoff := time.Duration(float64(retryConfig.InitialBackoff) *
+		pow(retryConfig.BackoffMultiplier, float64(attempt)))
+	if backoff > retryConfig.MaxBackoff {
+		backoff = retryConfig.MaxBackoff
+	}
+	if retryConfig.Jitter > 0 {
+		backoff -= time.Duration(float64(backoff) * retryConfig.Jitter * rand.Float64())
 	}
 	return backoff
 }
@@ -129,9 +204,13 @@ func (httpClient *Client) buildRequest(
 	req.Header.SetMethod(method)
 	req.Header.SetContentType(fiber.MIMEApplicationJSON)
 
-	if len(requestHeader) > 0 {
-		for headerKey, headerValue := range requestHeader {
-			req.Header.Set(headerKey, strings.Join(headerValue, ""))
+	for headerKey, headerValues := range requestHeader {
+		for valueIndex, headerValue := range headerValues {
+			if valueIndex == 0 {
+				req.Header.Set(headerKey, headerValue)
+				continue
+			}
+			req.Header.Add(headerKey, headerValue)
 		}
 	}
 
@@ -162,20 +241,41 @@ func (httpClient *Client) handleResponse(resp *fasthttp.Response, req *fasthttp.
 		return nil, errors.New("response failed")
 	}
 
+	return httpClient.readResponse(resp, req), nil
+}
+
+func (httpClient *Client) readResponse(resp *fasthttp.Response, req *fasthttp.Request) *Response {
+	statusCode := resp.StatusCode()
 	body := make([]byte, len(resp.Body()))
 	copy(body, resp.Body())
 
+	headers := make(http.Header)
+	for headerKey, headerValue := range resp.Header.All() {
+		headers.Add(string(headerKey), string(headerValue))
+	}
+
 	fasthttp.ReleaseRequest(req)
 	fasthttp.ReleaseResponse(resp)
 
 	return &Response{
-		Status: statusCode,
-		Body:   body,
-	}, nil
+		Status:  statusCode,
+		Headers: headers,
+		Body:    body,
+	}
 }
 
-func (httpClient *Client) shouldRetry(err error, statusCode, attempt int) bool {
-	return isRetryableError(err, statusCode) && attempt < httpClient.retryConfig.MaxRetries
+func (retryConfig RetryConfig) shouldRetry(method string, err error, statusCode, attempt int) bool {
+	if attempt >= retryConfig.MaxRetries {
+		return false
+	}
+	if retryConfig.IdempotentOnly && !slices.Contains(idempotentMethods, method) {
+		return false
+	}
+	if err != nil || len(retryConfig.RetryOnStatusCodes) == 0 {
+		return isRetryableError(err, statusCode)
+	}
+
+	return slices.Contains(retryConfig.RetryOnStatusCodes, statusCode)
 }
 
 func (httpClient *Client) makeRequest(
@@ -183,38 +283,78 @@ func (httpClient *Client) makeRequest(
 	method string,
 	requestHeader http.Header,
 	requestBody []byte,
+	options RequestOptions,
 ) (*Response, error) {
 	var lastErr error
+	upstream := upstreamName(uri)
+	retryConfig := httpClient.retryConfig
+	if options.Retry != nil {
+		retryConfig = *options.Retry
+	}
 
-	for attempt := 0; attempt <= httpClient.retryConfig.MaxRetries; attempt++ {
+	var (
+		attemptErr        error
+		attemptStatusCode int
+	)
+	for attempt := 0; attempt <= retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
-			backoff := httpClient.calculateBackoff(attempt - 1)
-			time.Sleep(backoff)
+			if options.Retarget != nil {
+				nextUri, canRetry := options.Retarget(attemptErr, attemptStatusCode)
+				if !canRetry {
+					break
+				}
+				uri = nextUri
+				upstream = upstreamName(uri)
+			}
+			httpClient.observeUpstream(upstream, UpstreamOutcomeRetry)
+			time.Sleep(retryConfig.backoff(attempt - 1))
 		}
 
 		req := httpClient.buildRequest(uri, method, requestHeader, requestBody)
+		if options.Timeout > 0 {
+			req.SetTimeout(options.Timeout)
+		} else {
+			req.SetTimeout(DefaultTimeout)
+		}
 		resp, err := httpClient.executeRequest(req)
 
 		if err != nil {
 			lastErr = err
-			if httpClient.shouldRetry(err, 0, attempt) {
+			attemptErr, attemptStatusCode = err, 0
+			if retryConfig.shouldRetry(method, err, 0, attempt) {
 				continue
 			}
+			httpClient.observeUpstream(upstream, UpstreamOutcomeFailure)
 			return nil, err
 		}
 
+		if retryConfig.shouldRetry(method, nil, resp.StatusCode(), attempt) {
+			attemptErr, attemptStatusCode = nil, resp.StatusCode()
+			fasthttp.ReleaseRequest(req)
+			fasthttp.ReleaseResponse(resp)
+			continue
+		}
+
+		if options.KeepServerErrors {
+			outcome := UpstreamOutcomeSuccess
+			if resp.StatusCode() >= http.StatusInternalServerError {
+				outcome = UpstreamOutcomeFailure
+			}
+			httpClient.observeUpstream(upstream, outcome)
+			return httpClient.readResponse(resp, req), nil
+		}
+
 		response, err := httpClient.handleResponse(resp, req)
 		if err != nil {
-			lastErr = err
-			if httpClient.shouldRetry(nil, resp.StatusCode(), attempt) {
-				continue
-			}
-			return nil, lastErr
+			httpClient.observeUpstream(upstream, UpstreamOutcomeFailure)
+			return nil, err
 		}
 
+		httpClient.observeUpstream(upstream, UpstreamOutcomeSuccess)
 		return response, nil
 	}
 
+	httpClient.observeUpstream(upstream, UpstreamOutcomeFailure)
 	if lastErr != nil {
 		return nil, lastErr
 	}
@@ -222,6 +362,21 @@ func (httpClient *Client) makeRequest(
 	return nil, errors.New("request failed after all retries")
 }
 
+func (httpClient *Client) observeUpstream(upstream, outcome string) {
+	if httpClient.observe != nil {
+		httpClient.observe(upstream, outcome)
+	}
+}
+
+func upstreamName(uri string) string {
+	parsedUri, err := url.Parse(uri)
+	if err != nil || parsedUri.Host == "" {
+		return uri
+	}
+
+	return parsedUri.Scheme + "://" + parsedUri.Host
+}
+
 func GetFreePort() (int, error) {
 	addr, err := net.ResolveTCPAddr("tcp", "localhost:0")
 	if err != nil {
@@ -3,6 +3,8 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -53,10 +55,9 @@ func TestClient_Get(t *testing.T) {
 		assert.Equal(t, []string{
 			TestReqHeaderValue,
 		}, xTestKeyHeader)
-		assert.Equal(t, &Response{
-			Status: fiber.StatusOK,
-			Body:   TestReqBody,
-		}, response)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestReqBody, response.Body)
+		assert.NotEmpty(t, response.Headers.Get(fiber.HeaderContentType))
 	})
 
 	t.Run("when upstream returns 404", func(t *testing.T) {
@@ -110,10 +111,9 @@ func TestClient_Post(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, TestReqBody, requestBodyBytes)
-		assert.Equal(t, &Response{
-			Status: fiber.StatusOK,
-			Body:   TestRespBody,
-		}, response)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestRespBody, response.Body)
+		assert.NotEmpty(t, response.Headers.Get(fiber.HeaderContentType))
 	})
 
 	t.Run("when upstream returns 404", func(t *testing.T) {
@@ -167,10 +167,9 @@ func TestClient_Put(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, TestReqBody, requestBodyBytes)
-		assert.Equal(t, &Response{
-			Status: fiber.StatusOK,
-			Body:   TestRespBody,
-		}, response)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestRespBody, response.Body)
+		assert.NotEmpty(t, response.Headers.Get(fiber.HeaderContentType))
 	})
 
 	t.Run("when upstream returns 404", func(t *testing.T) {
@@ -224,10 +223,9 @@ func TestClient_Delete(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, TestReqBody, requestBodyBytes)
-		assert.Equal(t, &Response{
-			Status: fiber.StatusOK,
-			Body:   TestRespBody,
-		}, response)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestRespBody, response.Body)
+		assert.NotEmpty(t, response.Headers.Get(fiber.HeaderContentType))
 	})
 
 	t.Run("when upstream returns 404", func(t *testing.T) {
@@ -281,10 +279,9 @@ func TestClient_Patch(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, TestReqBody, requestBodyBytes)
-		assert.Equal(t, &Response{
-			Status: fiber.StatusOK,
-			Body:   TestRespBody,
-		}, response)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestRespBody, response.Body)
+		assert.NotEmpty(t, response.Headers.Get(fiber.HeaderContentType))
 	})
 
 	t.Run("when upstream returns 404", func(t *testing.T) {
@@ -311,6 +308,66 @@ func TestClient_Patch(t *testing.T) {
 	})
 }
 
+func TestClient_Do(t *testing.T) {
+	t.Run("happy path - sends custom method", func(t *testing.T) {
+		freePort, err := GetFreePort()
+		require.NoError(t, err)
+
+		httpClient := NewHttpClient()
+		mockServer := fiber.New(fiber.Config{
+			DisableStartupMessage: true,
+			RequestMethods:        append([]string{"PURGE"}, fiber.DefaultMethods...),
+		})
+
+		var receivedMethod string
+		mockServer.Add("PURGE", TestReqPath, func(ctx *fiber.Ctx) error {
+			receivedMethod = ctx.Method()
+			return ctx.Status(fiber.StatusOK).Send(TestRespBody)
+		})
+
+		go mockServer.Listen(fmt.Sprintf(":%d", freePort))
+		defer mockServer.Shutdown()
+		time.Sleep(1 * time.Second)
+
+		url := fmt.Sprintf("%s:%d%s", TestReqUri, freePort, TestReqPath)
+		response, err := httpClient.Do("PURGE", url, http.Header{
+			TestReqHeaderKey: {TestReqHeaderValue},
+		}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PURGE", receivedMethod)
+		assert.Equal(t, fiber.StatusOK, response.Status)
+		assert.Equal(t, TestRespBody, response.Body)
+	})
+
+	t.Run("happy path - HEAD returns headers without body", func(t *testing.T) {
+		freePort, err := GetFreePort()
+		require.NoError(t, err)
+
+		httpClient := NewHttpClient()
+		mockServer := fiber.New(fiber.Config{
+			DisableStartupMessage: true,
+		})
+
+		mockServer.Head(TestReqPath, func(ctx *fiber.Ctx) error {
+			ctx.Set("X-Health", "ok")
+			return ctx.SendStatus(fiber.StatusNoContent)
+		})
+
+		go mockServer.Listen(fmt.Sprintf(":%d", freePort))
+		defer mockServer.Shutdown()
+		time.Sleep(1 * time.Second)
+
+		url := fmt.Sprintf("%s:%d%s", TestReqUri, freePort, TestReqPath)
+		response, err := httpClient.Do(http.MethodHead, url, nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, response.Status)
+		assert.Equal(t, "ok", response.Headers.Get("X-Health"))
+		assert.Empty(t, response.Body)
+	})
+}
+
 func TestClient_SetRetryConfig(t *testing.T) {
 	t.Run("happy path - sets retry config", func(t *testing.T) {
 		httpClient := NewHttpClient()
@@ -330,6 +387,53 @@ func TestClient_SetRetryConfig(t *testing.T) {
 	})
 }
 
+func TestClient_SetUpstreamObserver(t *testing.T) {
+	newObservedClient := func() (*Client, *[]string) {
+		var outcomes []string
+		httpClient := NewHttpClient()
+		httpClient.SetRetryConfig(RetryConfig{
+			MaxRetries:        2,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			BackoffMultiplier: 1,
+		})
+		httpClient.SetUpstreamObserver(func(upstream, outcome string) {
+			outcomes = append(outcomes, upstream+" "+outcome)
+		})
+
+		return httpClient, &outcomes
+	}
+
+	t.Run("happy path - reports a success", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer upstream.Close()
+		httpClient, outcomes := newObservedClient()
+
+		_, err := httpClient.Get(upstream.URL+TestReqPath+"?page=1", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{upstream.URL + " " + UpstreamOutcomeSuccess}, *outcomes)
+	})
+
+	t.Run("error path - reports every retry and the failure", func(t *testing.T) {
+		freePort, err := GetFreePort()
+		require.NoError(t, err)
+		httpClient, outcomes := newObservedClient()
+		upstream := fmt.Sprintf("%s:%d", TestReqUri, freePort)
+
+		_, err = httpClient.Get(upstream+TestReqPath, nil)
+
+		require.Error(t, err)
+		assert.Equal(t, []string{
+			upstream + " " + UpstreamOutcomeRetry,
+			upstream + " " + UpstreamOutcomeRetry,
+			upstream + " " + UpstreamOutcomeFailure,
+		}, *outcomes)
+	})
+}
+
 func TestDefaultRetryConfig(t *testing.T) {
 	t.Run("happy path - returns default config", func(t *testing.T) {
 		config := DefaultRetryConfig()
@@ -341,38 +445,44 @@ func TestDefaultRetryConfig(t *testing.T) {
 	})
 }
 
-func TestClient_calculateBackoff(t *testing.T) {
+func TestRetryConfig_backoff(t *testing.T) {
 	t.Run("happy path - calculates backoff correctly", func(t *testing.T) {
-		httpClient := NewHttpClient()
-		httpClient.SetRetryConfig(RetryConfig{
+		retryConfig := RetryConfig{
 			MaxRetries:        3,
 			InitialBackoff:    100 * time.Millisecond,
 			MaxBackoff:        2 * time.Second,
 			BackoffMultiplier: 2.0,
-		})
-
-		backoff1 := httpClient.calculateBackoff(0)
-		assert.Equal(t, 100*time.Millisecond, backoff1)
-
-		backoff2 := httpClient.calculateBackoff(1)
-		assert.Equal(t, 200*time.Millisecond, backoff2)
+		}
 
-		backoff3 := httpClient.calculateBackoff(2)
-		assert.Equal(t, 400*time.Millisecond, backoff3)
+		assert.Equal(t, 100*time.Millisecond, retryConfig.backoff(0))
+		assert.Equal(t, 200*time.Millisecond, retryConfig.backoff(1))
+		assert.Equal(t, 400*time.Millisecond, retryConfig.backoff(2))
 	})
 
 	t.Run("happy path - backoff capped at MaxBackoff", func(t *testing.T) {
-		httpClient := NewHttpClient()
-		httpClient.SetRetryConfig(RetryConfig{
+		retryConfig := RetryConfig{
 			MaxRetries:        3,
 			InitialBackoff:    100 * time.Millisecond,
 			MaxBackoff:        500 * time.Millisecond,
 			BackoffMultiplier: 10.0,
-		})
+		}
 
-		backoff := httpClient.calculateBackoff(2)
+		assert.Equal(t, 500*time.Millisecond, retryConfig.backoff(2))
+	})
+
+	t.Run("happy path - jitter shortens backoff within its fraction", func(t *testing.T) {
+		retryConfig := RetryConfig{
+			InitialBackoff:    100 * time.Millisecond,
+			MaxBackoff:        time.Second,
+			BackoffMultiplier: 2.0,
+			Jitter:            0.5,
+		}
 
-		assert.Equal(t, 500*time.Millisecond, backoff)
+		for range 50 {
+			backoff := retryConfig.backoff(1)
+			assert.GreaterOrEqual(t, backoff, 100*time.Millisecond)
+			assert.LessOrEqual(t, backoff, 200*time.Millisecond)
+		}
 	})
 }
 
@@ -430,35 +540,219 @@ func TestIsRetryableError(t *testing.T) {
 	})
 }
 
-func TestClient_shouldRetry(t *testing.T) {
+func TestRetryConfig_shouldRetry(t *testing.T) {
 	t.Run("happy path - should retry when error and attempt < max", func(t *testing.T) {
-		httpClient := NewHttpClient()
-		httpClient.SetRetryConfig(RetryConfig{
-			MaxRetries: 3,
-		})
+		retryConfig := RetryConfig{MaxRetries: 3}
 
-		assert.True(t, httpClient.shouldRetry(assert.AnError, 0, 0))
-		assert.True(t, httpClient.shouldRetry(assert.AnError, 0, 1))
-		assert.True(t, httpClient.shouldRetry(assert.AnError, 0, 2))
-		assert.False(t, httpClient.shouldRetry(assert.AnError, 0, 3))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 1))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 2))
+		assert.False(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 3))
 	})
 
 	t.Run("happy path - should retry for 5xx status codes", func(t *testing.T) {
-		httpClient := NewHttpClient()
-		httpClient.SetRetryConfig(RetryConfig{
-			MaxRetries: 3,
-		})
+		retryConfig := RetryConfig{MaxRetries: 3}
 
-		assert.True(t, httpClient.shouldRetry(nil, http.StatusInternalServerError, 0))
-		assert.True(t, httpClient.shouldRetry(nil, http.StatusBadGateway, 1))
-		assert.False(t, httpClient.shouldRetry(nil, http.StatusInternalServerError, 3))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusInternalServerError, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusBadGateway, 1))
+		assert.False(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusInternalServerError, 3))
 	})
 
 	t.Run("happy path - should not retry for 2xx status codes", func(t *testing.T) {
-		httpClient := NewHttpClient()
+		retryConfig := DefaultRetryConfig()
+
+		assert.False(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusOK, 0))
+		assert.False(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusCreated, 1))
+	})
+
+	t.Run("happy path - retries only configured status codes", func(t *testing.T) {
+		retryConfig := RetryConfig{
+			MaxRetries:         3,
+			RetryOnStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+		}
+
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusTooManyRequests, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusServiceUnavailable, 0))
+		assert.False(t, retryConfig.shouldRetry(http.MethodGet, nil, http.StatusInternalServerError, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 0))
+	})
+
+	t.Run("happy path - idempotent only skips non-idempotent methods", func(t *testing.T) {
+		retryConfig := RetryConfig{MaxRetries: 3, IdempotentOnly: true}
+
+		assert.True(t, retryConfig.shouldRetry(http.MethodGet, assert.AnError, 0, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodPut, assert.AnError, 0, 0))
+		assert.True(t, retryConfig.shouldRetry(http.MethodDelete, nil, http.StatusBadGateway, 0))
+		assert.False(t, retryConfig.shouldRetry(http.MethodPost, assert.AnError, 0, 0))
+		assert.False(t, retryConfig.shouldRetry(http.MethodPatch, nil, http.StatusBadGateway, 0))
+	})
+}
+
+func TestClient_DoWithOptions(t *testing.T) {
+	noBackoff := func(maxRetries int) *RetryConfig {
+		return &RetryConfig{MaxRetries: maxRetries, BackoffMultiplier: 1.0, IdempotentOnly: true}
+	}
+
+	t.Run("happy path - retries a 5xx response until it succeeds", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		response, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{Retry: noBackoff(3)})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Status)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("happy path - does not retry non-idempotent methods", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		_, err := NewHttpClient().DoWithOptions(http.MethodPost, server.URL, nil, nil, RequestOptions{Retry: noBackoff(3)})
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("happy path - returns the last retry-on response once attempts run out", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		retryConfig := noBackoff(2)
+		retryConfig.RetryOnStatusCodes = []int{http.StatusTooManyRequests}
+
+		response, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{Retry: retryConfig})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, response.Status)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("happy path - keeps a 5xx response when asked to", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("down"))
+		}))
+		defer server.Close()
+
+		response, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{
+			Retry:            noBackoff(0),
+			KeepServerErrors: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, response.Status)
+		assert.Equal(t, []byte("down"), response.Body)
+	})
+
+	t.Run("error path - times out a slow upstream", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		_, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{
+			Timeout: 50 * time.Millisecond,
+			Retry:   noBackoff(0),
+		})
+
+		assert.ErrorIs(t, err, fasthttp.ErrTimeout)
+		assert.Less(t, time.Since(start), 200*time.Millisecond)
+	})
+
+	t.Run("happy path - waits past the default timeout when the request timeout allows it", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(DefaultTimeout + time.Second)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		response, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{
+			Timeout: 2 * DefaultTimeout,
+			Retry:   noBackoff(0),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.Status)
+	})
+
+	t.Run("error path - sends a failed request once without retries", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+		}))
+		defer server.Close()
+
+		_, err := NewHttpClient().DoWithOptions(http.MethodGet, server.URL, nil, nil, RequestOptions{
+			Retry: noBackoff(0),
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("happy path - retries at the uri Retarget returns", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failing.Close()
+		healthy := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			_, _ = writer.Write([]byte("healthy"))
+		}))
+		defer healthy.Close()
+
+		var failedStatusCode int
+		response, err := NewHttpClient().DoWithOptions(http.MethodGet, failing.URL, nil, nil, RequestOptions{
+			Retry: noBackoff(1),
+			Retarget: func(err error, statusCode int) (string, bool) {
+				failedStatusCode = statusCode
+				return healthy.URL, true
+			},
+		})
 
-		assert.False(t, httpClient.shouldRetry(nil, http.StatusOK, 0))
-		assert.False(t, httpClient.shouldRetry(nil, http.StatusCreated, 1))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, failedStatusCode)
+		assert.Equal(t, "healthy", string(response.Body))
+	})
+
+	t.Run("error path - stops retrying when Retarget says so", func(t *testing.T) {
+		var attempts atomic.Int32
+		failing := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			attempts.Add(1)
+			writer.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failing.Close()
+
+		_, err := NewHttpClient().DoWithOptions(http.MethodGet, failing.URL, nil, nil, RequestOptions{
+			Retry: noBackoff(1),
+			Retarget: func(error, int) (string, bool) {
+				return "", false
+			},
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
 	})
 }
 
@@ -515,6 +809,21 @@ func TestClient_buildRequest(t *testing.T) {
 		assert.Empty(t, req.Body())
 	})
 
+	t.Run("happy path - keeps every value of multi-value headers", func(t *testing.T) {
+		httpClient := NewHttpClient()
+		headers := http.Header{
+			"Accept": {"application/json", "text/plain"},
+		}
+
+		req := httpClient.buildRequest("http://localhost:8080/test", http.MethodGet, headers, nil)
+
+		var acceptValues []string
+		for _, value := range req.Header.PeekAll("Accept") {
+			acceptValues = append(acceptValues, string(value))
+		}
+		assert.Equal(t, []string{"application/json", "text/plain"}, acceptValues)
+	})
+
 	t.Run("happy path - builds request with empty headers", func(t *testing.T) {
 		httpClient := NewHttpClient()
 		uri := "http://localhost:8080/test"
@@ -653,7 +962,7 @@ func TestClient_makeRequest(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		uri := fmt.Sprintf("%s:%d%s", TestReqUri, freePort, "/test")
-		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil)
+		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil, RequestOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, response)
@@ -685,7 +994,7 @@ func TestClient_makeRequest(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		uri := fmt.Sprintf("%s:%d%s", TestReqUri, freePort, "/test")
-		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil)
+		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil, RequestOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -701,7 +1010,7 @@ func TestClient_makeRequest(t *testing.T) {
 		})
 
 		uri := "http://localhost:99999/nonexistent"
-		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil)
+		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil, RequestOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
@@ -732,7 +1041,7 @@ func TestClient_makeRequest(t *testing.T) {
 		time.Sleep(1 * time.Second)
 
 		uri := fmt.Sprintf("%s:%d%s", TestReqUri, freePort, "/test")
-		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil)
+		response, err := httpClient.makeRequest(uri, http.MethodGet, http.Header{}, nil, RequestOptions{})
 
 		assert.Error(t, err)
 		assert.Nil(t, response)
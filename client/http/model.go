@@ -1,6 +1,9 @@
 package http
 
+import "net/http"
+
 type Response struct {
-	Status int
-	Body   []byte
+	Status  int
+	Headers http.Header
+	Body    []byte
 }
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/goccy/go-json"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/lynicis/inzibat/handler"
+	_ "github.com/lynicis/inzibat/log"
+)
+
+const defaultBreakerAddr = "localhost:8080"
+
+var breakerAddr string
+
+var breakerCmd = &cobra.Command{
+	Use:     "breaker",
+	Aliases: []string{"cb"},
+	Short:   "Inspect and control circuit breakers of a running inzibat server",
+	Long: `Inspect and control circuit breakers of a running inzibat server.
+
+Breakers are named by their route key, as shown by "inzibat breaker list".
+Quote route keys, since they contain spaces:
+
+  inzibat breaker open "GET /users -> GET http://users:8080/users"`,
+}
+
+var breakerListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls", "l"},
+	Short:   "List circuit breakers",
+	Long: `List the circuit breakers of the running inzibat server with their
+state and counters.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := fetchCircuitBreakers(breakerAddr)
+		if err != nil {
+			zap.L().Fatal("failed to fetch circuit breakers", zap.Error(err))
+		}
+
+		if len(records) == 0 {
+			fmt.Println("No circuit breakers.")
+			return
+		}
+
+		rows := make([][]string, 0, len(records))
+		for _, record := range records {
+			openedAt := "-"
+			if !record.OpenedAt.IsZero() {
+				openedAt = record.OpenedAt.Format(time.RFC3339)
+			}
+
+			rows = append(rows, []string{
+				record.RouteKey,
+				string(record.State),
+				fmt.Sprintf("%d", record.RequestCount),
+				fmt.Sprintf("%d", record.ConsecutiveFailures),
+				fmt.Sprintf("%d", record.ConsecutiveSuccesses),
+				openedAt,
+			})
+		}
+
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			Headers("ROUTE KEY", "STATE", "REQUESTS", "FAILURES", "SUCCESSES", "OPENED AT").
+			Rows(rows...)
+
+		fmt.Println(t)
+	},
+}
+
+var breakerResetCmd = &cobra.Command{
+	Use:   "reset [route key]",
+	Short: "Close circuit breakers and clear their counters",
+	Long: `Close the circuit breaker of the given route key and clear its counters.
+Without a route key, every circuit breaker is reset.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		request := map[string]string{}
+		if len(args) == 1 {
+			request["routeKey"] = args[0]
+		}
+
+		if err := updateCircuitBreaker(breakerAddr, http.MethodPost, "/_inzibat/circuit-breakers/reset", request); err != nil {
+			zap.L().Fatal("failed to reset circuit breakers", zap.Error(err))
+		}
+
+		if len(args) == 0 {
+			fmt.Println("All circuit breakers reset.")
+			return
+		}
+		fmt.Printf("Circuit breaker %q reset.\n", args[0])
+	},
+}
+
+func newBreakerStateCmd(use string, state handler.CircuitBreakerState, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <route key>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			request := map[string]string{
+				"routeKey": args[0],
+				"state":    string(state),
+			}
+
+			if err := updateCircuitBreaker(breakerAddr, http.MethodPut, "/_inzibat/circuit-breakers/state", request); err != nil {
+				zap.L().Fatal("failed to set circuit breaker state", zap.Error(err))
+			}
+
+			fmt.Printf("Circuit breaker %q is %s.\n", args[0], state)
+		},
+	}
+}
+
+func fetchCircuitBreakers(addr string) ([]handler.CircuitBreakerRecord, error) {
+	url := fmt.Sprintf("http://%s/_inzibat/circuit-breakers", addr)
+
+	resp, err := http.Get(url) //nolint:noctx
+	if err != nil {
+		return nil, fmt.Errorf(
+			"could not connect to inzibat server at %s: %w",
+			addr,
+			err,
+		)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var records []handler.CircuitBreakerRecord
+	if err = json.Unmarshal(body, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return records, nil
+}
+
+func updateCircuitBreaker(addr, method, path string, request map[string]string) error {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpRequest, err := http.NewRequest(method, fmt.Sprintf("http://%s%s", addr, path), bytes.NewReader(requestBody)) //nolint:noctx
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf(
+			"could not connect to inzibat server at %s: %w",
+			addr,
+			err,
+		)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var response struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = json.Unmarshal(body, &response)
+
+		return fmt.Errorf("unexpected response from server (status %d): %s", resp.StatusCode, response.Message)
+	}
+
+	return nil
+}
+
+func init() {
+	breakerCmd.PersistentFlags().StringVarP(
+		&breakerAddr,
+		"addr",
+		"a",
+		defaultBreakerAddr,
+		"Address of the running inzibat server",
+	)
+
+	breakerCmd.AddCommand(breakerListCmd)
+	breakerCmd.AddCommand(newBreakerStateCmd("open", handler.CircuitBreakerStateOpen, "Force a circuit breaker open"))
+	breakerCmd.AddCommand(newBreakerStateCmd("close", handler.CircuitBreakerStateClosed, "Force a circuit breaker closed"))
+	breakerCmd.AddCommand(newBreakerStateCmd("half-open", handler.CircuitBreakerStateHalfOpen, "Force a circuit breaker half-open"))
+	breakerCmd.AddCommand(breakerResetCmd)
+	rootCmd.AddCommand(breakerCmd)
+}
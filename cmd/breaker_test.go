@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+)
+
+func serveCircuitBreakers(t *testing.T) (string, *handler.CircuitBreakerStore) {
+	t.Helper()
+
+	store, err := handler.NewCircuitBreakerStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Seed("GET /proxy", config.CircuitBreakerConfig{OpenTimeoutMs: 60000}))
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	handler.RegisterCircuitBreakerAdminRoutes(app, store)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() {
+		_ = app.Listener(listener)
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+
+	return listener.Addr().String(), store
+}
+
+func TestBreakerCmd(t *testing.T) {
+	t.Run("happy path - command is registered", func(t *testing.T) {
+		subcommands := make([]string, 0, len(breakerCmd.Commands()))
+		for _, subcommand := range breakerCmd.Commands() {
+			subcommands = append(subcommands, subcommand.Name())
+		}
+
+		assert.Contains(t, breakerCmd.Aliases, "cb")
+		assert.ElementsMatch(t, []string{"list", "open", "close", "half-open", "reset"}, subcommands)
+	})
+}
+
+func TestFetchCircuitBreakers(t *testing.T) {
+	t.Run("happy path - lists breakers", func(t *testing.T) {
+		addr, _ := serveCircuitBreakers(t)
+
+		records, err := fetchCircuitBreakers(addr)
+
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "GET /proxy", records[0].RouteKey)
+		assert.Equal(t, handler.CircuitBreakerStateClosed, records[0].State)
+	})
+
+	t.Run("error path - server is not running", func(t *testing.T) {
+		_, err := fetchCircuitBreakers("127.0.0.1:1")
+
+		assert.ErrorContains(t, err, "could not connect to inzibat server")
+	})
+}
+
+func TestUpdateCircuitBreaker(t *testing.T) {
+	t.Run("happy path - forces a breaker open and resets it", func(t *testing.T) {
+		addr, store := serveCircuitBreakers(t)
+
+		err := updateCircuitBreaker(addr, http.MethodPut, "/_inzibat/circuit-breakers/state",
+			map[string]string{"routeKey": "GET /proxy", "state": "open"})
+		require.NoError(t, err)
+		state, err := store.State("GET /proxy")
+		require.NoError(t, err)
+		assert.Equal(t, handler.CircuitBreakerStateOpen, state)
+
+		err = updateCircuitBreaker(addr, http.MethodPost, "/_inzibat/circuit-breakers/reset", map[string]string{})
+		require.NoError(t, err)
+		state, err = store.State("GET /proxy")
+		require.NoError(t, err)
+		assert.Equal(t, handler.CircuitBreakerStateClosed, state)
+	})
+
+	t.Run("error path - unknown breaker", func(t *testing.T) {
+		addr, _ := serveCircuitBreakers(t)
+
+		err := updateCircuitBreaker(addr, http.MethodPost, "/_inzibat/circuit-breakers/reset",
+			map[string]string{"routeKey": "GET /missing"})
+
+		assert.ErrorContains(t, err, "status 404")
+		assert.ErrorContains(t, err, "circuit breaker record not found")
+	})
+}
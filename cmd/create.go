@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
@@ -23,6 +24,11 @@ var (
 		{Key: "PUT", Value: "PUT"},
 		{Key: "PATCH", Value: "PATCH"},
 		{Key: "DELETE", Value: "DELETE"},
+		{Key: "HEAD", Value: "HEAD"},
+		{Key: "OPTIONS", Value: "OPTIONS"},
+		{Key: "TRACE", Value: "TRACE"},
+		{Key: "ANY", Value: config.MethodAny},
+		{Key: "Custom", Value: MethodCustom},
 	}
 	routeTypes = []huh.Option[string]{
 		{Key: "Mock", Value: "mock"},
@@ -38,6 +44,7 @@ var (
 )
 
 func createRouteForm() *huh.Form {
+	var method string
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -48,23 +55,46 @@ func createRouteForm() *huh.Form {
 			huh.NewSelect[string]().
 				Key("method").
 				Title("HTTP Method").
-				Options(httpMethods...),
+				Options(httpMethods...).
+				Value(&method),
 			huh.NewSelect[string]().
 				Key("routeType").
 				Title("Route Type").
 				Options(routeTypes...),
 		),
+		createCustomMethodGroup(&method),
 	).
 		WithInput(os.Stdin).
 		WithOutput(os.Stdout).
 		WithProgramOptions(tea.WithInput(os.Stdin), tea.WithOutput(os.Stdout))
 }
 
+func createCustomMethodGroup(method *string) *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Key("customMethod").
+			Title("Custom HTTP Method").
+			Placeholder("PURGE").
+			Validate(form_builder.ValidateHttpMethod),
+	).WithHideFunc(func() bool {
+		return *method != MethodCustom
+	})
+}
+
+func resolveMethod(formRunner form_builder.FormRunner) string {
+	method := formRunner.GetString("method")
+	if method == MethodCustom {
+		return strings.ToUpper(strings.TrimSpace(formRunner.GetString("customMethod")))
+	}
+
+	return method
+}
+
 func createMockResponseFormInternal(
 	statusFormRunner form_builder.FormRunner,
 	headersCollector func() (http.Header, error),
 	bodyTypeFormRunner form_builder.FormRunner,
-	bodyCollector func() (config.HttpBody, error),
+	bodyCollector func() (any, error),
 	bodyStringCollector func() (string, error),
 ) (*config.FakeResponse, error) {
 	if err := statusFormRunner.Run(); err != nil {
@@ -133,7 +163,7 @@ func createMockResponseForm() (*config.FakeResponse, error) {
 				Key("bodyType").
 				Title("Body Type").
 				Options([]huh.Option[string]{
-					{Key: "Body (JSON object)", Value: BodyTypeBody},
+					{Key: "Body (JSON value)", Value: BodyTypeBody},
 					{Key: "BodyString (string)", Value: BodyTypeBodyString},
 					{Key: "Skip", Value: form_builder.SourceSkip},
 				}...),
@@ -150,7 +180,7 @@ func createMockResponseForm() (*config.FakeResponse, error) {
 		statusFormRunner,
 		form_builder.CollectHeaders,
 		bodyTypeFormRunner,
-		form_builder.CollectBody,
+		form_builder.CollectResponseBody,
 		form_builder.CollectBodyString,
 	)
 }
@@ -168,7 +198,7 @@ func createClientRequestFormInternal(
 
 	host := basicFormRunner.GetString("host")
 	targetPath := basicFormRunner.GetString("path")
-	targetMethod := basicFormRunner.GetString("method")
+	targetMethod := resolveMethod(basicFormRunner)
 
 	headers, err := headersCollector()
 	if err != nil {
@@ -297,6 +327,7 @@ func parsePositiveInt(value string, key string) (int, error) {
 }
 
 func createClientRequestForm() (*config.RequestTo, error) {
+	var targetMethod string
 	basicForm := huh.NewForm(
 		huh.NewGroup(
 			huh.NewInput().
@@ -312,8 +343,10 @@ func createClientRequestForm() (*config.RequestTo, error) {
 			huh.NewSelect[string]().
 				Key("method").
 				Title("Target HTTP Method").
-				Options(httpMethods...),
+				Options(httpMethods...).
+				Value(&targetMethod),
 		),
+		createCustomMethodGroup(&targetMethod),
 	).
 		WithInput(os.Stdin).
 		WithOutput(os.Stdout).
@@ -402,7 +435,7 @@ func createRouteInternal(
 	}
 
 	path := routeFormRunner.GetString("path")
-	method := routeFormRunner.GetString("method")
+	method := resolveMethod(routeFormRunner)
 	routeType := routeFormRunner.GetString("routeType")
 
 	var fakeResponse *config.FakeResponse
@@ -183,7 +183,7 @@ func TestCreateCmd(t *testing.T) {
 
 func TestHttpMethods(t *testing.T) {
 	t.Run("happy path - httpMethods contains all methods", func(t *testing.T) {
-		assert.Equal(t, 5, len(httpMethods))
+		assert.Equal(t, 10, len(httpMethods))
 		methodValues := make(map[string]bool)
 		for _, opt := range httpMethods {
 			methodValues[opt.Value] = true
@@ -193,6 +193,11 @@ func TestHttpMethods(t *testing.T) {
 		assert.True(t, methodValues["PUT"])
 		assert.True(t, methodValues["PATCH"])
 		assert.True(t, methodValues["DELETE"])
+		assert.True(t, methodValues["HEAD"])
+		assert.True(t, methodValues["OPTIONS"])
+		assert.True(t, methodValues["TRACE"])
+		assert.True(t, methodValues[config.MethodAny])
+		assert.True(t, methodValues[MethodCustom])
 	})
 }
 
@@ -240,7 +245,7 @@ func TestCreateMockResponseForm_Structure(t *testing.T) {
 					Key("bodyType").
 					Title("Body Type").
 					Options([]huh.Option[string]{
-						{Key: "Body (JSON object)", Value: BodyTypeBody},
+						{Key: "Body (JSON value)", Value: BodyTypeBody},
 						{Key: "BodyString (string)", Value: BodyTypeBodyString},
 						{Key: "Skip", Value: form_builder.SourceSkip},
 					}...),
@@ -745,7 +750,7 @@ func TestCreateMockResponseForm_BodyTypes(t *testing.T) {
 		assert.Equal(t, statusCode, fakeResponse.StatusCode)
 		assert.NotNil(t, fakeResponse.Headers)
 		assert.NotNil(t, fakeResponse.Body)
-		assert.Equal(t, "success", fakeResponse.Body["message"])
+		assert.Equal(t, "success", fakeResponse.Body.(config.HttpBody)["message"])
 		assert.Empty(t, fakeResponse.BodyString)
 	})
 
@@ -1013,6 +1018,9 @@ func TestHttpMethods_Completeness(t *testing.T) {
 
 	t.Run("happy path - method keys match values", func(t *testing.T) {
 		for _, opt := range httpMethods {
+			if opt.Value == MethodCustom {
+				continue
+			}
 			assert.Equal(t, opt.Key, opt.Value, "method key should match value for %s", opt.Value)
 		}
 	})
@@ -1131,8 +1139,7 @@ func TestCreateMockResponseForm_CompleteStructure(t *testing.T) {
 		assert.Equal(t, statusCode, fakeResponse.StatusCode)
 		assert.Equal(t, 1, len(fakeResponse.Headers))
 		assert.NotNil(t, fakeResponse.Body)
-		assert.Equal(t, float64(123), fakeResponse.Body["id"])
-		assert.Equal(t, "created", fakeResponse.Body["name"])
+		assert.Equal(t, body, fakeResponse.Body)
 	})
 
 	t.Run("happy path - fake response with bodyString only", func(t *testing.T) {
@@ -1202,7 +1209,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return nil, nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1223,7 +1230,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return nil, nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1245,7 +1252,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return nil, expectedError },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1268,7 +1275,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return make(http.Header), nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1294,7 +1301,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return headers, nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return body, nil },
+			func() (any, error) { return body, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1303,10 +1310,33 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 		assert.Equal(t, 200, result.StatusCode)
 		assert.Equal(t, "application/json", result.Headers.Get("Content-Type"))
 		assert.NotNil(t, result.Body)
-		assert.Equal(t, "success", result.Body["message"])
+		assert.Equal(t, config.HttpBody{"message": "success"}, result.Body)
 		assert.Empty(t, result.BodyString)
 	})
 
+	t.Run("happy path - body type BodyTypeBody with a JSON array", func(t *testing.T) {
+
+		mockStatusForm := form_builder.NewMockFormRunner(ctrl)
+		mockBodyTypeForm := form_builder.NewMockFormRunner(ctrl)
+		body := []any{map[string]any{"id": float64(1)}}
+
+		mockStatusForm.EXPECT().Run().Return(nil)
+		mockStatusForm.EXPECT().GetString("statusCode").Return("200")
+		mockBodyTypeForm.EXPECT().Run().Return(nil)
+		mockBodyTypeForm.EXPECT().GetString("bodyType").Return(BodyTypeBody)
+
+		result, err := createMockResponseFormInternal(
+			mockStatusForm,
+			func() (http.Header, error) { return nil, nil },
+			mockBodyTypeForm,
+			func() (any, error) { return body, nil },
+			func() (string, error) { return "", nil },
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, body, result.Body)
+	})
+
 	t.Run("happy path - body type BodyTypeBodyString", func(t *testing.T) {
 
 		mockStatusForm := form_builder.NewMockFormRunner(ctrl)
@@ -1323,7 +1353,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return headers, nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return bodyString, nil },
 		)
 
@@ -1349,7 +1379,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return headers, nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1375,7 +1405,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return make(http.Header), nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, expectedError },
+			func() (any, error) { return nil, expectedError },
 			func() (string, error) { return "", nil },
 		)
 
@@ -1399,7 +1429,7 @@ func TestCreateMockResponseFormInternal(t *testing.T) {
 			mockStatusForm,
 			func() (http.Header, error) { return make(http.Header), nil },
 			mockBodyTypeForm,
-			func() (config.HttpBody, error) { return nil, nil },
+			func() (any, error) { return nil, nil },
 			func() (string, error) { return "", expectedError },
 		)
 
@@ -1896,6 +1926,27 @@ func TestCreateRouteInternal(t *testing.T) {
 		assert.Equal(t, 200, result.FakeResponse.StatusCode)
 	})
 
+	t.Run("happy path - custom method", func(t *testing.T) {
+
+		mockRouteForm := form_builder.NewMockFormRunner(ctrl)
+		fakeResponse := &config.FakeResponse{StatusCode: 202, BodyString: "purged"}
+
+		mockRouteForm.EXPECT().Run().Return(nil)
+		mockRouteForm.EXPECT().GetString("path").Return("/cache")
+		mockRouteForm.EXPECT().GetString("method").Return(MethodCustom)
+		mockRouteForm.EXPECT().GetString("customMethod").Return(" purge ")
+		mockRouteForm.EXPECT().GetString("routeType").Return(RouteTypeMock)
+
+		result, err := createRouteInternal(
+			mockRouteForm,
+			func() (*config.FakeResponse, error) { return fakeResponse, nil },
+			func() (*config.RequestTo, error) { return nil, nil },
+		)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "PURGE", result.Method)
+	})
+
 	t.Run("happy path - client route", func(t *testing.T) {
 
 		mockRouteForm := form_builder.NewMockFormRunner(ctrl)
@@ -82,6 +82,49 @@ func CollectBody() (config.HttpBody, error) {
 	return collectBodyInternal(sourceFormRunner, filePathFormRunner)
 }
 
+func collectResponseBodyInternal(
+	sourceFormRunner,
+	filePathFormRunner FormRunner,
+) (any, error) {
+	if err := sourceFormRunner.Run(); err != nil {
+		return nil, err
+	}
+	source := sourceFormRunner.GetString(SourceKey)
+
+	if source == SourceSkip {
+		return nil, nil
+	}
+
+	if source == SourceFile {
+		if err := filePathFormRunner.Run(); err != nil {
+			return nil, err
+		}
+		filePath := filePathFormRunner.GetString("filepath")
+		return config.LoadResponseBodyFromFile(filePath)
+	}
+
+	body, err := CollectBodyFromForm()
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func CollectResponseBody() (any, error) {
+	sourceForm := BuildSourceSelectionForm("Body Source", SourceKey)
+	filePathForm := BuildFilePathForm(FilePathFormConfig{
+		Key:         "filepath",
+		Title:       "Body JSON File Path",
+		Placeholder: "/path/to/body.json",
+	})
+
+	sourceFormRunner := &HuhFormRunner{Form: sourceForm}
+	filePathFormRunner := &HuhFormRunner{Form: filePathForm}
+
+	return collectResponseBodyInternal(sourceFormRunner, filePathFormRunner)
+}
+
 func collectBodyStringInternal(
 	sourceFormRunner,
 	filePathFormRunner FormRunner,
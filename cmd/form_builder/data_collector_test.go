@@ -29,6 +29,14 @@ func TestCollectBody(t *testing.T) {
 	})
 }
 
+func TestCollectResponseBody(t *testing.T) {
+	t.Run("happy path - CollectResponseBody function exists and returns correct type", func(t *testing.T) {
+		var _ func() (any, error) = CollectResponseBody
+
+		assert.NotNil(t, CollectResponseBody)
+	})
+}
+
 func TestCollectBodyString(t *testing.T) {
 	t.Run("happy path - CollectBodyString function exists and returns correct type", func(t *testing.T) {
 		var _ func() (string, error) = CollectBodyString
@@ -435,6 +443,139 @@ func TestCollectBodyInternal(t *testing.T) {
 	})
 }
 
+func TestCollectResponseBodyInternal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("error path - sourceFormRunner.Run() returns error", func(t *testing.T) {
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+		expectedError := errors.New("form run error")
+
+		mockSourceForm.EXPECT().Run().Return(expectedError)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, body)
+	})
+
+	t.Run("happy path - SourceSkip returns nil body", func(t *testing.T) {
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceSkip)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.NoError(t, err)
+		assert.Nil(t, body)
+	})
+
+	t.Run("error path - SourceFile but filePathFormRunner.Run() returns error", func(t *testing.T) {
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+		expectedError := errors.New("file path form error")
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceFile)
+		mockFilePathForm.EXPECT().Run().Return(expectedError)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+		assert.Nil(t, body)
+	})
+
+	t.Run("happy path - SourceFile loads array body from file", func(t *testing.T) {
+
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`[{"id": 1}, {"id": 2}]`), 0644)
+		assert.NoError(t, err)
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceFile)
+		mockFilePathForm.EXPECT().Run().Return(nil)
+		mockFilePathForm.EXPECT().GetString("filepath").Return(filePath)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []any{
+			map[string]any{"id": float64(1)},
+			map[string]any{"id": float64(2)},
+		}, body)
+	})
+
+	t.Run("happy path - SourceFile loads scalar body from file", func(t *testing.T) {
+
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`42`), 0644)
+		assert.NoError(t, err)
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceFile)
+		mockFilePathForm.EXPECT().Run().Return(nil)
+		mockFilePathForm.EXPECT().GetString("filepath").Return(filePath)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(42), body)
+	})
+
+	t.Run("happy path - SourceFile with null", func(t *testing.T) {
+
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`null`), 0644)
+		assert.NoError(t, err)
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceFile)
+		mockFilePathForm.EXPECT().Run().Return(nil)
+		mockFilePathForm.EXPECT().GetString("filepath").Return(filePath)
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.NoError(t, err)
+		assert.Equal(t, config.NullBody, body)
+	})
+
+	t.Run("error path - SourceFile but file does not exist", func(t *testing.T) {
+
+		mockSourceForm := NewMockFormRunner(ctrl)
+		mockFilePathForm := NewMockFormRunner(ctrl)
+
+		mockSourceForm.EXPECT().Run().Return(nil)
+		mockSourceForm.EXPECT().GetString(SourceKey).Return(SourceFile)
+		mockFilePathForm.EXPECT().Run().Return(nil)
+		mockFilePathForm.EXPECT().GetString("filepath").Return("/nonexistent/body.json")
+
+		body, err := collectResponseBodyInternal(mockSourceForm, mockFilePathForm)
+
+		assert.Error(t, err)
+		assert.Nil(t, body)
+	})
+}
+
 func TestCollectBodyStringInternal(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -29,6 +29,34 @@ func ValidatePath(path string) error {
 	return nil
 }
 
+func ValidateHttpMethod(method string) error {
+	method = strings.TrimSpace(method)
+	if method == "" {
+		return fmt.Errorf("http method cannot be empty")
+	}
+
+	if strings.EqualFold(method, http.MethodConnect) {
+		return fmt.Errorf("CONNECT method is not supported")
+	}
+
+	for _, char := range method {
+		if !isMethodTokenChar(char) {
+			return fmt.Errorf("http method contains invalid character %q", char)
+		}
+	}
+
+	return nil
+}
+
+func isMethodTokenChar(char rune) bool {
+	switch {
+	case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		return true
+	default:
+		return strings.ContainsRune("!#$%&'*+-.^_`|~", char)
+	}
+}
+
 func ValidateHost(host string) error {
 	if _, err := url.Parse(host); err != nil {
 		return fmt.Errorf("invalid hostname")
@@ -75,6 +75,41 @@ func TestValidatePath(t *testing.T) {
 	})
 }
 
+func TestValidateHttpMethod(t *testing.T) {
+	t.Run("happy path - valid methods", func(t *testing.T) {
+		validMethods := []string{"GET", "OPTIONS", "HEAD", "purge", "M-SEARCH", " PROPFIND "}
+
+		for _, method := range validMethods {
+			err := ValidateHttpMethod(method)
+			assert.NoError(t, err, "method: %s", method)
+		}
+	})
+
+	t.Run("error path - empty method", func(t *testing.T) {
+		err := ValidateHttpMethod("  ")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "http method cannot be empty")
+	})
+
+	t.Run("error path - CONNECT method", func(t *testing.T) {
+		err := ValidateHttpMethod("connect")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "CONNECT method is not supported")
+	})
+
+	t.Run("error path - invalid characters", func(t *testing.T) {
+		invalidMethods := []string{"BAD METHOD", "A/B", "GET:1"}
+
+		for _, method := range invalidMethods {
+			err := ValidateHttpMethod(method)
+			assert.Error(t, err, "method: %s", method)
+			assert.Contains(t, err.Error(), "http method contains invalid character")
+		}
+	})
+}
+
 func TestValidateHost(t *testing.T) {
 	t.Run("happy path - valid URL", func(t *testing.T) {
 		validHosts := []string{
@@ -5,6 +5,8 @@ const (
 	RouteTypeClient = "client"
 )
 
+const MethodCustom = "custom"
+
 const (
 	BodyTypeBody       = "body"
 	BodyTypeBodyString = "bodyString"
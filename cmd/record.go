@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -23,7 +22,6 @@ const (
 	defaultRecordAddr   = "localhost:8080"
 	defaultExportOutput = "recorded-session.json"
 	defaultExportFormat = "json"
-	exportFilePerm      = 0644
 )
 
 var (
@@ -194,18 +192,12 @@ func fetchRecordedSession(addr string) (*recorder.RecordedSession, error) {
 }
 
 func exportAsJSON(session *recorder.RecordedSession) {
-	data, err := json.MarshalIndent(session, "", "  ")
-	if err != nil {
-		zap.L().Fatal("failed to marshal session", zap.Error(err))
-	}
-
 	absPath, err := config.ResolveAbsolutePath(recordExportOutput)
 	if err != nil {
 		zap.L().Fatal("failed to resolve output path", zap.Error(err))
 	}
 
-	// #nosec G306 - Export files are user-visible data, not secrets
-	if err = os.WriteFile(absPath, data, exportFilePerm); err != nil {
+	if err = recorder.WriteSessionFile(absPath, *session); err != nil {
 		zap.L().Fatal("failed to write export file", zap.Error(err))
 	}
 
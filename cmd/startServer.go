@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/recorder"
 	"github.com/lynicis/inzibat/server"
 )
 
@@ -11,6 +15,14 @@ var (
 	configFile      string
 	isGlobalConfig  = true
 	recordEnabled   bool
+	proxyUpstream   string
+	playbackEnabled bool
+	sessionFile     string
+	recordDir       string
+	recordMaxSizeMb int
+	recordRotate    time.Duration
+	recordMaxFiles  int
+	recordLoad      bool
 	startServerFunc = server.StartServer
 )
 
@@ -26,9 +38,49 @@ The server will read the configuration from (in order of precedence):
   3. inzibat.json in the current working directory
 
 The server will start listening on the port specified in the configuration
-and serve the routes defined in the config file.`,
+and serve the routes defined in the config file.
+
+With --proxy-record, requests no route matches are sent to the given upstream
+and their responses are saved to the session file on shutdown. With
+--playback, the recorded responses are served as mocks:
+
+  inzibat start --proxy-record https://api.example.com
+  inzibat start --playback
+
+With --record-dir, recorded requests are also appended to JSONL files in that
+directory, which are rotated by size or time. --record-load loads them back
+into the recorder at startup:
+
+  inzibat start --record-dir ./recordings --record-rotate 1h --record-load`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := startServerFunc(configFile, isGlobalConfig, recordEnabled); err != nil {
+		options := server.RunOptions{
+			RecordEnabled:       recordEnabled,
+			ProxyRecordUpstream: proxyUpstream,
+			Playback:            playbackEnabled,
+			SessionFile:         sessionFile,
+		}
+		if recordLoad && recordDir == "" {
+			zap.L().Fatal("--record-load needs --record-dir")
+		}
+		if recordDir != "" {
+			options.RecordEnabled = true
+			options.RecordSink = &recorder.FileSinkConfig{
+				Dir:            recordDir,
+				MaxSizeBytes:   int64(recordMaxSizeMb) << 20,
+				RotateInterval: recordRotate,
+				MaxFiles:       recordMaxFiles,
+			}
+			options.LoadRecordSink = recordLoad
+		}
+		if options.ProxyRecordUpstream != "" || options.Playback {
+			absPath, err := config.ResolveAbsolutePath(sessionFile)
+			if err != nil {
+				zap.L().Fatal("failed to resolve session file path", zap.Error(err))
+			}
+			options.SessionFile = absPath
+		}
+
+		if err := startServerFunc(configFile, isGlobalConfig, options); err != nil {
 			zap.L().Fatal("failed to start server", zap.Error(err))
 		}
 	},
@@ -56,5 +108,53 @@ func init() {
 		false,
 		"Enable request recording to capture incoming HTTP traffic",
 	)
+	startServerCmd.Flags().StringVar(
+		&proxyUpstream,
+		"proxy-record",
+		"",
+		"Proxy unmatched requests to this upstream and record their responses",
+	)
+	startServerCmd.Flags().BoolVar(
+		&playbackEnabled,
+		"playback",
+		false,
+		"Serve the requests recorded in the session file as mocks",
+	)
+	startServerCmd.Flags().StringVar(
+		&sessionFile,
+		"session",
+		defaultExportOutput,
+		"Session file used by --proxy-record and --playback",
+	)
+	startServerCmd.Flags().StringVar(
+		&recordDir,
+		"record-dir",
+		"",
+		"Record requests and append them to JSONL files in this directory",
+	)
+	startServerCmd.Flags().IntVar(
+		&recordMaxSizeMb,
+		"record-max-size",
+		recorder.DefaultSinkMaxSizeBytes>>20,
+		"Size in MB at which a --record-dir file is rotated",
+	)
+	startServerCmd.Flags().DurationVar(
+		&recordRotate,
+		"record-rotate",
+		0,
+		"Rotate the --record-dir file after this long, e.g. 1h (0 disables)",
+	)
+	startServerCmd.Flags().IntVar(
+		&recordMaxFiles,
+		"record-max-files",
+		recorder.DefaultSinkMaxFiles,
+		"Number of rotated --record-dir files to keep",
+	)
+	startServerCmd.Flags().BoolVar(
+		&recordLoad,
+		"record-load",
+		false,
+		"Load the requests in --record-dir into the recorder at startup",
+	)
 	rootCmd.AddCommand(startServerCmd)
 }
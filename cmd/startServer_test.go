@@ -3,11 +3,14 @@ package cmd
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/recorder"
+	"github.com/lynicis/inzibat/server"
 )
 
 func TestStartServerCmd(t *testing.T) {
@@ -72,7 +75,7 @@ func TestStartServerCmd_GlobalFlag(t *testing.T) {
 		}()
 
 		var calledWithGlobal bool
-		startServerFunc = func(_ string, isGlobal bool, _ bool) error {
+		startServerFunc = func(_ string, isGlobal bool, _ server.RunOptions) error {
 			calledWithGlobal = isGlobal
 			return nil
 		}
@@ -85,3 +88,78 @@ func TestStartServerCmd_GlobalFlag(t *testing.T) {
 		assert.True(t, calledWithGlobal)
 	})
 }
+
+func TestStartServerCmd_ProxyRecordFlags(t *testing.T) {
+	t.Run("happy path - command has proxy record and playback flags", func(t *testing.T) {
+		require.NotNil(t, startServerCmd.Flag("proxy-record"))
+		require.NotNil(t, startServerCmd.Flag("playback"))
+
+		sessionFlag := startServerCmd.Flag("session")
+		require.NotNil(t, sessionFlag)
+		assert.Equal(t, "recorded-session.json", sessionFlag.DefValue)
+	})
+
+	t.Run("happy path - start server invoked with proxy record options", func(t *testing.T) {
+		originalStartServerFunc := startServerFunc
+		defer func() {
+			startServerFunc = originalStartServerFunc
+			_ = startServerCmd.Flags().Set("proxy-record", "")
+			_ = startServerCmd.Flags().Set("playback", "false")
+			_ = startServerCmd.Flags().Set("session", "recorded-session.json")
+		}()
+
+		var calledWithOptions server.RunOptions
+		startServerFunc = func(_ string, _ bool, options server.RunOptions) error {
+			calledWithOptions = options
+			return nil
+		}
+
+		sessionFile := filepath.Join(t.TempDir(), "session.json")
+		require.NoError(t, startServerCmd.Flags().Set("proxy-record", "http://localhost:9090"))
+		require.NoError(t, startServerCmd.Flags().Set("playback", "true"))
+		require.NoError(t, startServerCmd.Flags().Set("session", sessionFile))
+
+		startServerCmd.Run(startServerCmd, []string{})
+
+		assert.Equal(t, "http://localhost:9090", calledWithOptions.ProxyRecordUpstream)
+		assert.True(t, calledWithOptions.Playback)
+		assert.Equal(t, sessionFile, calledWithOptions.SessionFile)
+	})
+}
+
+func TestStartServerCmd_RecordDirFlags(t *testing.T) {
+	t.Run("happy path - start server invoked with a record sink", func(t *testing.T) {
+		originalStartServerFunc := startServerFunc
+		defer func() {
+			startServerFunc = originalStartServerFunc
+			_ = startServerCmd.Flags().Set("record-dir", "")
+			_ = startServerCmd.Flags().Set("record-max-size", "100")
+			_ = startServerCmd.Flags().Set("record-rotate", "0s")
+			_ = startServerCmd.Flags().Set("record-load", "false")
+		}()
+
+		var calledWithOptions server.RunOptions
+		startServerFunc = func(_ string, _ bool, options server.RunOptions) error {
+			calledWithOptions = options
+			return nil
+		}
+
+		recordDir := t.TempDir()
+		require.NoError(t, startServerCmd.Flags().Set("record-dir", recordDir))
+		require.NoError(t, startServerCmd.Flags().Set("record-max-size", "5"))
+		require.NoError(t, startServerCmd.Flags().Set("record-rotate", "1h"))
+		require.NoError(t, startServerCmd.Flags().Set("record-load", "true"))
+
+		startServerCmd.Run(startServerCmd, []string{})
+
+		assert.True(t, calledWithOptions.RecordEnabled)
+		assert.True(t, calledWithOptions.LoadRecordSink)
+		require.NotNil(t, calledWithOptions.RecordSink)
+		assert.Equal(t, recorder.FileSinkConfig{
+			Dir:            recordDir,
+			MaxSizeBytes:   5 << 20,
+			RotateInterval: time.Hour,
+			MaxFiles:       recorder.DefaultSinkMaxFiles,
+		}, *calledWithOptions.RecordSink)
+	})
+}
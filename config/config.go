@@ -80,36 +80,42 @@ func (reader *Reader) Read() (*Cfg, error) {
 		return nil, err
 	}
 
-	if err = reader.validate(config); err != nil {
+	if err = reader.Prepare(config); err != nil {
 		return nil, err
 	}
 
+	if config.Concurrency == 0 {
+		config.Concurrency = runtime.GOMAXPROCS(3)
+	}
+
+	return config, nil
+}
+
+func (reader *Reader) Prepare(config *Cfg) error {
+	if err := reader.validate(config); err != nil {
+		return err
+	}
+
 	if config.CircuitBreaker != nil {
 		config.CircuitBreaker = MergeCircuitBreakerConfig(nil, config.CircuitBreaker)
 	}
+	if config.Retry != nil {
+		config.Retry = MergeRetryPolicy(nil, config.Retry)
+	}
 
-	if err = normalizeRoutes(config); err != nil {
-		return nil, err
+	if err := normalizeRoutes(config); err != nil {
+		return err
 	}
 
-	if config.HealthCheckRoute {
-		config.Routes = append(
-			config.Routes,
-			Route{
-				Method: "GET",
-				Path:   "/health",
-				FakeResponse: &FakeResponse{
-					StatusCode: http.StatusOK,
-				},
-			},
-		)
+	if config.TLS != nil {
+		config.TLS.BaseDir = reader.baseDir()
 	}
 
-	if config.Concurrency == 0 {
-		config.Concurrency = runtime.GOMAXPROCS(3)
+	if err := loadResponseFiles(config, reader.baseDir()); err != nil {
+		return err
 	}
 
-	return config, nil
+	return prepareServices(config, reader.baseDir())
 }
 
 func (reader *Reader) validate(config *Cfg) error {
@@ -117,12 +123,34 @@ func (reader *Reader) validate(config *Cfg) error {
 		return nil
 	}
 
-	return reader.Validator.Struct(config)
+	if err := reader.Validator.Struct(config); err != nil {
+		return err
+	}
+
+	if len(config.Routes) == 0 && len(config.Services) == 0 {
+		return ErrorNoRoutes
+	}
+
+	return nil
 }
 
 func normalizeRoutes(config *Cfg) error {
 	for routeIndex := range config.Routes {
 		route := &config.Routes[routeIndex]
+		if route.Submitted == nil {
+			submitted, err := copyRoute(*route)
+			if err != nil {
+				return err
+			}
+			route.Submitted = submitted
+		}
+
+		if err := checkFaultProbabilities(route); err != nil {
+			return err
+		}
+		if route.Mirror != nil {
+			normalizeMirror(route.Mirror)
+		}
 		if route.RequestTo == nil {
 			continue
 		}
@@ -131,11 +159,24 @@ func normalizeRoutes(config *Cfg) error {
 			config.CircuitBreaker,
 			route.RequestTo.CircuitBreaker,
 		)
+		route.RequestTo.Retry = MergeRetryPolicy(config.Retry, route.RequestTo.Retry)
+		if route.RequestTo.TimeoutMs == 0 {
+			route.RequestTo.TimeoutMs = config.UpstreamTimeoutMs
+		}
 
 		if route.RequestTo.Method == "" {
 			route.RequestTo.Method = http.MethodGet
 		}
 
+		if len(route.RequestTo.Hosts) > 0 && route.RequestTo.LoadBalancing == "" {
+			route.RequestTo.LoadBalancing = LoadBalancingRoundRobin
+		}
+		for hostIndex := range route.RequestTo.Hosts {
+			if route.RequestTo.Hosts[hostIndex].Weight == 0 {
+				route.RequestTo.Hosts[hostIndex].Weight = 1
+			}
+		}
+
 		if route.RequestTo.Method == http.MethodGet && route.RequestTo.Body != nil {
 			return ErrorGetSendBody
 		}
@@ -144,6 +185,48 @@ func normalizeRoutes(config *Cfg) error {
 	return nil
 }
 
+// copyRoute deep copies a route, so normalizing it leaves the copy as it was.
+func copyRoute(route Route) (*Route, error) {
+	encodedRoute, err := json.Marshal(route)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy route %s %s: %w", route.Method, route.Path, err)
+	}
+
+	var copiedRoute Route
+	if err = json.Unmarshal(encodedRoute, &copiedRoute); err != nil {
+		return nil, fmt.Errorf("failed to copy route %s %s: %w", route.Method, route.Path, err)
+	}
+
+	return &copiedRoute, nil
+}
+
+// checkFaultProbabilities rejects faults that could not all be rolled: a
+// single roll picks at most one of them, so their probabilities share 1.
+func checkFaultProbabilities(route *Route) error {
+	var probabilitySum float64
+	for _, fault := range route.Faults {
+		probabilitySum += fault.Probability
+	}
+	// Leave room for rounding, as 0.1 + 0.2 + 0.7 adds up to a bit over 1.
+	if probabilitySum > 1+1e-9 {
+		return fmt.Errorf("%w: %s %s", ErrorFaultProbabilities, route.Method, route.Path)
+	}
+
+	return nil
+}
+
+func normalizeMirror(mirror *Mirror) {
+	if mirror.Percentage == nil {
+		mirror.Percentage = Float64Pointer(DefaultMirrorPercentage)
+	}
+	if mirror.MaxConcurrency == 0 {
+		mirror.MaxConcurrency = DefaultMirrorMaxConcurrency
+	}
+	if mirror.TimeoutMs == 0 {
+		mirror.TimeoutMs = DefaultMirrorTimeoutMs
+	}
+}
+
 func WriteConfig(cfg *Cfg, filePath string) error {
 	absPath, err := ResolveAbsolutePath(filePath)
 	if err != nil {
@@ -258,6 +258,546 @@ func TestReader_Read(t *testing.T) {
 		assert.NotNil(t, cfg.Routes[0].FakeResponse)
 	})
 
+	t.Run("when fake response body is an array or scalar it should pass validation", func(t *testing.T) {
+		for _, body := range []any{[]any{"a", "b"}, []any{}, "text", float64(0), false} {
+			cfgWithBody := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/mock",
+						FakeResponse: &FakeResponse{
+							StatusCode: http.StatusOK,
+							Body:       body,
+						},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithBody, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.NoError(t, err, "body %v", body)
+			assert.Equal(t, body, cfg.Routes[0].FakeResponse.Body)
+		}
+	})
+
+	t.Run("when route uses extended or custom methods it should pass validation", func(t *testing.T) {
+		for _, method := range []string{fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace, MethodAny, "PURGE", "M-SEARCH"} {
+			cfgWithMethod := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: method,
+						Path:   "/mock",
+						FakeResponse: &FakeResponse{
+							StatusCode: http.StatusOK,
+							BodyString: "ok",
+						},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithMethod, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.NoError(t, err, "method: %s", method)
+			assert.NotNil(t, cfg, "method: %s", method)
+		}
+	})
+
+	t.Run("when route method is CONNECT, lowercase or malformed should return validation error", func(t *testing.T) {
+		for _, method := range []string{"", fiber.MethodConnect, "get", "BAD METHOD", "A/B"} {
+			cfgWithMethod := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: method,
+						Path:   "/mock",
+						FakeResponse: &FakeResponse{
+							StatusCode: http.StatusOK,
+							BodyString: "ok",
+						},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithMethod, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "method: %q", method)
+			assert.Nil(t, cfg, "method: %q", method)
+		}
+	})
+
+	t.Run("when route delay is invalid should return validation error", func(t *testing.T) {
+		invalidDelays := []*Delay{
+			{FixedMs: -1},
+			{Distribution: &DelayDistribution{Type: "gaussian"}},
+			{Distribution: &DelayDistribution{Type: DelayDistributionUniform, MinMs: 200, MaxMs: 100}},
+			{Distribution: &DelayDistribution{Type: DelayDistributionLogNormal, Sigma: 0.5}},
+			{Chunked: &ChunkedDelay{DurationMs: 100}},
+		}
+
+		for _, delay := range invalidDelays {
+			cfgWithDelay := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/mock",
+						FakeResponse: &FakeResponse{
+							StatusCode: http.StatusOK,
+							BodyString: "ok",
+						},
+						Delay: delay,
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithDelay, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "delay: %+v", delay)
+			assert.Nil(t, cfg, "delay: %+v", delay)
+		}
+	})
+
+	t.Run("when route fault is invalid should return validation error", func(t *testing.T) {
+		invalidFaults := []Fault{
+			{Type: "timeout", Probability: 0.5},
+			{Type: FaultEmptyReply},
+			{Type: FaultEmptyReply, Probability: 1.5},
+			{Type: FaultServerError, Probability: 0.5, StatusCodes: []int{http.StatusOK}},
+		}
+
+		for _, fault := range invalidFaults {
+			cfgWithFault := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/mock",
+						FakeResponse: &FakeResponse{
+							StatusCode: http.StatusOK,
+							BodyString: "ok",
+						},
+						Faults: []Fault{fault},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithFault, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "fault: %+v", fault)
+			assert.Nil(t, cfg, "fault: %+v", fault)
+		}
+	})
+
+	t.Run("when route fault probabilities add up to more than 1 should return error", func(t *testing.T) {
+		readFaults := func(faults []Fault) (*Cfg, error) {
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(&Cfg{
+					ServerPort: 8080,
+					Routes: []Route{
+						{
+							Method: fiber.MethodGet,
+							Path:   "/mock",
+							FakeResponse: &FakeResponse{
+								StatusCode: http.StatusOK,
+								BodyString: "ok",
+							},
+							Faults: faults,
+						},
+					},
+				}, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			return cfgLoader.Read()
+		}
+
+		cfg, err := readFaults([]Fault{
+			{Type: FaultEmptyReply, Probability: 0.6},
+			{Type: FaultServerError, Probability: 0.5},
+		})
+		assert.ErrorIs(t, err, ErrorFaultProbabilities)
+		assert.Nil(t, cfg)
+
+		cfg, err = readFaults([]Fault{
+			{Type: FaultEmptyReply, Probability: 0.1},
+			{Type: FaultMalformed, Probability: 0.2},
+			{Type: FaultServerError, Probability: 0.7},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+	})
+
+	t.Run("when proxy fallback is invalid should return validation error", func(t *testing.T) {
+		invalidFallbacks := []*FakeResponse{
+			{BodyString: "cached"},
+			{StatusCode: http.StatusOK},
+		}
+
+		for _, fallback := range invalidFallbacks {
+			cfgWithFallback := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/proxy",
+						RequestTo: &RequestTo{
+							Host:     "http://localhost:8081",
+							Path:     "/users",
+							Fallback: fallback,
+						},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithFallback, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "fallback: %+v", fallback)
+			assert.Nil(t, cfg, "fallback: %+v", fallback)
+		}
+	})
+
+	t.Run("when retry policy is invalid should return validation error", func(t *testing.T) {
+		invalidRetryPolicies := []*RetryPolicy{
+			{MaxAttempts: -1},
+			{BackoffMultiplier: 0.5},
+			{Jitter: 1.5},
+			{RetryOn: []int{600}},
+		}
+
+		for _, retryPolicy := range invalidRetryPolicies {
+			cfgWithRetry := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/proxy",
+						RequestTo: &RequestTo{
+							Host:  "http://localhost:8081",
+							Path:  "/users",
+							Retry: retryPolicy,
+						},
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithRetry, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "retry: %+v", retryPolicy)
+			assert.Nil(t, cfg, "retry: %+v", retryPolicy)
+		}
+	})
+
+	t.Run("when upstream hosts are invalid should return validation error", func(t *testing.T) {
+		invalidRequestTos := []*RequestTo{
+			{Path: "/users"},
+			{Host: "http://localhost:8081", Hosts: []UpstreamHost{{Url: "http://localhost:8082"}}, Path: "/users"},
+			{Hosts: []UpstreamHost{{Url: "not a url"}}, Path: "/users"},
+			{Hosts: []UpstreamHost{{Url: "http://localhost:8082", Weight: -1}}, Path: "/users"},
+			{Hosts: []UpstreamHost{{Url: "http://localhost:8082"}}, LoadBalancing: "fastest", Path: "/users"},
+		}
+
+		for _, requestTo := range invalidRequestTos {
+			cfgWithHosts := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method:    fiber.MethodGet,
+						Path:      "/proxy",
+						RequestTo: requestTo,
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithHosts, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "requestTo: %+v", requestTo)
+			assert.Nil(t, cfg, "requestTo: %+v", requestTo)
+		}
+	})
+
+	t.Run("when mirror is invalid should return validation error", func(t *testing.T) {
+		invalidMirrors := []*Mirror{
+			{},
+			{Hosts: []string{"not a url"}},
+			{Hosts: []string{"http://shadow:8080"}, Percentage: Float64Pointer(150)},
+			{Hosts: []string{"http://shadow:8080"}, MaxConcurrency: -1},
+		}
+
+		for _, mirror := range invalidMirrors {
+			cfgWithMirror := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method:       fiber.MethodGet,
+						Path:         "/users",
+						FakeResponse: &FakeResponse{StatusCode: http.StatusOK},
+						Mirror:       mirror,
+					},
+				},
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithMirror, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "mirror: %+v", mirror)
+			assert.Nil(t, cfg, "mirror: %+v", mirror)
+		}
+	})
+
+	t.Run("when tls is invalid should return validation error", func(t *testing.T) {
+		invalidTLSConfigs := []TLS{
+			{CertFile: "server.pem"},
+			{KeyFile: "server-key.pem"},
+			{CertFile: "server.pem", KeyFile: "server-key.pem", RequireClientCert: true},
+			{Hosts: []string{""}},
+		}
+
+		for _, tlsConfig := range invalidTLSConfigs {
+			cfgWithTLS := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method:       fiber.MethodGet,
+						Path:         "/mock",
+						FakeResponse: &FakeResponse{StatusCode: http.StatusOK, BodyString: "ok"},
+					},
+				},
+				TLS: &tlsConfig,
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithTLS, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			assert.Error(t, err, "tls: %+v", tlsConfig)
+			assert.Nil(t, cfg, "tls: %+v", tlsConfig)
+		}
+	})
+
+	t.Run("when tls is valid it should resolve paths against the config file", func(t *testing.T) {
+		validTLSConfigs := []TLS{
+			{},
+			{Hosts: []string{"api.local", "10.0.0.7"}, RequireClientCert: true},
+			{CertFile: "server.pem", KeyFile: "server-key.pem"},
+			{CertFile: "server.pem", KeyFile: "server-key.pem", RequireClientCert: true, ClientCAFile: "ca.pem"},
+		}
+
+		for _, tlsConfig := range validTLSConfigs {
+			cfgWithTLS := &Cfg{
+				ServerPort: 8080,
+				Routes: []Route{
+					{
+						Method:       fiber.MethodGet,
+						Path:         "/mock",
+						FakeResponse: &FakeResponse{StatusCode: http.StatusOK, BodyString: "ok"},
+					},
+				},
+				TLS: &tlsConfig,
+			}
+
+			mockReader := NewMockReaderStrategy(ctrl)
+			mockReader.EXPECT().
+				Read(gomock.Any()).
+				Return(cfgWithTLS, nil).
+				Times(1)
+
+			cfgLoader := &Reader{
+				ConfigReader: mockReader,
+				Validator:    validator.New(),
+				Filepath:     filepath.Join("configs", "inzibat.json"),
+			}
+
+			cfg, err := cfgLoader.Read()
+
+			require.NoError(t, err, "tls: %+v", tlsConfig)
+			assert.Equal(t, "configs", cfg.TLS.BaseDir)
+		}
+	})
+
+	t.Run("when route only has a response sequence it should pass validation", func(t *testing.T) {
+		cfgWithSequenceOnly := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: fiber.MethodGet,
+					Path:   "/jobs/1",
+					Scenario: &RouteScenario{
+						Name: "jobs",
+					},
+					Sequence: &ResponseSequence{
+						Mode: SequenceModeCycle,
+						Responses: []FakeResponse{
+							{StatusCode: http.StatusAccepted, BodyString: "pending"},
+							{StatusCode: http.StatusOK, BodyString: "done"},
+						},
+					},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().
+			Read(gomock.Any()).
+			Return(cfgWithSequenceOnly, nil).
+			Times(1)
+
+		cfgLoader := &Reader{
+			ConfigReader: mockReader,
+			Validator:    validator.New(),
+		}
+
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Len(t, cfg.Routes[0].Sequence.Responses, 2)
+	})
+
+	t.Run("when scenario has no name should return validation error", func(t *testing.T) {
+		cfgWithUnnamedScenario := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method:       fiber.MethodGet,
+					Path:         "/jobs/1",
+					Scenario:     &RouteScenario{RequiredState: "Started"},
+					FakeResponse: &FakeResponse{StatusCode: http.StatusOK, BodyString: "done"},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().
+			Read(gomock.Any()).
+			Return(cfgWithUnnamedScenario, nil).
+			Times(1)
+
+		cfgLoader := &Reader{
+			ConfigReader: mockReader,
+			Validator:    validator.New(),
+		}
+
+		cfg, err := cfgLoader.Read()
+
+		assert.Nil(t, cfg)
+		assert.Error(t, err)
+	})
+
 	t.Run("should assign route method and RequestTo method correctly", func(t *testing.T) {
 		expectedCfg := &Cfg{
 			ServerPort: 8080,
@@ -330,6 +870,172 @@ func TestReader_Read(t *testing.T) {
 		assert.Equal(t, 3, cfg.Routes[0].RequestTo.CircuitBreaker.FailureThreshold)
 		assert.Equal(t, 10, cfg.Routes[0].RequestTo.CircuitBreaker.MinimumRequests)
 	})
+
+	t.Run("should merge global and route retry policy and default the timeout", func(t *testing.T) {
+		expectedCfg := &Cfg{
+			ServerPort:        8080,
+			UpstreamTimeoutMs: 1500,
+			Retry: &RetryPolicy{
+				MaxAttempts: 2,
+				RetryOn:     []int{http.StatusServiceUnavailable},
+			},
+			Routes: []Route{
+				{
+					Method: fiber.MethodGet,
+					Path:   "/proxy",
+					RequestTo: &RequestTo{
+						Path: "/target",
+						Retry: &RetryPolicy{
+							IdempotentOnly: BoolPointer(false),
+						},
+					},
+				},
+				{
+					Method: fiber.MethodGet,
+					Path:   "/slow",
+					RequestTo: &RequestTo{
+						Path:      "/slow",
+						TimeoutMs: 5000,
+					},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().Read(gomock.Any()).Return(expectedCfg, nil).Times(1)
+
+		cfgLoader := &Reader{ConfigReader: mockReader}
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, 100, cfg.Retry.InitialBackoffMs)
+		routeRetry := cfg.Routes[0].RequestTo.Retry
+		assert.NotNil(t, routeRetry)
+		assert.Equal(t, 2, routeRetry.MaxAttempts)
+		assert.Equal(t, []int{http.StatusServiceUnavailable}, routeRetry.RetryOn)
+		assert.False(t, *routeRetry.IdempotentOnly)
+		assert.Equal(t, 1500, cfg.Routes[0].RequestTo.TimeoutMs)
+		assert.Equal(t, 5000, cfg.Routes[1].RequestTo.TimeoutMs)
+	})
+
+	t.Run("should default load balancing strategy and host weights", func(t *testing.T) {
+		expectedCfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: fiber.MethodGet,
+					Path:   "/proxy",
+					RequestTo: &RequestTo{
+						Hosts: []UpstreamHost{
+							{Url: "http://localhost:8081"},
+							{Url: "http://localhost:8082", Weight: 3},
+						},
+						Path: "/users",
+					},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().Read(gomock.Any()).Return(expectedCfg, nil).Times(1)
+
+		cfgLoader := &Reader{
+			ConfigReader: mockReader,
+			Validator:    validator.New(),
+		}
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, LoadBalancingRoundRobin, cfg.Routes[0].RequestTo.LoadBalancing)
+		assert.Equal(t, 1, cfg.Routes[0].RequestTo.Hosts[0].Weight)
+		assert.Equal(t, 3, cfg.Routes[0].RequestTo.Hosts[1].Weight)
+	})
+
+	t.Run("should default mirror settings", func(t *testing.T) {
+		expectedCfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method:       fiber.MethodGet,
+					Path:         "/users",
+					FakeResponse: &FakeResponse{StatusCode: http.StatusOK},
+					Mirror:       &Mirror{Hosts: []string{"http://shadow:8080"}, Percentage: Float64Pointer(25)},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().Read(gomock.Any()).Return(expectedCfg, nil).Times(1)
+
+		cfgLoader := &Reader{ConfigReader: mockReader}
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.NotNil(t, cfg)
+		assert.Equal(t, &Mirror{
+			Hosts:          []string{"http://shadow:8080"},
+			Percentage:     Float64Pointer(25),
+			MaxConcurrency: DefaultMirrorMaxConcurrency,
+			TimeoutMs:      DefaultMirrorTimeoutMs,
+		}, cfg.Routes[0].Mirror)
+	})
+
+	t.Run("should keep a mirror percentage of zero", func(t *testing.T) {
+		expectedCfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method:       fiber.MethodGet,
+					Path:         "/users",
+					FakeResponse: &FakeResponse{StatusCode: http.StatusOK, BodyString: "users"},
+					Mirror:       &Mirror{Hosts: []string{"http://shadow:8080"}, Percentage: Float64Pointer(0)},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().Read(gomock.Any()).Return(expectedCfg, nil).Times(1)
+
+		cfgLoader := &Reader{
+			ConfigReader: mockReader,
+			Validator:    validator.New(),
+		}
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.Equal(t, Float64Pointer(0), cfg.Routes[0].Mirror.Percentage)
+	})
+
+	t.Run("should keep each route as submitted without the defaults", func(t *testing.T) {
+		expectedCfg := &Cfg{
+			ServerPort:        8080,
+			UpstreamTimeoutMs: 1500,
+			CircuitBreaker:    &CircuitBreakerConfig{Enabled: BoolPointer(true)},
+			Routes: []Route{
+				{
+					Method:    fiber.MethodGet,
+					Path:      "/proxy",
+					RequestTo: &RequestTo{Host: "http://localhost:8081", Path: "/target"},
+				},
+			},
+		}
+
+		mockReader := NewMockReaderStrategy(ctrl)
+		mockReader.EXPECT().Read(gomock.Any()).Return(expectedCfg, nil).Times(1)
+
+		cfgLoader := &Reader{ConfigReader: mockReader}
+		cfg, err := cfgLoader.Read()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1500, cfg.Routes[0].RequestTo.TimeoutMs)
+		assert.Equal(t, Route{
+			Method:    fiber.MethodGet,
+			Path:      "/proxy",
+			RequestTo: &RequestTo{Host: "http://localhost:8081", Path: "/target"},
+		}, cfg.Routes[0].AsSubmitted())
+	})
 }
 
 func TestReadOrCreateConfig(t *testing.T) {
@@ -374,6 +1080,39 @@ func TestReadOrCreateConfig(t *testing.T) {
 		assert.Equal(t, "/test", cfg.Routes[0].Path)
 	})
 
+	t.Run("happy path - read array and scalar response bodies", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		configPath := filepath.Join(tmpDir, "inzibat.json")
+		configJSON := `{
+			"serverPort": 9090,
+			"concurrency": 10,
+			"routes": [
+				{
+					"method": "GET",
+					"path": "/users",
+					"fakeResponse": {"statusCode": 200, "body": [{"id": 1}, {"id": 2}]}
+				},
+				{
+					"method": "GET",
+					"path": "/count",
+					"fakeResponse": {"statusCode": 200, "body": 42}
+				}
+			]
+		}`
+		err := os.WriteFile(configPath, []byte(configJSON), 0644)
+		require.NoError(t, err)
+
+		cfg, err := ReadOrCreateConfig(configPath)
+
+		require.NoError(t, err)
+		require.Len(t, cfg.Routes, 2)
+		assert.Equal(t, []any{
+			map[string]any{"id": float64(1)},
+			map[string]any{"id": float64(2)},
+		}, cfg.Routes[0].FakeResponse.Body)
+		assert.Equal(t, float64(42), cfg.Routes[1].FakeResponse.Body)
+	})
+
 	t.Run("happy path - file without extension defaults to JSON", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		configPath := filepath.Join(tmpDir, "config")
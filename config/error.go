@@ -9,6 +9,12 @@ var (
 	ErrorReadFile      = errors.New("error occurred while reading config file")
 	ErrorUnmarshalling = errors.New("error occurred while unmarshalling config file")
 	ErrorGetSendBody   = errors.New("send body with get http method")
+
+	ErrorFaultProbabilities = errors.New("fault probabilities add up to more than 1")
+
+	ErrorNoRoutes        = errors.New("config needs routes or services")
+	ErrorServiceConflict = errors.New("services listen on the same port and host")
+	ErrorServiceName     = errors.New("service name cannot contain path separators or be . or ..")
 )
 
 func newFailOpeningError(err error) error {
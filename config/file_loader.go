@@ -73,6 +73,32 @@ func (l *BodyLoader) Load(filePath string) (interface{}, error) {
 	return body, nil
 }
 
+// ResponseBodyLoader loads any JSON value, unlike BodyLoader which only
+// accepts objects.
+type ResponseBodyLoader struct{}
+
+func (l *ResponseBodyLoader) Load(filePath string) (interface{}, error) {
+	absPath, err := ResolveAbsolutePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 - File path is validated and cleaned before use
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, newFailOpeningError(err)
+	}
+
+	var body any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if body == nil {
+		return NullBody, nil
+	}
+
+	return body, nil
+}
+
 type BodyStringLoader struct{}
 
 func (l *BodyStringLoader) Load(filePath string) (interface{}, error) {
@@ -113,6 +139,11 @@ func LoadBodyFromFile(filePath string) (HttpBody, error) {
 	return result.(HttpBody), nil
 }
 
+func LoadResponseBodyFromFile(filePath string) (any, error) {
+	loader := &ResponseBodyLoader{}
+	return loader.Load(filePath)
+}
+
 func LoadBodyStringFromFile(filePath string) (string, error) {
 	loader := &BodyStringLoader{}
 	result, err := loader.Load(filePath)
@@ -169,6 +169,89 @@ func TestBodyLoader_Load(t *testing.T) {
 	})
 }
 
+func TestResponseBodyLoader_Load(t *testing.T) {
+	t.Run("happy path - object", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`{"message": "Hello"}`), 0644)
+		assert.NoError(t, err)
+
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load(filePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"message": "Hello"}, result)
+	})
+
+	t.Run("happy path - array", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`[{"id": 1}, "two", 3]`), 0644)
+		assert.NoError(t, err)
+
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load(filePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []any{map[string]any{"id": float64(1)}, "two", float64(3)}, result)
+	})
+
+	t.Run("happy path - scalar", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`false`), 0644)
+		assert.NoError(t, err)
+
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load(filePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, false, result)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`null`), 0644)
+		assert.NoError(t, err)
+
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load(filePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, NullBody, result)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load("/nonexistent/file.json")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to open file")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "invalid.json")
+		err := os.WriteFile(filePath, []byte(`[1, 2`), 0644)
+		assert.NoError(t, err)
+
+		loader := &ResponseBodyLoader{}
+
+		result, err := loader.Load(filePath)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to parse JSON")
+	})
+}
+
 func TestBodyStringLoader_Load(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -316,6 +399,27 @@ func TestLoadBodyFromFile(t *testing.T) {
 	})
 }
 
+func TestLoadResponseBodyFromFile(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		tempDir := t.TempDir()
+		filePath := filepath.Join(tempDir, "body.json")
+		err := os.WriteFile(filePath, []byte(`["a", "b"]`), 0644)
+		assert.NoError(t, err)
+
+		body, err := LoadResponseBodyFromFile(filePath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"a", "b"}, body)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		body, err := LoadResponseBodyFromFile("/nonexistent/file.json")
+
+		assert.Error(t, err)
+		assert.Nil(t, body)
+	})
+}
+
 func TestLoadBodyStringFromFile(t *testing.T) {
 	t.Run("happy path", func(t *testing.T) {
 		tempDir := t.TempDir()
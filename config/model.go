@@ -1,10 +1,33 @@
 package config
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+
+	"github.com/goccy/go-json"
+)
+
+const MethodAny = "ANY"
+
+const (
+	DelayDistributionUniform   = "uniform"
+	DelayDistributionLogNormal = "lognormal"
+)
+
+const (
+	FaultConnectionReset = "connectionReset"
+	FaultEmptyReply      = "emptyReply"
+	FaultMalformed       = "malformed"
+	FaultTruncated       = "truncated"
+	FaultServerError     = "serverError"
+)
+
+const (
+	SequenceModeStep  = "step"
+	SequenceModeCycle = "cycle"
 )
 
 const (
@@ -14,11 +37,19 @@ const (
 )
 
 type Cfg struct {
-	ServerPort       int                   `json:"serverPort" koanf:"serverPort" validate:"required"`
-	Routes           []Route               `json:"routes" koanf:"routes" validate:"required,gt=0,dive,required"`
-	Concurrency      int                   `json:"concurrency" koanf:"concurrency"`
-	HealthCheckRoute bool                  `json:"healthCheckRoute" koanf:"isHealthCheckRouteEnabled"`
-	CircuitBreaker   *CircuitBreakerConfig `json:"circuitBreaker,omitempty" koanf:"circuitBreaker"`
+	ServerPort          int                   `json:"serverPort" koanf:"serverPort" validate:"required"`
+	Routes              []Route               `json:"routes" koanf:"routes" validate:"required_without=Services,omitempty,dive,required"`
+	Concurrency         int                   `json:"concurrency" koanf:"concurrency"`
+	HealthCheckRoute    bool                  `json:"healthCheckRoute" koanf:"isHealthCheckRouteEnabled"`
+	CircuitBreaker      *CircuitBreakerConfig `json:"circuitBreaker,omitempty" koanf:"circuitBreaker"`
+	Retry               *RetryPolicy          `json:"retry,omitempty" koanf:"retry"`
+	UpstreamTimeoutMs   int                   `json:"upstreamTimeoutMs,omitempty" koanf:"upstreamTimeoutMs" validate:"gte=0"`
+	NoMatchResponse     *FakeResponse         `json:"noMatchResponse,omitempty" koanf:"noMatchResponse"`
+	JournalSize         int                   `json:"journalSize,omitempty" koanf:"journalSize" validate:"gte=0"`
+	JournalMaxBodyBytes int                   `json:"journalMaxBodyBytes,omitempty" koanf:"journalMaxBodyBytes" validate:"gte=0"`
+	Delay               *Delay                `json:"delay,omitempty" koanf:"delay"`
+	TLS                 *TLS                  `json:"tls,omitempty" koanf:"tls"`
+	Services            []Service             `json:"services,omitempty" koanf:"services" validate:"omitempty,unique=Name,dive"`
 }
 
 func (cfg *Cfg) GetServerAddr() string {
@@ -26,10 +57,166 @@ func (cfg *Cfg) GetServerAddr() string {
 }
 
 type Route struct {
-	Method       string        `json:"method" koanf:"method" validate:"oneof=GET POST PUT PATCH DELETE"`
-	Path         string        `json:"path" koanf:"path" validate:"required,startswith=/"`
-	RequestTo    *RequestTo    `json:"requestTo,omitempty" koanf:"requestTo" validate:"required_without=FakeResponse"`
-	FakeResponse *FakeResponse `json:"fakeResponse,omitempty" koanf:"fakeResponse" validate:"required_without=RequestTo"`
+	ID           string            `json:"id,omitempty" koanf:"id"`
+	Method       string            `json:"method" koanf:"method" validate:"required,uppercase,printascii,excludesall= /:;()<>@?[]{},ne=CONNECT"`
+	Path         string            `json:"path" koanf:"path" validate:"required,startswith=/"`
+	Match        *RouteMatch       `json:"match,omitempty" koanf:"match"`
+	Scenario     *RouteScenario    `json:"scenario,omitempty" koanf:"scenario"`
+	RequestTo    *RequestTo        `json:"requestTo,omitempty" koanf:"requestTo" validate:"required_without_all=FakeResponse Sequence Static"`
+	FakeResponse *FakeResponse     `json:"fakeResponse,omitempty" koanf:"fakeResponse" validate:"required_without_all=RequestTo Sequence Static"`
+	Sequence     *ResponseSequence `json:"sequence,omitempty" koanf:"sequence" validate:"required_without_all=RequestTo FakeResponse Static"`
+	Static       *StaticDirectory  `json:"static,omitempty" koanf:"static" validate:"required_without_all=RequestTo FakeResponse Sequence"`
+	Delay        *Delay            `json:"delay,omitempty" koanf:"delay"`
+	Faults       []Fault           `json:"faults,omitempty" koanf:"faults" validate:"omitempty,dive"`
+	Mirror       *Mirror           `json:"mirror,omitempty" koanf:"mirror"`
+	// Submitted is the route as it was read or submitted, before the defaults
+	// were merged into it. It is what gets written back to the config file.
+	Submitted *Route `json:"-" koanf:"-"`
+}
+
+// AsSubmitted returns the route without the defaults merged into it, or the
+// route itself when it was never normalized.
+func (route Route) AsSubmitted() Route {
+	if route.Submitted == nil {
+		return route
+	}
+
+	return *route.Submitted
+}
+
+const (
+	DefaultMirrorPercentage     = 100
+	DefaultMirrorMaxConcurrency = 16
+	DefaultMirrorTimeoutMs      = 5000
+)
+
+// Mirror sends a copy of sampled requests to Hosts without waiting for them.
+// Their responses are discarded; copies that would go over MaxConcurrency are
+// dropped instead of queued.
+type Mirror struct {
+	Hosts []string `json:"hosts" koanf:"hosts" validate:"required,gt=0,dive,url"`
+	// Percentage is a pointer so that 0, which mirrors nothing, can be told
+	// apart from leaving it out.
+	Percentage     *float64 `json:"percentage,omitempty" koanf:"percentage" validate:"omitempty,gte=0,lte=100"`
+	MaxConcurrency int      `json:"maxConcurrency,omitempty" koanf:"maxConcurrency" validate:"gte=0"`
+	TimeoutMs      int      `json:"timeoutMs,omitempty" koanf:"timeoutMs" validate:"gte=0"`
+}
+
+// Fault breaks the response of a route with the given probability. The
+// probabilities of a route's faults add up to at most 1; a single roll picks
+// at most one.
+type Fault struct {
+	Type        string  `json:"type" koanf:"type" validate:"required,oneof=connectionReset emptyReply malformed truncated serverError"`
+	Probability float64 `json:"probability" koanf:"probability" validate:"gt=0,lte=1"`
+	StatusCodes []int   `json:"statusCodes,omitempty" koanf:"statusCodes" validate:"omitempty,dive,gte=500,lte=599"`
+}
+
+// Delay holds a response back. The fixed delay and the distribution sample are
+// added up and waited before the response is sent; chunked then spreads the
+// body over its own duration.
+type Delay struct {
+	FixedMs      int                `json:"fixedMs,omitempty" koanf:"fixedMs" validate:"gte=0"`
+	Distribution *DelayDistribution `json:"distribution,omitempty" koanf:"distribution"`
+	Chunked      *ChunkedDelay      `json:"chunked,omitempty" koanf:"chunked"`
+}
+
+type DelayDistribution struct {
+	Type     string  `json:"type" koanf:"type" validate:"required,oneof=uniform lognormal"`
+	MinMs    int     `json:"minMs,omitempty" koanf:"minMs" validate:"gte=0"`
+	MaxMs    int     `json:"maxMs,omitempty" koanf:"maxMs" validate:"gtefield=MinMs"`
+	MedianMs int     `json:"medianMs,omitempty" koanf:"medianMs" validate:"required_if=Type lognormal,gte=0"`
+	Sigma    float64 `json:"sigma,omitempty" koanf:"sigma" validate:"gte=0"`
+}
+
+type ChunkedDelay struct {
+	Chunks     int `json:"chunks" koanf:"chunks" validate:"required,gt=0"`
+	DurationMs int `json:"durationMs" koanf:"durationMs" validate:"required,gt=0"`
+}
+
+// StaticDirectory serves the files under Dir below the route path.
+type StaticDirectory struct {
+	Dir   string `json:"dir" koanf:"dir" validate:"required"`
+	Index string `json:"index,omitempty" koanf:"index"`
+	// ResolvedDir is Dir resolved against the config file directory.
+	ResolvedDir string `json:"-" koanf:"-"`
+}
+
+// Service mocks one more downstream service from the same process. It listens
+// on its own ServerPort, or shares the port of another service and answers the
+// requests whose Host header matches Host. Settings it leaves out are taken
+// from the top level of the config.
+type Service struct {
+	Name            string                `json:"name" koanf:"name" validate:"required"`
+	ServerPort      int                   `json:"serverPort,omitempty" koanf:"serverPort" validate:"omitempty,gt=0,lte=65535"`
+	Host            string                `json:"host,omitempty" koanf:"host" validate:"omitempty,hostname_rfc1123"`
+	Routes          []Route               `json:"routes" koanf:"routes" validate:"required,gt=0,dive,required"`
+	CircuitBreaker  *CircuitBreakerConfig `json:"circuitBreaker,omitempty" koanf:"circuitBreaker"`
+	NoMatchResponse *FakeResponse         `json:"noMatchResponse,omitempty" koanf:"noMatchResponse"`
+	Delay           *Delay                `json:"delay,omitempty" koanf:"delay"`
+}
+
+// TLS serves HTTPS. Without CertFile and KeyFile, a local CA and a leaf
+// certificate for localhost and Hosts are generated and cached in CertDir.
+// RequireClientCert turns on mTLS; client certificates are verified against
+// ClientCAFile, or the generated CA when it is not set.
+type TLS struct {
+	CertFile          string   `json:"certFile,omitempty" koanf:"certFile" validate:"required_with=KeyFile"`
+	KeyFile           string   `json:"keyFile,omitempty" koanf:"keyFile" validate:"required_with=CertFile"`
+	Hosts             []string `json:"hosts,omitempty" koanf:"hosts" validate:"omitempty,dive,required"`
+	CertDir           string   `json:"certDir,omitempty" koanf:"certDir"`
+	RequireClientCert bool     `json:"requireClientCert,omitempty" koanf:"requireClientCert"`
+	ClientCAFile      string   `json:"clientCaFile,omitempty" koanf:"clientCaFile" validate:"required_with_all=CertFile RequireClientCert"`
+	// BaseDir is the directory relative paths are resolved against.
+	BaseDir string `json:"-" koanf:"-"`
+}
+
+// Path resolves filePath against the config file directory.
+func (tlsConfig *TLS) Path(filePath string) string {
+	if filePath == "" {
+		return ""
+	}
+
+	return resolveConfigRelativePath(tlsConfig.BaseDir, filePath)
+}
+
+type RouteScenario struct {
+	Name          string `json:"name" koanf:"name" validate:"required"`
+	RequiredState string `json:"requiredState,omitempty" koanf:"requiredState"`
+	NewState      string `json:"newState,omitempty" koanf:"newState"`
+}
+
+type ResponseSequence struct {
+	Mode      string         `json:"mode,omitempty" koanf:"mode" validate:"omitempty,oneof=step cycle"`
+	Responses []FakeResponse `json:"responses" koanf:"responses" validate:"required,gt=0,dive"`
+}
+
+type RouteMatch struct {
+	Headers map[string]ValueMatcher `json:"headers,omitempty" koanf:"headers" validate:"omitempty,dive"`
+	Query   map[string]ValueMatcher `json:"query,omitempty" koanf:"query" validate:"omitempty,dive"`
+	Cookies map[string]ValueMatcher `json:"cookies,omitempty" koanf:"cookies" validate:"omitempty,dive"`
+	Body    []BodyMatcher           `json:"body,omitempty" koanf:"body" validate:"omitempty,dive"`
+}
+
+type ValueMatcher struct {
+	Equals   string `json:"equals,omitempty" koanf:"equals"`
+	Contains string `json:"contains,omitempty" koanf:"contains"`
+	Regex    string `json:"regex,omitempty" koanf:"regex"`
+}
+
+type BodyMatcher struct {
+	JSONPath string `json:"jsonPath" koanf:"jsonPath" validate:"required,startswith=$"`
+	Exists   *bool  `json:"exists,omitempty" koanf:"exists"`
+	Equals   string `json:"equals,omitempty" koanf:"equals"`
+	Contains string `json:"contains,omitempty" koanf:"contains"`
+	Regex    string `json:"regex,omitempty" koanf:"regex"`
+}
+
+func (bodyMatcher BodyMatcher) ValueMatcher() ValueMatcher {
+	return ValueMatcher{
+		Equals:   bodyMatcher.Equals,
+		Contains: bodyMatcher.Contains,
+		Regex:    bodyMatcher.Regex,
+	}
 }
 
 func (cfg *Cfg) ConvertRoutesTuiTable() [][]string {
@@ -40,9 +227,15 @@ func (cfg *Cfg) ConvertRoutesTuiTable() [][]string {
 		if route.FakeResponse != nil {
 			routeType = "MOCK"
 		}
+		if route.Sequence != nil {
+			routeType = "SEQUENCE"
+		}
 		if route.RequestTo != nil {
 			routeType = "PROXY"
 		}
+		if route.Static != nil {
+			routeType = "STATIC"
+		}
 
 		rows = append(rows, []string{
 			route.Method,
@@ -55,15 +248,49 @@ func (cfg *Cfg) ConvertRoutesTuiTable() [][]string {
 }
 
 type RequestTo struct {
-	Method                 string                `json:"method" koanf:"method" validate:"oneof=GET POST PUT PATCH DELETE"`
+	Method                 string                `json:"method" koanf:"method" validate:"omitempty,uppercase,printascii,excludesall= /:;()<>@?[]{},ne=CONNECT"`
 	Headers                http.Header           `json:"headers" koanf:"headers"`
 	Body                   HttpBody              `json:"body,omitempty" koanf:"body"`
-	Host                   string                `json:"host" koanf:"host" validate:"url"`
+	Host                   string                `json:"host,omitempty" koanf:"host" validate:"required_without=Hosts,excluded_with=Hosts,omitempty,url"`
+	Hosts                  []UpstreamHost        `json:"hosts,omitempty" koanf:"hosts" validate:"omitempty,dive"`
+	LoadBalancing          string                `json:"loadBalancing,omitempty" koanf:"loadBalancing" validate:"omitempty,oneof=round-robin weighted random least-in-flight"`
 	Path                   string                `json:"path" koanf:"path" validate:"required,startswith=/"`
 	PassWithRequestBody    bool                  `json:"passWithRequestBody,omitempty" koanf:"passWithRequestBody"`
 	PassWithRequestHeaders bool                  `json:"passWithRequestHeaders,omitempty" koanf:"passWithRequestHeaders"`
 	InErrorReturn500       bool                  `json:"inErrorReturn500,omitempty" koanf:"inErrorReturn500"`
 	CircuitBreaker         *CircuitBreakerConfig `json:"circuitBreaker,omitempty" koanf:"circuitBreaker"`
+	Retry                  *RetryPolicy          `json:"retry,omitempty" koanf:"retry"`
+	TimeoutMs              int                   `json:"timeoutMs,omitempty" koanf:"timeoutMs" validate:"gte=0"`
+	Query                  *QueryRewrite         `json:"query,omitempty" koanf:"query"`
+	ResponseHeaders        *HeaderFilter         `json:"responseHeaders,omitempty" koanf:"responseHeaders"`
+	// Fallback is answered instead when the circuit breaker is open or the
+	// upstream fails.
+	Fallback *FakeResponse `json:"fallback,omitempty" koanf:"fallback"`
+}
+
+const (
+	LoadBalancingRoundRobin    = "round-robin"
+	LoadBalancingWeighted      = "weighted"
+	LoadBalancingRandom        = "random"
+	LoadBalancingLeastInFlight = "least-in-flight"
+)
+
+// UpstreamHost is one of the hosts a proxy route spreads its requests over.
+// Weight only counts for the weighted strategy and defaults to 1.
+type UpstreamHost struct {
+	Url    string `json:"url" koanf:"url" validate:"required,url"`
+	Weight int    `json:"weight,omitempty" koanf:"weight" validate:"gte=0"`
+}
+
+type QueryRewrite struct {
+	DropIncoming bool              `json:"dropIncoming,omitempty" koanf:"dropIncoming"`
+	Set          map[string]string `json:"set,omitempty" koanf:"set"`
+	Remove       []string          `json:"remove,omitempty" koanf:"remove"`
+}
+
+type HeaderFilter struct {
+	Allow []string `json:"allow,omitempty" koanf:"allow"`
+	Deny  []string `json:"deny,omitempty" koanf:"deny"`
 }
 
 type CircuitBreakerConfig struct {
@@ -80,6 +307,11 @@ func BoolPointer(value bool) *bool {
 	return &boolValue
 }
 
+func Float64Pointer(value float64) *float64 {
+	floatValue := value
+	return &floatValue
+}
+
 func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
 	return CircuitBreakerConfig{
 		Enabled:             BoolPointer(false),
@@ -131,6 +363,72 @@ func applyCircuitBreakerConfig(destination CircuitBreakerConfig, source CircuitB
 	return destination
 }
 
+// RetryPolicy retries failed upstream requests. MaxAttempts counts the first
+// request. RetryOn lists the statuses to retry, any 5xx when empty; requests
+// that get no response are always retried.
+type RetryPolicy struct {
+	MaxAttempts       int     `json:"maxAttempts,omitempty" koanf:"maxAttempts" validate:"gte=0"`
+	InitialBackoffMs  int     `json:"initialBackoffMs,omitempty" koanf:"initialBackoffMs" validate:"gte=0"`
+	MaxBackoffMs      int     `json:"maxBackoffMs,omitempty" koanf:"maxBackoffMs" validate:"gte=0"`
+	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty" koanf:"backoffMultiplier" validate:"omitempty,gte=1"`
+	Jitter            float64 `json:"jitter,omitempty" koanf:"jitter" validate:"gte=0,lte=1"`
+	RetryOn           []int   `json:"retryOn,omitempty" koanf:"retryOn" validate:"omitempty,dive,gte=100,lte=599"`
+	IdempotentOnly    *bool   `json:"idempotentOnly,omitempty" koanf:"idempotentOnly"`
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       4,
+		InitialBackoffMs:  100,
+		MaxBackoffMs:      2000,
+		BackoffMultiplier: 2,
+		IdempotentOnly:    BoolPointer(true),
+	}
+}
+
+func MergeRetryPolicy(base *RetryPolicy, override *RetryPolicy) *RetryPolicy {
+	if base == nil && override == nil {
+		return nil
+	}
+
+	mergedPolicy := DefaultRetryPolicy()
+	if base != nil {
+		mergedPolicy = applyRetryPolicy(mergedPolicy, *base)
+	}
+	if override != nil {
+		mergedPolicy = applyRetryPolicy(mergedPolicy, *override)
+	}
+
+	return &mergedPolicy
+}
+
+func applyRetryPolicy(destination RetryPolicy, source RetryPolicy) RetryPolicy {
+	if source.IdempotentOnly != nil {
+		destination.IdempotentOnly = BoolPointer(*source.IdempotentOnly)
+	}
+	if source.RetryOn != nil {
+		destination.RetryOn = append([]int(nil), source.RetryOn...)
+	}
+
+	if source.MaxAttempts > 0 {
+		destination.MaxAttempts = source.MaxAttempts
+	}
+	if source.InitialBackoffMs > 0 {
+		destination.InitialBackoffMs = source.InitialBackoffMs
+	}
+	if source.MaxBackoffMs > 0 {
+		destination.MaxBackoffMs = source.MaxBackoffMs
+	}
+	if source.BackoffMultiplier > 0 {
+		destination.BackoffMultiplier = source.BackoffMultiplier
+	}
+	if source.Jitter > 0 {
+		destination.Jitter = source.Jitter
+	}
+
+	return destination
+}
+
 func (requestTo *RequestTo) GetParsedUrl() (*url.URL, error) {
 	parsedUrl, err := url.Parse(requestTo.Host + requestTo.Path)
 	if err != nil {
@@ -142,9 +440,46 @@ func (requestTo *RequestTo) GetParsedUrl() (*url.URL, error) {
 
 type HttpBody map[string]any
 
+// FakeResponse is a mocked response. Body is any JSON value: an object, an
+// array, a string, a number, a boolean or null, which is held as NullBody.
 type FakeResponse struct {
 	Headers    http.Header `json:"headers" koanf:"headers"`
-	Body       HttpBody    `json:"body,omitempty" koanf:"body" validate:"required_without=BodyString"`
-	BodyString string      `json:"bodyString,omitempty" koanf:"bodyString" validate:"required_without=Body"`
+	Body       any         `json:"body,omitempty" koanf:"body" validate:"required_without_all=BodyString BodyFile"`
+	BodyString string      `json:"bodyString,omitempty" koanf:"bodyString" validate:"required_without_all=Body BodyFile"`
+	BodyFile   string      `json:"bodyFile,omitempty" koanf:"bodyFile" validate:"required_without_all=Body BodyString"`
 	StatusCode int         `json:"statusCode" koanf:"statusCode" validate:"required"`
+	Template   bool        `json:"template,omitempty" koanf:"template"`
+	// BodyFileContent holds the content of BodyFile once the config is prepared.
+	BodyFileContent []byte `json:"-" koanf:"-"`
+}
+
+type nullBody struct{}
+
+func (*nullBody) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// NullBody is the Body of a response that sends the JSON null. A nil Body
+// means the response has no body at all.
+var NullBody any = &nullBody{}
+
+// UnmarshalJSON reads "body": null as NullBody, so it is not mistaken for a
+// response without a body.
+func (resp *FakeResponse) UnmarshalJSON(data []byte) error {
+	type plainFakeResponse FakeResponse
+	var decoded plainFakeResponse
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if body, exists := fields["body"]; exists && string(bytes.TrimSpace(body)) == "null" {
+		decoded.Body = NullBody
+	}
+
+	*resp = FakeResponse(decoded)
+	return nil
 }
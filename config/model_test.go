@@ -2,8 +2,10 @@ package config
 
 import (
 	"net/url"
+	"path/filepath"
 	"testing"
 
+	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +38,22 @@ func TestCfg_GetServerAddr(t *testing.T) {
 	})
 }
 
+func TestTLS_Path(t *testing.T) {
+	t.Run("happy path - resolves relative paths against the base dir", func(t *testing.T) {
+		tlsConfig := &TLS{BaseDir: filepath.Join("etc", "inzibat")}
+
+		assert.Equal(t, filepath.Join("etc", "inzibat", "server.pem"), tlsConfig.Path("server.pem"))
+	})
+
+	t.Run("happy path - keeps absolute and empty paths", func(t *testing.T) {
+		tlsConfig := &TLS{BaseDir: "etc"}
+		absolutePath := filepath.Join(t.TempDir(), "server.pem")
+
+		assert.Equal(t, absolutePath, tlsConfig.Path(absolutePath))
+		assert.Empty(t, tlsConfig.Path(""))
+	})
+}
+
 func TestCfg_ConvertRoutesTuiTable(t *testing.T) {
 	t.Run("happy path - converts routes to table rows with correct types", func(t *testing.T) {
 
@@ -60,18 +78,25 @@ func TestCfg_ConvertRoutesTuiTable(t *testing.T) {
 					Method: "DELETE",
 					Path:   "/unknown",
 				},
+				{
+					Method: "GET",
+					Path:   "/assets",
+					Static: &StaticDirectory{Dir: "public"},
+				},
 			},
 		}
 
 		rows := cfg.ConvertRoutesTuiTable()
 
-		assert.Len(t, rows, 3)
+		assert.Len(t, rows, 4)
 
 		assert.Equal(t, []string{"GET", "/mock", "MOCK"}, rows[0])
 
 		assert.Equal(t, []string{"POST", "/proxy", "PROXY"}, rows[1])
 
 		assert.Equal(t, []string{"DELETE", "/unknown", "UNKNOWN"}, rows[2])
+
+		assert.Equal(t, []string{"GET", "/assets", "STATIC"}, rows[3])
 	})
 }
 
@@ -183,3 +208,71 @@ func TestMergeCircuitBreakerConfig(t *testing.T) {
 		assert.Equal(t, 60000, result.OpenTimeoutMs)
 	})
 }
+
+func TestMergeRetryPolicy(t *testing.T) {
+	t.Run("happy path - returns nil when both policies are nil", func(t *testing.T) {
+		assert.Nil(t, MergeRetryPolicy(nil, nil))
+	})
+
+	t.Run("happy path - uses default values when only route policy exists", func(t *testing.T) {
+		result := MergeRetryPolicy(nil, &RetryPolicy{Jitter: 0.2})
+
+		assert.NotNil(t, result)
+		assert.Equal(t, 4, result.MaxAttempts)
+		assert.Equal(t, 100, result.InitialBackoffMs)
+		assert.Equal(t, 2000, result.MaxBackoffMs)
+		assert.Equal(t, 2.0, result.BackoffMultiplier)
+		assert.Equal(t, 0.2, result.Jitter)
+		assert.True(t, *result.IdempotentOnly)
+	})
+
+	t.Run("happy path - route overrides global values", func(t *testing.T) {
+		globalPolicy := &RetryPolicy{
+			MaxAttempts:      5,
+			InitialBackoffMs: 50,
+			RetryOn:          []int{502, 503},
+		}
+		routePolicy := &RetryPolicy{
+			MaxAttempts:    2,
+			RetryOn:        []int{429},
+			IdempotentOnly: BoolPointer(false),
+		}
+
+		result := MergeRetryPolicy(globalPolicy, routePolicy)
+
+		assert.NotNil(t, result)
+		assert.Equal(t, 2, result.MaxAttempts)
+		assert.Equal(t, 50, result.InitialBackoffMs)
+		assert.Equal(t, []int{429}, result.RetryOn)
+		assert.False(t, *result.IdempotentOnly)
+	})
+}
+
+func TestFakeResponse_UnmarshalJSON(t *testing.T) {
+	t.Run("happy path - reads an explicit null body as NullBody", func(t *testing.T) {
+		var resp FakeResponse
+		err := json.Unmarshal([]byte(`{"statusCode": 200, "body": null}`), &resp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, NullBody, resp.Body)
+		assert.Equal(t, 200, resp.StatusCode)
+	})
+
+	t.Run("happy path - leaves an absent body nil", func(t *testing.T) {
+		var resp FakeResponse
+		err := json.Unmarshal([]byte(`{"statusCode": 204}`), &resp)
+
+		assert.NoError(t, err)
+		assert.Nil(t, resp.Body)
+	})
+
+	t.Run("happy path - round-trips NullBody", func(t *testing.T) {
+		data, err := json.Marshal(FakeResponse{StatusCode: 200, Body: NullBody})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"body":null`)
+
+		var resp FakeResponse
+		assert.NoError(t, json.Unmarshal(data, &resp))
+		assert.Equal(t, NullBody, resp.Body)
+	})
+}
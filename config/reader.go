@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
+	"maps"
+	"reflect"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/parsers/toml"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -43,12 +46,7 @@ func (jsonReader *JsonReader) Read(filename string) (*Cfg, error) {
 		return nil, ErrorReadFile
 	}
 
-	var config *Cfg
-	if err := jsonReader.KoanfInstance.Unmarshal("", &config); err != nil {
-		return nil, ErrorUnmarshalling
-	}
-
-	return config, nil
+	return unmarshalConfig(jsonReader.KoanfInstance)
 }
 
 type YamlReader struct {
@@ -63,12 +61,7 @@ func (yamlReader *YamlReader) Read(filename string) (*Cfg, error) {
 		return nil, ErrorReadFile
 	}
 
-	var config *Cfg
-	if err := yamlReader.KoanfInstance.Unmarshal("", &config); err != nil {
-		return nil, ErrorUnmarshalling
-	}
-
-	return config, nil
+	return unmarshalConfig(yamlReader.KoanfInstance)
 }
 
 type TomlReader struct {
@@ -83,10 +76,43 @@ func (tomlReader *TomlReader) Read(filename string) (*Cfg, error) {
 		return nil, ErrorReadFile
 	}
 
+	return unmarshalConfig(tomlReader.KoanfInstance)
+}
+
+func unmarshalConfig(koanfInstance *koanf.Koanf) (*Cfg, error) {
 	var config *Cfg
-	if err := tomlReader.KoanfInstance.Unmarshal("", &config); err != nil {
+	if err := koanfInstance.UnmarshalWithConf("", &config, koanf.UnmarshalConf{
+		DecoderConfig: &mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				mapstructure.StringToTimeDurationHookFunc(),
+				mapstructure.TextUnmarshallerHookFunc(),
+				nullBodyHook,
+			),
+			WeaklyTypedInput: true,
+		},
+	}); err != nil {
 		return nil, ErrorUnmarshalling
 	}
 
 	return config, nil
 }
+
+// nullBodyHook reads "body": null as NullBody, so it is not mistaken for a
+// response without a body.
+func nullBodyHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeFor[FakeResponse]() {
+		return data, nil
+	}
+
+	fields, isMap := data.(map[string]any)
+	if !isMap {
+		return data, nil
+	}
+	if body, exists := fields["body"]; !exists || body != nil {
+		return data, nil
+	}
+
+	withNullBody := maps.Clone(fields)
+	withNullBody["body"] = NullBody
+	return withNullBody, nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -81,6 +82,45 @@ func TestReader_ReadConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("null response body", func(t *testing.T) {
+		t.Run("json reader", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "inzibat.json")
+			content := `{"routes": [
+				{"method": "GET", "path": "/null", "fakeResponse": {"statusCode": 200, "body": null}},
+				{"method": "GET", "path": "/empty", "fakeResponse": {"statusCode": 204}}
+			]}`
+			assert.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+
+			jsonReader := &JsonReader{
+				KoanfInstance: koanf.New("."),
+			}
+			cfg, err := jsonReader.Read(filename)
+
+			assert.NoError(t, err)
+			assert.Equal(t, NullBody, cfg.Routes[0].FakeResponse.Body)
+			assert.Nil(t, cfg.Routes[1].FakeResponse.Body)
+		})
+
+		t.Run("yaml reader", func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "inzibat.yaml")
+			content := "routes:\n" +
+				"  - method: GET\n" +
+				"    path: /null\n" +
+				"    fakeResponse:\n" +
+				"      statusCode: 200\n" +
+				"      body: null\n"
+			assert.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+
+			yamlReader := &YamlReader{
+				KoanfInstance: koanf.New("."),
+			}
+			cfg, err := yamlReader.Read(filename)
+
+			assert.NoError(t, err)
+			assert.Equal(t, NullBody, cfg.Routes[0].FakeResponse.Body)
+		})
+	})
+
 	t.Run("config file not found", func(t *testing.T) {
 		t.Run("json reader", func(t *testing.T) {
 			jsonReader := &JsonReader{
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// baseDir is the directory file paths in the config are relative to. Without
+// a config file they are relative to the working directory.
+func (reader *Reader) baseDir() string {
+	if reader.Filepath == "" {
+		return ""
+	}
+
+	return filepath.Dir(reader.Filepath)
+}
+
+func resolveConfigRelativePath(baseDir, filePath string) string {
+	if filepath.IsAbs(filePath) {
+		return filePath
+	}
+
+	return filepath.Join(baseDir, filePath)
+}
+
+func loadResponseFiles(config *Cfg, baseDir string) error {
+	if err := loadBodyFile(config.NoMatchResponse, baseDir); err != nil {
+		return err
+	}
+
+	for routeIndex := range config.Routes {
+		route := &config.Routes[routeIndex]
+		if err := loadBodyFile(route.FakeResponse, baseDir); err != nil {
+			return err
+		}
+
+		if route.RequestTo != nil {
+			if err := loadBodyFile(route.RequestTo.Fallback, baseDir); err != nil {
+				return err
+			}
+		}
+
+		if route.Sequence != nil {
+			for responseIndex := range route.Sequence.Responses {
+				if err := loadBodyFile(&route.Sequence.Responses[responseIndex], baseDir); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := resolveStaticDirectory(route.Static, baseDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadBodyFile(resp *FakeResponse, baseDir string) error {
+	if resp == nil || resp.BodyFile == "" {
+		return nil
+	}
+
+	// #nosec G304 - Body files are chosen by whoever writes the config
+	content, err := os.ReadFile(resolveConfigRelativePath(baseDir, resp.BodyFile))
+	if err != nil {
+		return fmt.Errorf("failed to read body file %s: %w", resp.BodyFile, err)
+	}
+	resp.BodyFileContent = content
+
+	return nil
+}
+
+func resolveStaticDirectory(static *StaticDirectory, baseDir string) error {
+	if static == nil {
+		return nil
+	}
+
+	resolvedDir := resolveConfigRelativePath(baseDir, static.Dir)
+	info, err := os.Stat(resolvedDir)
+	if err != nil {
+		return fmt.Errorf("failed to open static directory %s: %w", static.Dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("static directory %s is not a directory", static.Dir)
+	}
+	static.ResolvedDir = resolvedDir
+
+	return nil
+}
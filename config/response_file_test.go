@@ -0,0 +1,133 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_Prepare_ResponseFiles(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "fixtures", "static"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "fixtures", "users.json"), []byte(`[{"id":1}]`), 0o600))
+	reader := &Reader{
+		Validator: validator.New(),
+		Filepath:  filepath.Join(configDir, "inzibat.json"),
+	}
+
+	t.Run("happy path - loads body files relative to the config file", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/users",
+					FakeResponse: &FakeResponse{
+						StatusCode: http.StatusOK,
+						BodyFile:   "fixtures/users.json",
+					},
+				},
+				{
+					Method: http.MethodGet,
+					Path:   "/jobs",
+					Sequence: &ResponseSequence{
+						Responses: []FakeResponse{
+							{StatusCode: http.StatusOK, BodyFile: filepath.Join(configDir, "fixtures", "users.json")},
+						},
+					},
+				},
+				{
+					Method: http.MethodGet,
+					Path:   "/admins",
+					RequestTo: &RequestTo{
+						Host:     "http://localhost:8081",
+						Path:     "/admins",
+						Fallback: &FakeResponse{StatusCode: http.StatusOK, BodyFile: "fixtures/users.json"},
+					},
+				},
+			},
+			NoMatchResponse: &FakeResponse{StatusCode: http.StatusNotFound, BodyFile: "fixtures/users.json"},
+		}
+
+		require.NoError(t, reader.Prepare(cfg))
+
+		assert.Equal(t, []byte(`[{"id":1}]`), cfg.Routes[0].FakeResponse.BodyFileContent)
+		assert.Equal(t, []byte(`[{"id":1}]`), cfg.Routes[1].Sequence.Responses[0].BodyFileContent)
+		assert.Equal(t, []byte(`[{"id":1}]`), cfg.Routes[2].RequestTo.Fallback.BodyFileContent)
+		assert.Equal(t, []byte(`[{"id":1}]`), cfg.NoMatchResponse.BodyFileContent)
+	})
+
+	t.Run("happy path - resolves static directories relative to the config file", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/assets",
+					Static: &StaticDirectory{Dir: "fixtures/static"},
+				},
+			},
+		}
+
+		require.NoError(t, reader.Prepare(cfg))
+
+		assert.Equal(t, filepath.Join(configDir, "fixtures", "static"), cfg.Routes[0].Static.ResolvedDir)
+	})
+
+	t.Run("error path - missing body file", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/users",
+					FakeResponse: &FakeResponse{
+						StatusCode: http.StatusOK,
+						BodyFile:   "fixtures/missing.json",
+					},
+				},
+			},
+		}
+
+		err := reader.Prepare(cfg)
+
+		assert.ErrorContains(t, err, "failed to read body file fixtures/missing.json")
+	})
+
+	t.Run("error path - static directory is a file", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/assets",
+					Static: &StaticDirectory{Dir: "fixtures/users.json"},
+				},
+			},
+		}
+
+		err := reader.Prepare(cfg)
+
+		assert.ErrorContains(t, err, "is not a directory")
+	})
+
+	t.Run("error path - static route without a directory", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes: []Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/assets",
+					Static: &StaticDirectory{},
+				},
+			},
+		}
+
+		assert.Error(t, reader.Prepare(cfg))
+	})
+}
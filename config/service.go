@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceConfig is the config a single service is served from. The top-level
+// routes of a config form the service without a name.
+type ServiceConfig struct {
+	Name string
+	Host string
+	Cfg  *Cfg
+}
+
+// ServiceConfigs splits cfg into one config per service. Each service config
+// carries the service routes and port along with the top-level settings the
+// service does not override.
+func (cfg *Cfg) ServiceConfigs() []ServiceConfig {
+	serviceConfigs := make([]ServiceConfig, 0, len(cfg.Services)+1)
+	if len(cfg.Routes) > 0 {
+		topLevelCfg := *cfg
+		topLevelCfg.Services = nil
+		serviceConfigs = append(serviceConfigs, ServiceConfig{Cfg: &topLevelCfg})
+	}
+
+	for _, service := range cfg.Services {
+		serviceCfg := *cfg
+		serviceCfg.Services = nil
+		serviceCfg.Routes = service.Routes
+		if service.ServerPort != 0 {
+			serviceCfg.ServerPort = service.ServerPort
+		}
+		if service.CircuitBreaker != nil {
+			serviceCfg.CircuitBreaker = service.CircuitBreaker
+		}
+		if service.NoMatchResponse != nil {
+			serviceCfg.NoMatchResponse = service.NoMatchResponse
+		}
+		if service.Delay != nil {
+			serviceCfg.Delay = service.Delay
+		}
+
+		serviceConfigs = append(serviceConfigs, ServiceConfig{
+			Name: service.Name,
+			Host: service.Host,
+			Cfg:  &serviceCfg,
+		})
+	}
+
+	return serviceConfigs
+}
+
+// SetServiceRoutes replaces the routes of the named service, or the top-level
+// routes when name is empty. It reports whether the service exists.
+func (cfg *Cfg) SetServiceRoutes(name string, routes []Route) bool {
+	if name == "" {
+		cfg.Routes = routes
+		return true
+	}
+
+	for serviceIndex := range cfg.Services {
+		if cfg.Services[serviceIndex].Name == name {
+			cfg.Services[serviceIndex].Routes = routes
+			return true
+		}
+	}
+
+	return false
+}
+
+func prepareServices(config *Cfg, baseDir string) error {
+	serviceByListener := make(map[string]string, len(config.Services)+1)
+	if len(config.Routes) > 0 {
+		serviceByListener[serviceListenerKey(config.ServerPort, "")] = "top-level routes"
+	}
+
+	for serviceIndex := range config.Services {
+		service := &config.Services[serviceIndex]
+		if !isValidServiceName(service.Name) {
+			return fmt.Errorf("%w: %q", ErrorServiceName, service.Name)
+		}
+
+		serverPort := service.ServerPort
+		if serverPort == 0 {
+			serverPort = config.ServerPort
+		}
+		listenerKey := serviceListenerKey(serverPort, service.Host)
+		if otherService, exists := serviceByListener[listenerKey]; exists {
+			return fmt.Errorf("%w: %s and service %s", ErrorServiceConflict, otherService, service.Name)
+		}
+		serviceByListener[listenerKey] = "service " + service.Name
+
+		service.CircuitBreaker = MergeCircuitBreakerConfig(config.CircuitBreaker, service.CircuitBreaker)
+		serviceCfg := &Cfg{
+			Routes:            service.Routes,
+			CircuitBreaker:    service.CircuitBreaker,
+			Retry:             config.Retry,
+			UpstreamTimeoutMs: config.UpstreamTimeoutMs,
+			NoMatchResponse:   service.NoMatchResponse,
+		}
+		if err := normalizeRoutes(serviceCfg); err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		if err := loadResponseFiles(serviceCfg, baseDir); err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// isValidServiceName reports whether name can be used as a file name, as the
+// recordings of a service are written to <record dir>/<name>.jsonl.
+func isValidServiceName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+func serviceListenerKey(serverPort int, host string) string {
+	return fmt.Sprintf("%d %s", serverPort, strings.ToLower(host))
+}
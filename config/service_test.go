@@ -0,0 +1,187 @@
+package config
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serviceTestRoute(path string) Route {
+	return Route{
+		Method:       http.MethodGet,
+		Path:         path,
+		FakeResponse: &FakeResponse{StatusCode: http.StatusOK, BodyString: "ok"},
+	}
+}
+
+func TestCfg_ServiceConfigs(t *testing.T) {
+	t.Run("happy path - top-level routes form the unnamed service", func(t *testing.T) {
+		cfg := &Cfg{ServerPort: 8080, Routes: []Route{serviceTestRoute("/")}}
+
+		serviceConfigs := cfg.ServiceConfigs()
+
+		require.Len(t, serviceConfigs, 1)
+		assert.Empty(t, serviceConfigs[0].Name)
+		assert.Equal(t, 8080, serviceConfigs[0].Cfg.ServerPort)
+		assert.Equal(t, cfg.Routes, serviceConfigs[0].Cfg.Routes)
+	})
+
+	t.Run("happy path - services inherit the settings they do not override", func(t *testing.T) {
+		topLevelDelay := &Delay{FixedMs: 10}
+		serviceDelay := &Delay{FixedMs: 20}
+		topLevelBreaker := &CircuitBreakerConfig{FailureThreshold: 3}
+		cfg := &Cfg{
+			ServerPort:     8080,
+			Concurrency:    4,
+			Delay:          topLevelDelay,
+			CircuitBreaker: topLevelBreaker,
+			Services: []Service{
+				{Name: "users", Routes: []Route{serviceTestRoute("/users")}},
+				{Name: "orders", ServerPort: 8081, Host: "orders.local", Delay: serviceDelay, Routes: []Route{serviceTestRoute("/orders")}},
+			},
+		}
+
+		serviceConfigs := cfg.ServiceConfigs()
+
+		require.Len(t, serviceConfigs, 2)
+		users, orders := serviceConfigs[0], serviceConfigs[1]
+		assert.Equal(t, "users", users.Name)
+		assert.Equal(t, 8080, users.Cfg.ServerPort)
+		assert.Equal(t, 4, users.Cfg.Concurrency)
+		assert.Same(t, topLevelDelay, users.Cfg.Delay)
+		assert.Same(t, topLevelBreaker, users.Cfg.CircuitBreaker)
+		assert.Nil(t, users.Cfg.Services)
+
+		assert.Equal(t, "orders", orders.Name)
+		assert.Equal(t, "orders.local", orders.Host)
+		assert.Equal(t, 8081, orders.Cfg.ServerPort)
+		assert.Same(t, serviceDelay, orders.Cfg.Delay)
+		assert.Equal(t, "/orders", orders.Cfg.Routes[0].Path)
+	})
+}
+
+func TestCfg_SetServiceRoutes(t *testing.T) {
+	cfg := &Cfg{
+		Routes:   []Route{serviceTestRoute("/")},
+		Services: []Service{{Name: "users", Routes: []Route{serviceTestRoute("/users")}}},
+	}
+
+	t.Run("happy path - replaces the routes of a service", func(t *testing.T) {
+		assert.True(t, cfg.SetServiceRoutes("users", []Route{serviceTestRoute("/admins")}))
+		assert.Equal(t, "/admins", cfg.Services[0].Routes[0].Path)
+	})
+
+	t.Run("happy path - empty name replaces the top-level routes", func(t *testing.T) {
+		assert.True(t, cfg.SetServiceRoutes("", []Route{serviceTestRoute("/health")}))
+		assert.Equal(t, "/health", cfg.Routes[0].Path)
+	})
+
+	t.Run("error path - unknown service", func(t *testing.T) {
+		assert.False(t, cfg.SetServiceRoutes("billing", nil))
+	})
+}
+
+func TestReader_Prepare_Services(t *testing.T) {
+	reader := &Reader{Validator: validator.New()}
+
+	t.Run("happy path - normalizes service routes with the service circuit breaker", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort:     8080,
+			CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 3},
+			Services: []Service{
+				{
+					Name:           "users",
+					CircuitBreaker: &CircuitBreakerConfig{OpenTimeoutMs: 500},
+					Routes: []Route{{
+						Method:    http.MethodGet,
+						Path:      "/users",
+						RequestTo: &RequestTo{Host: "http://localhost:8081", Path: "/users"},
+					}},
+				},
+			},
+		}
+
+		require.NoError(t, reader.Prepare(cfg))
+
+		requestTo := cfg.Services[0].Routes[0].RequestTo
+		assert.Equal(t, http.MethodGet, requestTo.Method)
+		assert.Equal(t, 3, requestTo.CircuitBreaker.FailureThreshold)
+		assert.Equal(t, 500, requestTo.CircuitBreaker.OpenTimeoutMs)
+	})
+
+	t.Run("happy path - services may share a port with different hosts", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes:     []Route{serviceTestRoute("/")},
+			Services: []Service{
+				{Name: "users", Host: "users.local", Routes: []Route{serviceTestRoute("/")}},
+				{Name: "orders", Host: "orders.local", Routes: []Route{serviceTestRoute("/")}},
+			},
+		}
+
+		assert.NoError(t, reader.Prepare(cfg))
+	})
+
+	t.Run("error path - services on the same port and host", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Services: []Service{
+				{Name: "users", Host: "api.local", Routes: []Route{serviceTestRoute("/")}},
+				{Name: "orders", ServerPort: 8080, Host: "API.local", Routes: []Route{serviceTestRoute("/")}},
+			},
+		}
+
+		err := reader.Prepare(cfg)
+
+		assert.ErrorIs(t, err, ErrorServiceConflict)
+		assert.ErrorContains(t, err, "service users and service orders")
+	})
+
+	t.Run("error path - service without host on the top-level port", func(t *testing.T) {
+		cfg := &Cfg{
+			ServerPort: 8080,
+			Routes:     []Route{serviceTestRoute("/")},
+			Services:   []Service{{Name: "users", Routes: []Route{serviceTestRoute("/")}}},
+		}
+
+		assert.ErrorIs(t, reader.Prepare(cfg), ErrorServiceConflict)
+	})
+
+	t.Run("error path - service names that are not file names", func(t *testing.T) {
+		for _, name := range []string{"../users", "users/v1", `users\v1`, ".."} {
+			cfg := &Cfg{
+				ServerPort: 8080,
+				Services:   []Service{{Name: name, Routes: []Route{serviceTestRoute("/")}}},
+			}
+
+			assert.ErrorIs(t, reader.Prepare(cfg), ErrorServiceName, name)
+		}
+	})
+
+	t.Run("error path - neither routes nor services", func(t *testing.T) {
+		assert.ErrorIs(t, reader.Prepare(&Cfg{ServerPort: 8080, Routes: []Route{}}), ErrorNoRoutes)
+	})
+
+	t.Run("error path - invalid services", func(t *testing.T) {
+		invalidServices := [][]Service{
+			{{Routes: []Route{serviceTestRoute("/")}}},
+			{{Name: "users"}},
+			{{Name: "users", ServerPort: 70000, Routes: []Route{serviceTestRoute("/")}}},
+			{{Name: "users", Host: "not a host", Routes: []Route{serviceTestRoute("/")}}},
+			{
+				{Name: "users", ServerPort: 8081, Routes: []Route{serviceTestRoute("/")}},
+				{Name: "users", ServerPort: 8082, Routes: []Route{serviceTestRoute("/")}},
+			},
+			{{Name: "users", Routes: []Route{{Method: http.MethodGet, Path: "users"}}}},
+		}
+
+		for _, services := range invalidServices {
+			err := reader.Prepare(&Cfg{ServerPort: 8080, Services: services})
+
+			assert.Error(t, err, "services: %+v", services)
+		}
+	})
+}
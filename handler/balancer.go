@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+// UpstreamBalancer spreads the requests of a proxy route over its hosts.
+type UpstreamBalancer struct {
+	strategy       string
+	weights        []int
+	currentWeights []int
+	inFlight       []atomic.Int64
+	next           atomic.Uint64
+	mu             sync.Mutex
+}
+
+func NewUpstreamBalancer(strategy string, hosts []config.UpstreamHost) *UpstreamBalancer {
+	weights := make([]int, len(hosts))
+	for hostIndex, host := range hosts {
+		weights[hostIndex] = max(host.Weight, 1)
+	}
+
+	return &UpstreamBalancer{
+		strategy:       strategy,
+		weights:        weights,
+		currentWeights: make([]int, len(hosts)),
+		inFlight:       make([]atomic.Int64, len(hosts)),
+	}
+}
+
+// BuildUpstreamBalancers returns a balancer for every proxy route with hosts,
+// keyed by route index.
+func BuildUpstreamBalancers(routes []config.Route) map[int]*UpstreamBalancer {
+	balancers := make(map[int]*UpstreamBalancer)
+	for routeIndex, route := range routes {
+		if route.RequestTo == nil || len(route.RequestTo.Hosts) == 0 {
+			continue
+		}
+
+		balancers[routeIndex] = NewUpstreamBalancer(route.RequestTo.LoadBalancing, route.RequestTo.Hosts)
+	}
+
+	return balancers
+}
+
+// Order returns the host indexes in the order they should be tried for one
+// request; the first one is the pick of the strategy, the rest are there to
+// skip ejected hosts.
+func (balancer *UpstreamBalancer) Order() []int {
+	hostCount := len(balancer.weights)
+	if hostCount == 0 {
+		return nil
+	}
+
+	var first int
+	switch balancer.strategy {
+	case config.LoadBalancingWeighted:
+		first = balancer.pickWeighted()
+	case config.LoadBalancingRandom:
+		first = rand.IntN(hostCount)
+	case config.LoadBalancingLeastInFlight:
+		return balancer.orderByInFlight()
+	default:
+		first = int((balancer.next.Add(1) - 1) % uint64(hostCount))
+	}
+
+	order := make([]int, hostCount)
+	for offset := range order {
+		order[offset] = (first + offset) % hostCount
+	}
+
+	return order
+}
+
+// Acquire counts a request sent to the host until Release is called.
+func (balancer *UpstreamBalancer) Acquire(hostIndex int) {
+	balancer.inFlight[hostIndex].Add(1)
+}
+
+func (balancer *UpstreamBalancer) Release(hostIndex int) {
+	balancer.inFlight[hostIndex].Add(-1)
+}
+
+// pickWeighted is the smooth weighted round-robin of nginx, which interleaves
+// hosts instead of sending runs of requests to the heaviest one.
+func (balancer *UpstreamBalancer) pickWeighted() int {
+	balancer.mu.Lock()
+	defer balancer.mu.Unlock()
+
+	totalWeight := 0
+	picked := 0
+	for hostIndex, weight := range balancer.weights {
+		balancer.currentWeights[hostIndex] += weight
+		totalWeight += weight
+		if balancer.currentWeights[hostIndex] > balancer.currentWeights[picked] {
+			picked = hostIndex
+		}
+	}
+	balancer.currentWeights[picked] -= totalWeight
+
+	return picked
+}
+
+// orderByInFlight sorts hosts by their requests in flight. Ties start from a
+// rotating host so idle hosts share the load.
+func (balancer *UpstreamBalancer) orderByInFlight() []int {
+	hostCount := len(balancer.weights)
+	start := int((balancer.next.Add(1) - 1) % uint64(hostCount))
+
+	order := make([]int, hostCount)
+	inFlight := make([]int64, hostCount)
+	for offset := range order {
+		hostIndex := (start + offset) % hostCount
+		order[offset] = hostIndex
+		inFlight[hostIndex] = balancer.inFlight[hostIndex].Load()
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(inFlight[a], inFlight[b])
+	})
+
+	return order
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestUpstreamBalancer_Order(t *testing.T) {
+	threeHosts := []config.UpstreamHost{{Url: "http://a"}, {Url: "http://b"}, {Url: "http://c"}}
+
+	t.Run("happy path - round robin rotates the first host", func(t *testing.T) {
+		balancer := NewUpstreamBalancer(config.LoadBalancingRoundRobin, threeHosts)
+
+		assert.Equal(t, []int{0, 1, 2}, balancer.Order())
+		assert.Equal(t, []int{1, 2, 0}, balancer.Order())
+		assert.Equal(t, []int{2, 0, 1}, balancer.Order())
+		assert.Equal(t, []int{0, 1, 2}, balancer.Order())
+	})
+
+	t.Run("happy path - weighted interleaves hosts by weight", func(t *testing.T) {
+		balancer := NewUpstreamBalancer(config.LoadBalancingWeighted, []config.UpstreamHost{
+			{Url: "http://a", Weight: 3},
+			{Url: "http://b", Weight: 1},
+		})
+
+		var picks []int
+		for range 8 {
+			picks = append(picks, balancer.Order()[0])
+		}
+
+		assert.Equal(t, []int{0, 0, 1, 0, 0, 0, 1, 0}, picks)
+	})
+
+	t.Run("happy path - random tries every host", func(t *testing.T) {
+		balancer := NewUpstreamBalancer(config.LoadBalancingRandom, threeHosts)
+
+		picked := make(map[int]bool)
+		for range 100 {
+			order := balancer.Order()
+			assert.ElementsMatch(t, []int{0, 1, 2}, order)
+			picked[order[0]] = true
+		}
+
+		assert.Len(t, picked, 3)
+	})
+
+	t.Run("happy path - least in flight prefers idle hosts", func(t *testing.T) {
+		balancer := NewUpstreamBalancer(config.LoadBalancingLeastInFlight, threeHosts)
+		balancer.Acquire(0)
+		balancer.Acquire(0)
+		balancer.Acquire(2)
+
+		assert.Equal(t, []int{1, 2, 0}, balancer.Order())
+
+		balancer.Release(0)
+		balancer.Release(0)
+
+		assert.NotEqual(t, 2, balancer.Order()[0])
+	})
+
+	t.Run("happy path - no hosts", func(t *testing.T) {
+		balancer := NewUpstreamBalancer(config.LoadBalancingRoundRobin, nil)
+
+		assert.Empty(t, balancer.Order())
+	})
+}
+
+func TestBuildUpstreamBalancers(t *testing.T) {
+	t.Run("happy path - builds balancers for routes with hosts only", func(t *testing.T) {
+		routes := []config.Route{
+			{Method: "GET", Path: "/mock", FakeResponse: &config.FakeResponse{StatusCode: 200}},
+			{Method: "GET", Path: "/single", RequestTo: &config.RequestTo{Host: "http://a", Path: "/"}},
+			{Method: "GET", Path: "/balanced", RequestTo: &config.RequestTo{
+				Hosts: []config.UpstreamHost{{Url: "http://a"}, {Url: "http://b"}},
+				Path:  "/",
+			}},
+		}
+
+		balancers := BuildUpstreamBalancers(routes)
+
+		assert.Len(t, balancers, 1)
+		assert.Contains(t, balancers, 2)
+	})
+}
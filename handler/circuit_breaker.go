@@ -2,6 +2,7 @@ package handler
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -18,22 +19,33 @@ const (
 	CircuitBreakerStateHalfOpen CircuitBreakerState = "half-open"
 )
 
+var CircuitBreakerStates = []CircuitBreakerState{
+	CircuitBreakerStateClosed,
+	CircuitBreakerStateOpen,
+	CircuitBreakerStateHalfOpen,
+}
+
 type CircuitBreakerRecord struct {
-	RouteKey             string
-	State                CircuitBreakerState
-	Config               config.CircuitBreakerConfig
-	OpenedAt             time.Time
-	RequestCount         int
-	ConsecutiveFailures  int
-	ConsecutiveSuccesses int
-	HalfOpenRequests     int
-	UpdatedAt            time.Time
+	RouteKey             string                      `json:"routeKey"`
+	State                CircuitBreakerState         `json:"state"`
+	Config               config.CircuitBreakerConfig `json:"config"`
+	OpenedAt             time.Time                   `json:"openedAt"`
+	RequestCount         int                         `json:"requestCount"`
+	ConsecutiveFailures  int                         `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int                         `json:"consecutiveSuccesses"`
+	HalfOpenRequests     int                         `json:"halfOpenRequests"`
+	UpdatedAt            time.Time                   `json:"updatedAt"`
 }
 
+// CircuitBreakerTransitionObserver is called whenever a circuit breaker moves
+// from one state to another.
+type CircuitBreakerTransitionObserver func(routeKey string, from, to CircuitBreakerState)
+
 type CircuitBreakerStore struct {
-	db    *memdb.MemDB
-	clock func() time.Time
-	mu    sync.Mutex
+	db           *memdb.MemDB
+	clock        func() time.Time
+	onTransition CircuitBreakerTransitionObserver
+	mu           sync.Mutex
 }
 
 func NewCircuitBreakerStore() (*CircuitBreakerStore, error) {
@@ -79,6 +91,20 @@ func BuildCircuitBreakerRouteKey(route config.Route) string {
 	)
 }
 
+// BuildCircuitBreakerHostKeys returns a route key for every host of a load
+// balanced route, so each host has a breaker of its own.
+func BuildCircuitBreakerHostKeys(route config.Route) []string {
+	hostKeys := make([]string, 0, len(route.RequestTo.Hosts))
+	for _, host := range route.RequestTo.Hosts {
+		hostRequestTo := *route.RequestTo
+		hostRequestTo.Host = host.Url
+		route.RequestTo = &hostRequestTo
+		hostKeys = append(hostKeys, BuildCircuitBreakerRouteKey(route))
+	}
+
+	return hostKeys
+}
+
 func (store *CircuitBreakerStore) Seed(routeKey string, cfg config.CircuitBreakerConfig) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
@@ -177,6 +203,51 @@ func (store *CircuitBreakerStore) OnFailure(routeKey string) error {
 	})
 }
 
+// SetState moves the breaker to state as if it had got there on its own, so
+// an opened breaker still turns half-open after its open timeout. Closing it
+// keeps its request count, unlike Reset, so it opens again as soon as the
+// failure threshold is reached.
+func (store *CircuitBreakerStore) SetState(routeKey string, state CircuitBreakerState) error {
+	if !slices.Contains(CircuitBreakerStates, state) {
+		return fmt.Errorf("%w: %q", ErrorInvalidCircuitBreakerState, state)
+	}
+
+	return store.update(routeKey, func(record *CircuitBreakerRecord) {
+		switch state {
+		case CircuitBreakerStateOpen:
+			openRecord(record, store.clock())
+		case CircuitBreakerStateHalfOpen:
+			record.State = CircuitBreakerStateHalfOpen
+			record.HalfOpenRequests = 0
+			record.ConsecutiveSuccesses = 0
+		default:
+			record.State = CircuitBreakerStateClosed
+			record.HalfOpenRequests = 0
+			record.ConsecutiveSuccesses = 0
+		}
+	})
+}
+
+// Reset closes the breaker and clears its counters.
+func (store *CircuitBreakerStore) Reset(routeKey string) error {
+	return store.update(routeKey, resetToClosed)
+}
+
+func (store *CircuitBreakerStore) ResetAll() error {
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err = store.Reset(record.RouteKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (store *CircuitBreakerStore) State(routeKey string) (CircuitBreakerState, error) {
 	record, err := store.Get(routeKey)
 	if err != nil {
@@ -196,7 +267,7 @@ func (store *CircuitBreakerStore) Get(routeKey string) (*CircuitBreakerRecord, e
 	}
 
 	if recordRaw == nil {
-		return nil, fmt.Errorf("circuit breaker record not found for route key %s", routeKey)
+		return nil, fmt.Errorf("%w for route key %s", ErrorCircuitBreakerNotFound, routeKey)
 	}
 
 	record := recordRaw.(*CircuitBreakerRecord)
@@ -204,6 +275,30 @@ func (store *CircuitBreakerStore) Get(routeKey string) (*CircuitBreakerRecord, e
 	return &recordCopy, nil
 }
 
+func (store *CircuitBreakerStore) List() ([]CircuitBreakerRecord, error) {
+	txn := store.db.Txn(false)
+	defer txn.Abort()
+
+	iterator, err := txn.Get("circuit_breakers", "id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list circuit breaker records: %w", err)
+	}
+
+	var records []CircuitBreakerRecord
+	for recordRaw := iterator.Next(); recordRaw != nil; recordRaw = iterator.Next() {
+		records = append(records, *recordRaw.(*CircuitBreakerRecord))
+	}
+
+	return records, nil
+}
+
+func (store *CircuitBreakerStore) SetTransitionObserver(observer CircuitBreakerTransitionObserver) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.onTransition = observer
+}
+
 func (store *CircuitBreakerStore) update(routeKey string, updateFn func(record *CircuitBreakerRecord)) error {
 	store.mu.Lock()
 	defer store.mu.Unlock()
@@ -217,10 +312,11 @@ func (store *CircuitBreakerStore) update(routeKey string, updateFn func(record *
 	}
 
 	if recordRaw == nil {
-		return fmt.Errorf("circuit breaker record not found for route key %s", routeKey)
+		return fmt.Errorf("%w for route key %s", ErrorCircuitBreakerNotFound, routeKey)
 	}
 
 	record := recordRaw.(*CircuitBreakerRecord)
+	previousState := record.State
 	updateFn(record)
 	record.UpdatedAt = store.clock()
 
@@ -229,6 +325,9 @@ func (store *CircuitBreakerStore) update(routeKey string, updateFn func(record *
 	}
 
 	txn.Commit()
+	if store.onTransition != nil && record.State != previousState {
+		store.onTransition(routeKey, previousState, record.State)
+	}
 	return nil
 }
 
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type circuitBreakerStateRequest struct {
+	RouteKey string              `json:"routeKey"`
+	State    CircuitBreakerState `json:"state"`
+}
+
+type circuitBreakerResetRequest struct {
+	RouteKey string `json:"routeKey"`
+}
+
+// RegisterCircuitBreakerAdminRoutes exposes the breakers of store. Breakers
+// are named by route key, which is passed in the request body as it holds
+// spaces and slashes.
+func RegisterCircuitBreakerAdminRoutes(app *fiber.App, store *CircuitBreakerStore) {
+	group := app.Group("/_inzibat/circuit-breakers")
+
+	group.Get("/", listCircuitBreakersHandler(store))
+	group.Put("/state", setCircuitBreakerStateHandler(store))
+	group.Post("/reset", resetCircuitBreakersHandler(store))
+}
+
+func listCircuitBreakersHandler(store *CircuitBreakerStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		records, err := store.List()
+		if err != nil {
+			return err
+		}
+		if records == nil {
+			records = []CircuitBreakerRecord{}
+		}
+
+		return ctx.JSON(records)
+	}
+}
+
+func setCircuitBreakerStateHandler(store *CircuitBreakerStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var request circuitBreakerStateRequest
+		if err := ctx.BodyParser(&request); err != nil || request.RouteKey == "" {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "request body must contain a routeKey and a state",
+			})
+		}
+
+		if err := store.SetState(request.RouteKey, request.State); err != nil {
+			return writeCircuitBreakerError(ctx, err)
+		}
+
+		return writeCircuitBreakerRecord(ctx, store, request.RouteKey)
+	}
+}
+
+func resetCircuitBreakersHandler(store *CircuitBreakerStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var request circuitBreakerResetRequest
+		if len(ctx.Body()) > 0 {
+			if err := ctx.BodyParser(&request); err != nil {
+				return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"message": "request body must be empty or contain a routeKey",
+				})
+			}
+		}
+
+		if request.RouteKey == "" {
+			if err := store.ResetAll(); err != nil {
+				return err
+			}
+			return ctx.JSON(fiber.Map{
+				"message": "all circuit breakers reset",
+			})
+		}
+
+		if err := store.Reset(request.RouteKey); err != nil {
+			return writeCircuitBreakerError(ctx, err)
+		}
+
+		return writeCircuitBreakerRecord(ctx, store, request.RouteKey)
+	}
+}
+
+func writeCircuitBreakerRecord(ctx *fiber.Ctx, store *CircuitBreakerStore, routeKey string) error {
+	record, err := store.Get(routeKey)
+	if err != nil {
+		return writeCircuitBreakerError(ctx, err)
+	}
+
+	return ctx.JSON(record)
+}
+
+func writeCircuitBreakerError(ctx *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusInternalServerError
+	switch {
+	case errors.Is(err, ErrorCircuitBreakerNotFound):
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, ErrorInvalidCircuitBreakerState):
+		statusCode = fiber.StatusBadRequest
+	}
+
+	return ctx.Status(statusCode).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func setupCircuitBreakerAdminApp(t *testing.T) (*fiber.App, *CircuitBreakerStore) {
+	t.Helper()
+
+	store, err := NewCircuitBreakerStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Seed("GET /proxy", config.CircuitBreakerConfig{
+		Enabled:          config.BoolPointer(true),
+		FailureThreshold: 5,
+		MinimumRequests:  1,
+		OpenTimeoutMs:    60000,
+	}))
+
+	app := fiber.New(fiber.Config{
+		JSONEncoder: json.Marshal,
+		JSONDecoder: json.Unmarshal,
+	})
+	RegisterCircuitBreakerAdminRoutes(app, store)
+
+	return app, store
+}
+
+func sendCircuitBreakerAdminRequest(t *testing.T, app *fiber.App, method, target, body string) (int, string) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	response, err := app.Test(request, -1)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+func TestListCircuitBreakersHandler(t *testing.T) {
+	t.Run("happy path - lists breakers with their counters", func(t *testing.T) {
+		app, store := setupCircuitBreakerAdminApp(t)
+		require.NoError(t, store.OnFailure("GET /proxy"))
+
+		statusCode, body := sendCircuitBreakerAdminRequest(t, app, fiber.MethodGet, "/_inzibat/circuit-breakers", "")
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		var records []CircuitBreakerRecord
+		require.NoError(t, json.Unmarshal([]byte(body), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, "GET /proxy", records[0].RouteKey)
+		assert.Equal(t, CircuitBreakerStateClosed, records[0].State)
+		assert.Equal(t, 1, records[0].ConsecutiveFailures)
+		assert.Contains(t, body, `"routeKey":"GET /proxy"`)
+	})
+
+	t.Run("happy path - no breakers", func(t *testing.T) {
+		store, err := NewCircuitBreakerStore()
+		require.NoError(t, err)
+		app := fiber.New()
+		RegisterCircuitBreakerAdminRoutes(app, store)
+
+		_, body := sendCircuitBreakerAdminRequest(t, app, fiber.MethodGet, "/_inzibat/circuit-breakers", "")
+
+		assert.JSONEq(t, `[]`, body)
+	})
+}
+
+func TestSetCircuitBreakerStateHandler(t *testing.T) {
+	t.Run("happy path - forces the breaker open", func(t *testing.T) {
+		app, store := setupCircuitBreakerAdminApp(t)
+
+		statusCode, body := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPut, "/_inzibat/circuit-breakers/state",
+			`{"routeKey":"GET /proxy","state":"open"}`)
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Contains(t, body, `"state":"open"`)
+		state, err := store.State("GET /proxy")
+		require.NoError(t, err)
+		assert.Equal(t, CircuitBreakerStateOpen, state)
+	})
+
+	t.Run("error path - missing route key", func(t *testing.T) {
+		app, _ := setupCircuitBreakerAdminApp(t)
+
+		statusCode, _ := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPut, "/_inzibat/circuit-breakers/state", `{"state":"open"}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+	})
+
+	t.Run("error path - invalid state", func(t *testing.T) {
+		app, _ := setupCircuitBreakerAdminApp(t)
+
+		statusCode, body := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPut, "/_inzibat/circuit-breakers/state",
+			`{"routeKey":"GET /proxy","state":"broken"}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+		assert.Contains(t, body, "closed, open or half-open")
+	})
+
+	t.Run("error path - unknown breaker", func(t *testing.T) {
+		app, _ := setupCircuitBreakerAdminApp(t)
+
+		statusCode, _ := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPut, "/_inzibat/circuit-breakers/state",
+			`{"routeKey":"GET /missing","state":"open"}`)
+
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+}
+
+func TestResetCircuitBreakersHandler(t *testing.T) {
+	t.Run("happy path - resets one breaker", func(t *testing.T) {
+		app, store := setupCircuitBreakerAdminApp(t)
+		require.NoError(t, store.SetState("GET /proxy", CircuitBreakerStateOpen))
+
+		statusCode, body := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPost, "/_inzibat/circuit-breakers/reset",
+			`{"routeKey":"GET /proxy"}`)
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Contains(t, body, `"state":"closed"`)
+	})
+
+	t.Run("happy path - resets every breaker without a route key", func(t *testing.T) {
+		app, store := setupCircuitBreakerAdminApp(t)
+		require.NoError(t, store.SetState("GET /proxy", CircuitBreakerStateOpen))
+
+		statusCode, _ := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPost, "/_inzibat/circuit-breakers/reset", "")
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		state, err := store.State("GET /proxy")
+		require.NoError(t, err)
+		assert.Equal(t, CircuitBreakerStateClosed, state)
+	})
+
+	t.Run("error path - unknown breaker", func(t *testing.T) {
+		app, _ := setupCircuitBreakerAdminApp(t)
+
+		statusCode, _ := sendCircuitBreakerAdminRequest(t, app, fiber.MethodPost, "/_inzibat/circuit-breakers/reset",
+			`{"routeKey":"GET /missing"}`)
+
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
@@ -39,6 +40,28 @@ func TestBuildCircuitBreakerRouteKey(t *testing.T) {
 	})
 }
 
+func TestBuildCircuitBreakerHostKeys(t *testing.T) {
+	t.Run("happy path - one key per host", func(t *testing.T) {
+		route := config.Route{
+			Method: http.MethodGet,
+			Path:   "/proxy",
+			RequestTo: &config.RequestTo{
+				Method: http.MethodGet,
+				Hosts:  []config.UpstreamHost{{Url: "http://a:8080"}, {Url: "http://b:8080"}},
+				Path:   "/api",
+			},
+		}
+
+		hostKeys := BuildCircuitBreakerHostKeys(route)
+
+		assert.Equal(t, []string{
+			"GET /proxy -> GET http://a:8080/api",
+			"GET /proxy -> GET http://b:8080/api",
+		}, hostKeys)
+		assert.Empty(t, route.RequestTo.Host)
+	})
+}
+
 func TestCircuitBreakerStore_Seed(t *testing.T) {
 	t.Run("happy path - update existing record", func(t *testing.T) {
 		breakerStore, err := NewCircuitBreakerStore()
@@ -194,6 +217,179 @@ func TestCircuitBreakerStore_Get(t *testing.T) {
 	})
 }
 
+func TestCircuitBreakerStore_List(t *testing.T) {
+	t.Run("happy path - lists records by route key", func(t *testing.T) {
+		breakerStore, err := NewCircuitBreakerStore()
+		assert.NoError(t, err)
+
+		assert.NoError(t, breakerStore.Seed("POST /orders", config.CircuitBreakerConfig{FailureThreshold: 1}))
+		assert.NoError(t, breakerStore.Seed("GET /users", config.CircuitBreakerConfig{FailureThreshold: 1}))
+
+		records, err := breakerStore.List()
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+		assert.Equal(t, "GET /users", records[0].RouteKey)
+		assert.Equal(t, "POST /orders", records[1].RouteKey)
+		assert.Equal(t, CircuitBreakerStateClosed, records[1].State)
+	})
+
+	t.Run("happy path - empty store", func(t *testing.T) {
+		breakerStore, err := NewCircuitBreakerStore()
+		assert.NoError(t, err)
+
+		records, err := breakerStore.List()
+		assert.NoError(t, err)
+		assert.Empty(t, records)
+	})
+}
+
+func TestCircuitBreakerStore_SetState(t *testing.T) {
+	newStore := func(t *testing.T) *CircuitBreakerStore {
+		breakerStore, err := NewCircuitBreakerStore()
+		assert.NoError(t, err)
+		assert.NoError(t, breakerStore.Seed("GET /proxy", config.CircuitBreakerConfig{
+			Enabled:             config.BoolPointer(true),
+			FailureThreshold:    5,
+			MinimumRequests:     1,
+			OpenTimeoutMs:       10,
+			HalfOpenMaxRequests: 1,
+			SuccessThreshold:    1,
+		}))
+
+		return breakerStore
+	}
+
+	t.Run("happy path - opened breaker rejects requests until its open timeout", func(t *testing.T) {
+		now := time.Now()
+		breakerStore := newStore(t)
+		breakerStore.clock = func() time.Time { return now }
+
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateOpen))
+
+		allowed, err := breakerStore.Allow("GET /proxy")
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+
+		now = now.Add(20 * time.Millisecond)
+		allowed, err = breakerStore.Allow("GET /proxy")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("happy path - half-open breaker lets probe requests through", func(t *testing.T) {
+		breakerStore := newStore(t)
+
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateHalfOpen))
+
+		allowed, err := breakerStore.Allow("GET /proxy")
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		allowed, err = breakerStore.Allow("GET /proxy")
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("happy path - closing the breaker keeps its counters", func(t *testing.T) {
+		breakerStore := newStore(t)
+		assert.NoError(t, breakerStore.OnFailure("GET /proxy"))
+		assert.NoError(t, breakerStore.OnFailure("GET /proxy"))
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateHalfOpen))
+		assert.NoError(t, breakerStore.OnSuccess("GET /proxy"))
+		assert.NoError(t, breakerStore.OnFailure("GET /proxy"))
+		assert.NoError(t, breakerStore.OnFailure("GET /proxy"))
+
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateClosed))
+
+		record, err := breakerStore.Get("GET /proxy")
+		assert.NoError(t, err)
+		assert.Equal(t, CircuitBreakerStateClosed, record.State)
+		assert.Equal(t, 2, record.RequestCount)
+		assert.Equal(t, 2, record.ConsecutiveFailures)
+	})
+
+	t.Run("happy path - reset closes the breaker and clears its counters", func(t *testing.T) {
+		breakerStore := newStore(t)
+		assert.NoError(t, breakerStore.OnFailure("GET /proxy"))
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateOpen))
+
+		assert.NoError(t, breakerStore.Reset("GET /proxy"))
+
+		record, err := breakerStore.Get("GET /proxy")
+		assert.NoError(t, err)
+		assert.Equal(t, CircuitBreakerStateClosed, record.State)
+		assert.Zero(t, record.RequestCount)
+		assert.Zero(t, record.ConsecutiveFailures)
+	})
+
+	t.Run("happy path - reset all closes every breaker", func(t *testing.T) {
+		breakerStore := newStore(t)
+		assert.NoError(t, breakerStore.Seed("GET /other", config.CircuitBreakerConfig{}))
+		assert.NoError(t, breakerStore.SetState("GET /proxy", CircuitBreakerStateOpen))
+		assert.NoError(t, breakerStore.SetState("GET /other", CircuitBreakerStateHalfOpen))
+
+		assert.NoError(t, breakerStore.ResetAll())
+
+		records, err := breakerStore.List()
+		assert.NoError(t, err)
+		for _, record := range records {
+			assert.Equal(t, CircuitBreakerStateClosed, record.State)
+		}
+	})
+
+	t.Run("error path - unknown state", func(t *testing.T) {
+		breakerStore := newStore(t)
+
+		err := breakerStore.SetState("GET /proxy", "broken")
+
+		assert.ErrorIs(t, err, ErrorInvalidCircuitBreakerState)
+	})
+
+	t.Run("error path - unknown breaker", func(t *testing.T) {
+		breakerStore := newStore(t)
+
+		err := breakerStore.SetState("GET /missing", CircuitBreakerStateOpen)
+
+		assert.ErrorIs(t, err, ErrorCircuitBreakerNotFound)
+	})
+}
+
+func TestCircuitBreakerStore_SetTransitionObserver(t *testing.T) {
+	t.Run("happy path - reports state changes only", func(t *testing.T) {
+		now := time.Now()
+		breakerStore, err := NewCircuitBreakerStore()
+		assert.NoError(t, err)
+		breakerStore.clock = func() time.Time { return now }
+
+		var transitions []string
+		breakerStore.SetTransitionObserver(func(routeKey string, from, to CircuitBreakerState) {
+			transitions = append(transitions, fmt.Sprintf("%s: %s -> %s", routeKey, from, to))
+		})
+
+		routeKey := "GET /proxy"
+		assert.NoError(t, breakerStore.Seed(routeKey, config.CircuitBreakerConfig{
+			Enabled:             config.BoolPointer(true),
+			FailureThreshold:    2,
+			MinimumRequests:     1,
+			OpenTimeoutMs:       10,
+			HalfOpenMaxRequests: 1,
+			SuccessThreshold:    1,
+		}))
+
+		assert.NoError(t, breakerStore.OnFailure(routeKey))
+		assert.NoError(t, breakerStore.OnFailure(routeKey))
+		now = now.Add(20 * time.Millisecond)
+		_, err = breakerStore.Allow(routeKey)
+		assert.NoError(t, err)
+		assert.NoError(t, breakerStore.OnSuccess(routeKey))
+
+		assert.Equal(t, []string{
+			"GET /proxy: closed -> open",
+			"GET /proxy: open -> half-open",
+			"GET /proxy: half-open -> closed",
+		}, transitions)
+	})
+}
+
 func TestAllowHalfOpen(t *testing.T) {
 	t.Run("happy path - non-half-open state returns false", func(t *testing.T) {
 		record := &CircuitBreakerRecord{
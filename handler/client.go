@@ -1,64 +1,141 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
-	"net/http"
-	"strings"
+	"slices"
 
-	"github.com/goccy/go-json"
-
-	"github.com/goccy/go-reflect"
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 
 	httpPkg "github.com/lynicis/inzibat/client/http"
 	"github.com/lynicis/inzibat/config"
 )
 
+// ResponseSourceHeader tells which path a proxy route with a fallback took.
+const ResponseSourceHeader = "X-Inzibat-Response-Source"
+
+const (
+	ResponseSourceUpstream                = "upstream"
+	ResponseSourceFallbackCircuitOpen     = "fallback-circuit-open"
+	ResponseSourceFallbackUpstreamError   = "fallback-upstream-error"
+	ResponseSourceFallbackUpstreamTimeout = "fallback-upstream-timeout"
+)
+
 type ClientHandler struct {
 	Client                  *httpPkg.Client
 	RouteConfig             *[]config.Route
 	CircuitBreakerStore     *CircuitBreakerStore
 	CircuitBreakerRouteKeys map[int]string
+	// CircuitBreakerHostKeys holds the breaker of every host of load balanced
+	// routes, by route index and then host index.
+	CircuitBreakerHostKeys map[int][]string
+	UpstreamBalancers      map[int]*UpstreamBalancer
+	FallbackTemplates      map[int]*ResponseTemplate
 }
 
 func (clientRoute *ClientHandler) CreateHandler(routeIndex int) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		requestTo := (*clientRoute.RouteConfig)[routeIndex].RequestTo
 		routeKey, hasCircuitBreaker := clientRoute.CircuitBreakerRouteKeys[routeIndex]
+		balancer, isBalanced := clientRoute.UpstreamBalancers[routeIndex]
 
-		isAllowed, err := clientRoute.allowRequest(hasCircuitBreaker, routeKey)
+		var (
+			isAllowed bool
+			hostIndex int
+			bodyBytes []byte
+			err       error
+		)
+		method := resolveUpstreamMethod(ctx, requestTo)
+		if method != fiber.MethodGet && method != fiber.MethodHead {
+			bodyBytes, err = buildForwardBody(ctx, requestTo)
+			if errors.Is(err, errUnmergeableRequestBody) {
+				return ctx.Status(fiber.StatusBadRequest).SendString(err.Error())
+			}
+			if err != nil {
+				return fmt.Errorf("failed to marshal request body: %w", err)
+			}
+		}
+
+		if isBalanced {
+			hostIndex, routeKey, err = clientRoute.pickHost(routeIndex, balancer, -1)
+			isAllowed = hostIndex >= 0
+			hasCircuitBreaker = routeKey != ""
+		} else {
+			isAllowed, err = clientRoute.allowRequest(hasCircuitBreaker, routeKey)
+		}
 		if err != nil {
 			return err
 		}
 		if !isAllowed {
+			if requestTo.Fallback != nil {
+				return clientRoute.writeFallback(ctx, routeIndex, ResponseSourceFallbackCircuitOpen)
+			}
 			return ctx.
 				Status(fiber.StatusServiceUnavailable).
 				SendString("circuit breaker is open")
 		}
 
-		parsedUrl, err := requestTo.GetParsedUrl()
+		routeRequestTo := requestTo
+		if isBalanced {
+			requestTo = balancedRequestTo(routeRequestTo, hostIndex)
+			balancer.Acquire(hostIndex)
+			defer func() {
+				balancer.Release(hostIndex)
+			}()
+		}
+
+		requestTo = resolveRequestTo(ctx, requestTo)
+		upstreamUrl, err := buildUpstreamUrl(ctx, requestTo)
 		if err != nil {
 			return fmt.Errorf("failed to parse request URL: %w", err)
 		}
 
-		bodyBytes, err := json.Marshal(&requestTo.Body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+		requestOptions := buildRequestOptions(requestTo)
+		var retargetErr error
+		if isBalanced {
+			// Every retry goes to a host picked anew, and the failed attempt
+			// counts against the breaker of the host it was sent to.
+			requestOptions.Retarget = func(error, int) (string, bool) {
+				nextHostIndex, nextRouteKey, pickErr := clientRoute.pickHost(routeIndex, balancer, hostIndex)
+				if pickErr != nil || nextHostIndex < 0 {
+					retargetErr = pickErr
+					return "", false
+				}
+				nextUpstreamUrl, urlErr := buildUpstreamUrl(
+					ctx,
+					resolveRequestTo(ctx, balancedRequestTo(routeRequestTo, nextHostIndex)),
+				)
+				if urlErr != nil {
+					return "", false
+				}
+				if retargetErr = clientRoute.recordFailure(hasCircuitBreaker, routeKey); retargetErr != nil {
+					return "", false
+				}
+
+				balancer.Release(hostIndex)
+				balancer.Acquire(nextHostIndex)
+				hostIndex, routeKey = nextHostIndex, nextRouteKey
+				return nextUpstreamUrl, true
+			}
 		}
 
-		methodArguments := clientRoute.prepareMethodArguments(
-			parsedUrl.String(),
-			requestTo.Headers,
+		response, err := clientRoute.Client.DoWithOptions(
+			method,
+			upstreamUrl,
+			buildForwardHeaders(ctx, requestTo),
 			bodyBytes,
-			requestTo.Method,
+			requestOptions,
 		)
-		response, err := clientRoute.executeHttpMethod(requestTo.Method, methodArguments)
+		if retargetErr != nil {
+			return retargetErr
+		}
 		if err != nil {
 			if recordErr := clientRoute.recordFailure(hasCircuitBreaker, routeKey); recordErr != nil {
 				return recordErr
 			}
+			if requestTo.Fallback != nil {
+				return clientRoute.writeFallback(ctx, routeIndex, upstreamErrorSource(err))
+			}
 			if requestTo.InErrorReturn500 {
 				ctx.Status(fiber.StatusInternalServerError)
 				return ctx.Send(nil)
@@ -73,12 +150,40 @@ func (clientRoute *ClientHandler) CreateHandler(routeIndex int) func(ctx *fiber.
 			return recordErr
 		}
 
+		writeUpstreamHeaders(ctx, response.Headers, requestTo.ResponseHeaders)
+		if requestTo.Fallback != nil {
+			ctx.Set(ResponseSourceHeader, ResponseSourceUpstream)
+		}
+
 		return ctx.
 			Status(response.Status).
 			Send(response.Body)
 	}
 }
 
+func (clientRoute *ClientHandler) writeFallback(ctx *fiber.Ctx, routeIndex int, source string) error {
+	fallback := (*clientRoute.RouteConfig)[routeIndex].RequestTo.Fallback
+	if fallbackTemplate, isTemplated := clientRoute.FallbackTemplates[routeIndex]; isTemplated {
+		renderedFallback, err := fallbackTemplate.Render(ctx)
+		if err != nil {
+			return err
+		}
+		fallback = renderedFallback
+	}
+
+	ctx.Set(ResponseSourceHeader, source)
+	return WriteFakeResponse(ctx, fallback)
+}
+
+func upstreamErrorSource(err error) string {
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return ResponseSourceFallbackUpstreamTimeout
+	}
+
+	return ResponseSourceFallbackUpstreamError
+}
+
 func (clientRoute *ClientHandler) allowRequest(hasCircuitBreaker bool, routeKey string) (bool, error) {
 	if !hasCircuitBreaker {
 		return true, nil
@@ -92,6 +197,48 @@ func (clientRoute *ClientHandler) allowRequest(hasCircuitBreaker bool, routeKey
 	return allowed, nil
 }
 
+// balancedRequestTo returns requestTo sent to one of its hosts.
+func balancedRequestTo(requestTo *config.RequestTo, hostIndex int) *config.RequestTo {
+	balancedRequestTo := *requestTo
+	balancedRequestTo.Host = requestTo.Hosts[hostIndex].Url
+
+	return &balancedRequestTo
+}
+
+// pickHost returns the host of a load balanced route to send the request to
+// and the key of its breaker, skipping hosts whose breaker is open. The
+// failed host, if not -1, is tried last so retries go to another host when
+// one is left. The host index is -1 when every host is ejected.
+func (clientRoute *ClientHandler) pickHost(
+	routeIndex int,
+	balancer *UpstreamBalancer,
+	failedHostIndex int,
+) (int, string, error) {
+	hostKeys, hasCircuitBreaker := clientRoute.CircuitBreakerHostKeys[routeIndex]
+	order := balancer.Order()
+	if failedHostIndex >= 0 {
+		order = slices.DeleteFunc(order, func(hostIndex int) bool {
+			return hostIndex == failedHostIndex
+		})
+		order = append(order, failedHostIndex)
+	}
+	for _, hostIndex := range order {
+		if !hasCircuitBreaker {
+			return hostIndex, "", nil
+		}
+
+		isAllowed, err := clientRoute.allowRequest(true, hostKeys[hostIndex])
+		if err != nil {
+			return -1, "", err
+		}
+		if isAllowed {
+			return hostIndex, hostKeys[hostIndex], nil
+		}
+	}
+
+	return -1, "", nil
+}
+
 func (clientRoute *ClientHandler) recordFailure(hasCircuitBreaker bool, routeKey string) error {
 	if !hasCircuitBreaker {
 		return nil
@@ -118,42 +265,10 @@ func (clientRoute *ClientHandler) recordSuccess(hasCircuitBreaker bool, routeKey
 	return nil
 }
 
-func (clientRoute *ClientHandler) prepareMethodArguments(
-	url string,
-	headers http.Header,
-	bodyBytes []byte,
-	method string,
-) []reflect.Value {
-	arguments := []reflect.Value{
-		reflect.ValueOf(url),
-		reflect.ValueOf(headers),
-		reflect.ValueOf(bodyBytes),
-	}
-
-	if method == fiber.MethodGet {
-		arguments = arguments[:len(arguments)-1]
-	}
-
-	return arguments
-}
-
-func (clientRoute *ClientHandler) executeHttpMethod(
-	method string,
-	arguments []reflect.Value,
-) (*httpPkg.Response, error) {
-	methodName := cases.Title(language.Und).String(strings.ToLower(method))
-	requestMethod := reflect.ValueOf(clientRoute.Client).MethodByName(methodName)
-	returnedArguments := requestMethod.Call(arguments)
-
-	response, ok := returnedArguments[0].Interface().(*httpPkg.Response)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast response to http.Response for method %s", method)
-	}
-
-	var err error
-	if !returnedArguments[1].IsNil() {
-		err = returnedArguments[1].Interface().(error)
+func resolveUpstreamMethod(ctx *fiber.Ctx, requestTo *config.RequestTo) string {
+	if requestTo.Method == config.MethodAny {
+		return ctx.Method()
 	}
 
-	return response, err
+	return requestTo.Method
 }
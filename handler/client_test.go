@@ -1,15 +1,19 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
 
 	httpPkg "github.com/lynicis/inzibat/client/http"
 	"github.com/lynicis/inzibat/config"
@@ -190,6 +194,247 @@ func TestClientHandler_CreateHandler(t *testing.T) {
 		})
 	})
 
+	t.Run("pass through request body and headers", func(t *testing.T) {
+		var (
+			upstreamHeaders http.Header
+			upstreamBody    []byte
+		)
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamHeaders = r.Header.Clone()
+			upstreamBody, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: http.MethodPost,
+					Path:   "/proxy",
+					RequestTo: &config.RequestTo{
+						Method: http.MethodPost,
+						Headers: http.Header{
+							"X-Static": {"static"},
+						},
+						Body: config.HttpBody{
+							"source": "inzibat",
+						},
+						Host:                   targetServer.URL,
+						Path:                   "/",
+						PassWithRequestBody:    true,
+						PassWithRequestHeaders: true,
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Post("/proxy", clientHandler.CreateHandler(0))
+
+		request := httptest.NewRequest(http.MethodPost, "/proxy", strings.NewReader(`{"name":"lynicis"}`))
+		request.Header.Set("X-Request-Id", "abc")
+		request.Header.Set("Connection", "close")
+		response, err := fiberApp.Test(request)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "abc", upstreamHeaders.Get("X-Request-Id"))
+		assert.Equal(t, "static", upstreamHeaders.Get("X-Static"))
+		assert.NotEqual(t, "example.com", upstreamHeaders.Get("Host"))
+		assert.JSONEq(t, `{"name":"lynicis","source":"inzibat"}`, string(upstreamBody))
+	})
+
+	t.Run("rejects a non object body that requestTo.body must be merged into", func(t *testing.T) {
+		var upstreamCalled atomic.Bool
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamCalled.Store(true)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: http.MethodPost,
+					Path:   "/proxy",
+					RequestTo: &config.RequestTo{
+						Method:              http.MethodPost,
+						Body:                config.HttpBody{"source": "inzibat"},
+						Host:                targetServer.URL,
+						Path:                "/",
+						PassWithRequestBody: true,
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Post("/proxy", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodPost, "/proxy", strings.NewReader("name=lynicis")))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Equal(t, errUnmergeableRequestBody.Error(), string(body))
+		assert.False(t, upstreamCalled.Load())
+	})
+
+	t.Run("forwards upstream response headers and the query string", func(t *testing.T) {
+		var upstreamQuery string
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamQuery = r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Add("Set-Cookie", "a=1")
+			w.Header().Add("Set-Cookie", "b=2")
+			w.Header().Set("X-Internal", "secret")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"message":"success"}`))
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/proxy",
+					RequestTo: &config.RequestTo{
+						Method: http.MethodGet,
+						Host:   targetServer.URL,
+						Path:   "/",
+						ResponseHeaders: &config.HeaderFilter{
+							Deny: []string{"X-Internal"},
+						},
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/proxy", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/proxy?page=2", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, "page=2", upstreamQuery)
+		assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+		assert.ElementsMatch(t, []string{"a=1", "b=2"}, response.Header.Values("Set-Cookie"))
+		assert.Empty(t, response.Header.Get("X-Internal"))
+	})
+
+	t.Run("substitutes route params into the upstream path", func(t *testing.T) {
+		var upstreamPath string
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: http.MethodGet,
+					Path:   "/users/:id",
+					RequestTo: &config.RequestTo{
+						Method: http.MethodGet,
+						Host:   targetServer.URL,
+						Path:   "/v2/users/:id",
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/users/:id", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "/v2/users/42", upstreamPath)
+	})
+
+	t.Run("ANY target method forwards the incoming method", func(t *testing.T) {
+		var upstreamMethod string
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamMethod = r.Method
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: config.MethodAny,
+					Path:   "/cors",
+					RequestTo: &config.RequestTo{
+						Method: config.MethodAny,
+						Host:   targetServer.URL,
+						Path:   "/cors",
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New(fiber.Config{
+			RequestMethods: append([]string{"PURGE"}, fiber.DefaultMethods...),
+		})
+		fiberApp.All("/cors", clientHandler.CreateHandler(0))
+
+		for _, method := range []string{http.MethodOptions, "PURGE"} {
+			response, err := fiberApp.Test(httptest.NewRequest(method, "/cors", nil))
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNoContent, response.StatusCode)
+			assert.Equal(t, method, upstreamMethod)
+		}
+	})
+
+	t.Run("HEAD target method is sent without a body", func(t *testing.T) {
+		var upstreamMethod string
+		var upstreamBody []byte
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upstreamMethod = r.Method
+			upstreamBody, _ = io.ReadAll(r.Body)
+			w.Header().Set("X-Health", "ok")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: &[]config.Route{
+				{
+					Method: http.MethodHead,
+					Path:   "/health",
+					RequestTo: &config.RequestTo{
+						Method:              http.MethodHead,
+						Host:                targetServer.URL,
+						Path:                "/health",
+						PassWithRequestBody: true,
+					},
+				},
+			},
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Head("/health", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodHead, "/health", strings.NewReader("ignored")))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "ok", response.Header.Get("X-Health"))
+		assert.Equal(t, http.MethodHead, upstreamMethod)
+		assert.Empty(t, upstreamBody)
+	})
+
 	t.Run("error scenarios", func(t *testing.T) {
 		t.Run("invalid URL parsing", func(t *testing.T) {
 			httpClient := httpPkg.NewHttpClient()
@@ -355,3 +600,377 @@ func TestClientHandler_CreateHandler(t *testing.T) {
 		})
 	})
 }
+
+func TestClientHandler_Fallback(t *testing.T) {
+	fallback := &config.FakeResponse{StatusCode: fiber.StatusOK, BodyString: "cached users"}
+	noRetries := httpPkg.RetryConfig{MaxRetries: 0, InitialBackoff: 1, MaxBackoff: 1, BackoffMultiplier: 1}
+
+	newApp := func(clientHandler *ClientHandler) *fiber.App {
+		fiberApp := fiber.New()
+		fiberApp.Get("/users/:id", clientHandler.CreateHandler(0))
+		return fiberApp
+	}
+
+	sendRequest := func(t *testing.T, fiberApp *fiber.App) (*http.Response, string) {
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users/42", nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response, string(body)
+	}
+
+	newRoutes := func(host string, fallback *config.FakeResponse) *[]config.Route {
+		return &[]config.Route{{
+			Method: http.MethodGet,
+			Path:   "/users/:id",
+			RequestTo: &config.RequestTo{
+				Method:   http.MethodGet,
+				Host:     host,
+				Path:     "/users",
+				Fallback: fallback,
+			},
+		}}
+	}
+
+	t.Run("happy path - upstream answers", func(t *testing.T) {
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("live users"))
+		}))
+		defer targetServer.Close()
+
+		fiberApp := newApp(&ClientHandler{
+			Client:      httpPkg.NewHttpClient(),
+			RouteConfig: newRoutes(targetServer.URL, fallback),
+		})
+		response, body := sendRequest(t, fiberApp)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, "live users", body)
+		assert.Equal(t, ResponseSourceUpstream, response.Header.Get(ResponseSourceHeader))
+	})
+
+	t.Run("happy path - upstream fails", func(t *testing.T) {
+		httpClient := httpPkg.NewHttpClient()
+		httpClient.SetRetryConfig(noRetries)
+
+		fiberApp := newApp(&ClientHandler{
+			Client:      httpClient,
+			RouteConfig: newRoutes("http://127.0.0.1:99999", fallback),
+		})
+		response, body := sendRequest(t, fiberApp)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, "cached users", body)
+		assert.Equal(t, ResponseSourceFallbackUpstreamError, response.Header.Get(ResponseSourceHeader))
+	})
+
+	t.Run("happy path - circuit breaker is open", func(t *testing.T) {
+		var upstreamCallCount int32
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&upstreamCallCount, 1)
+		}))
+		defer targetServer.Close()
+
+		circuitBreakerStore, err := NewCircuitBreakerStore()
+		require.NoError(t, err)
+		require.NoError(t, circuitBreakerStore.Seed("users", config.CircuitBreakerConfig{OpenTimeoutMs: 60000}))
+		require.NoError(t, circuitBreakerStore.SetState("users", CircuitBreakerStateOpen))
+
+		fiberApp := newApp(&ClientHandler{
+			Client:                  httpPkg.NewHttpClient(),
+			RouteConfig:             newRoutes(targetServer.URL, fallback),
+			CircuitBreakerStore:     circuitBreakerStore,
+			CircuitBreakerRouteKeys: map[int]string{0: "users"},
+		})
+		response, body := sendRequest(t, fiberApp)
+
+		assert.Equal(t, "cached users", body)
+		assert.Equal(t, ResponseSourceFallbackCircuitOpen, response.Header.Get(ResponseSourceHeader))
+		assert.Zero(t, atomic.LoadInt32(&upstreamCallCount))
+	})
+
+	t.Run("happy path - renders a templated fallback", func(t *testing.T) {
+		httpClient := httpPkg.NewHttpClient()
+		httpClient.SetRetryConfig(noRetries)
+		routes := newRoutes("http://127.0.0.1:99999", &config.FakeResponse{
+			StatusCode: fiber.StatusOK,
+			BodyString: "cached user {{ .Params.id }}",
+			Template:   true,
+		})
+		fallbackTemplates, err := BuildFallbackTemplates(*routes)
+		require.NoError(t, err)
+
+		fiberApp := newApp(&ClientHandler{
+			Client:            httpClient,
+			RouteConfig:       routes,
+			FallbackTemplates: fallbackTemplates,
+		})
+		_, body := sendRequest(t, fiberApp)
+
+		assert.Equal(t, "cached user 42", body)
+	})
+
+	t.Run("happy path - without a fallback the source header is not set", func(t *testing.T) {
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer targetServer.Close()
+
+		fiberApp := newApp(&ClientHandler{
+			Client:      httpPkg.NewHttpClient(),
+			RouteConfig: newRoutes(targetServer.URL, nil),
+		})
+		response, _ := sendRequest(t, fiberApp)
+
+		assert.Empty(t, response.Header.Get(ResponseSourceHeader))
+	})
+}
+
+func TestUpstreamErrorSource(t *testing.T) {
+	t.Run("happy path - timeouts", func(t *testing.T) {
+		assert.Equal(t, ResponseSourceFallbackUpstreamTimeout, upstreamErrorSource(fasthttp.ErrTimeout))
+	})
+
+	t.Run("happy path - other errors", func(t *testing.T) {
+		assert.Equal(t, ResponseSourceFallbackUpstreamError, upstreamErrorSource(errors.New("response failed")))
+	})
+}
+
+func TestClientHandler_RetryPolicy(t *testing.T) {
+	newRoutes := func(host, method string, requestTo config.RequestTo) *[]config.Route {
+		requestTo.Method = method
+		requestTo.Host = host
+		requestTo.Path = "/users"
+		return &[]config.Route{{
+			Method:    method,
+			Path:      "/users",
+			RequestTo: &requestTo,
+		}}
+	}
+
+	newFlakyServer := func(failures int32) (*httptest.Server, *atomic.Int32) {
+		var attempts atomic.Int32
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte("live users"))
+		}))
+		return targetServer, &attempts
+	}
+
+	retryPolicy := &config.RetryPolicy{MaxAttempts: 3, BackoffMultiplier: 1, IdempotentOnly: config.BoolPointer(true)}
+
+	t.Run("happy path - retries an idempotent request", func(t *testing.T) {
+		targetServer, attempts := newFlakyServer(2)
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client:      httpPkg.NewHttpClient(),
+			RouteConfig: newRoutes(targetServer.URL, http.MethodGet, config.RequestTo{Retry: retryPolicy}),
+		}
+		fiberApp := fiber.New()
+		fiberApp.Get("/users", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("happy path - does not retry a non-idempotent request", func(t *testing.T) {
+		targetServer, attempts := newFlakyServer(2)
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client:      httpPkg.NewHttpClient(),
+			RouteConfig: newRoutes(targetServer.URL, http.MethodPost, config.RequestTo{Retry: retryPolicy}),
+		}
+		fiberApp := fiber.New()
+		fiberApp.Post("/users", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodPost, "/users", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusInternalServerError, response.StatusCode)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("error path - answers the timeout fallback for a slow upstream", func(t *testing.T) {
+		targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+		}))
+		defer targetServer.Close()
+
+		clientHandler := &ClientHandler{
+			Client: httpPkg.NewHttpClient(),
+			RouteConfig: newRoutes(targetServer.URL, http.MethodGet, config.RequestTo{
+				TimeoutMs: 50,
+				Retry:     &config.RetryPolicy{MaxAttempts: 1},
+				Fallback:  &config.FakeResponse{StatusCode: fiber.StatusOK, BodyString: "cached users"},
+			}),
+		}
+		fiberApp := fiber.New()
+		fiberApp.Get("/users", clientHandler.CreateHandler(0))
+
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, ResponseSourceFallbackUpstreamTimeout, response.Header.Get(ResponseSourceHeader))
+	})
+}
+
+func TestClientHandler_LoadBalancing(t *testing.T) {
+	noRetries := httpPkg.RetryConfig{MaxRetries: 0, InitialBackoff: 1, MaxBackoff: 1, BackoffMultiplier: 1}
+
+	newHostServer := func(name string, statusCode int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(statusCode)
+			_, _ = w.Write([]byte(name))
+		}))
+	}
+
+	newRoutes := func(hosts ...string) *[]config.Route {
+		upstreamHosts := make([]config.UpstreamHost, 0, len(hosts))
+		for _, host := range hosts {
+			upstreamHosts = append(upstreamHosts, config.UpstreamHost{Url: host, Weight: 1})
+		}
+		return &[]config.Route{{
+			Method: http.MethodGet,
+			Path:   "/users",
+			RequestTo: &config.RequestTo{
+				Method:        http.MethodGet,
+				Hosts:         upstreamHosts,
+				LoadBalancing: config.LoadBalancingRoundRobin,
+				Path:          "/users",
+			},
+		}}
+	}
+
+	sendRequest := func(t *testing.T, fiberApp *fiber.App) (int, string) {
+		response, err := fiberApp.Test(httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, string(body)
+	}
+
+	newApp := func(t *testing.T, routes *[]config.Route, withCircuitBreaker bool) *fiber.App {
+		httpClient := httpPkg.NewHttpClient()
+		httpClient.SetRetryConfig(noRetries)
+
+		clientHandler := &ClientHandler{
+			Client:            httpClient,
+			RouteConfig:       routes,
+			UpstreamBalancers: BuildUpstreamBalancers(*routes),
+		}
+		if withCircuitBreaker {
+			circuitBreakerStore, err := NewCircuitBreakerStore()
+			require.NoError(t, err)
+
+			hostKeys := BuildCircuitBreakerHostKeys((*routes)[0])
+			for _, hostKey := range hostKeys {
+				require.NoError(t, circuitBreakerStore.Seed(hostKey, config.CircuitBreakerConfig{
+					Enabled:             config.BoolPointer(true),
+					FailureThreshold:    1,
+					MinimumRequests:     1,
+					OpenTimeoutMs:       60000,
+					HalfOpenMaxRequests: 1,
+					SuccessThreshold:    1,
+				}))
+			}
+			clientHandler.CircuitBreakerStore = circuitBreakerStore
+			clientHandler.CircuitBreakerHostKeys = map[int][]string{0: hostKeys}
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/users", clientHandler.CreateHandler(0))
+		return fiberApp
+	}
+
+	t.Run("happy path - spreads requests over hosts", func(t *testing.T) {
+		firstServer := newHostServer("first", http.StatusOK)
+		defer firstServer.Close()
+		secondServer := newHostServer("second", http.StatusOK)
+		defer secondServer.Close()
+
+		fiberApp := newApp(t, newRoutes(firstServer.URL, secondServer.URL), false)
+
+		var bodies []string
+		for range 4 {
+			statusCode, body := sendRequest(t, fiberApp)
+			assert.Equal(t, fiber.StatusOK, statusCode)
+			bodies = append(bodies, body)
+		}
+
+		assert.Equal(t, []string{"first", "second", "first", "second"}, bodies)
+	})
+
+	t.Run("happy path - ejects a failing host on its own", func(t *testing.T) {
+		failingServer := newHostServer("failing", http.StatusBadGateway)
+		defer failingServer.Close()
+		healthyServer := newHostServer("healthy", http.StatusOK)
+		defer healthyServer.Close()
+
+		fiberApp := newApp(t, newRoutes(failingServer.URL, healthyServer.URL), true)
+
+		statusCode, _ := sendRequest(t, fiberApp)
+		assert.Equal(t, fiber.StatusInternalServerError, statusCode)
+
+		for range 3 {
+			statusCode, body := sendRequest(t, fiberApp)
+			assert.Equal(t, fiber.StatusOK, statusCode)
+			assert.Equal(t, "healthy", body)
+		}
+	})
+
+	t.Run("happy path - retries go to another host", func(t *testing.T) {
+		var failingAttempts atomic.Int32
+		failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			failingAttempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failingServer.Close()
+		healthyServer := newHostServer("healthy", http.StatusOK)
+		defer healthyServer.Close()
+
+		routes := newRoutes(failingServer.URL, healthyServer.URL)
+		(*routes)[0].RequestTo.Retry = &config.RetryPolicy{
+			MaxAttempts:       2,
+			BackoffMultiplier: 1,
+			IdempotentOnly:    config.BoolPointer(true),
+		}
+		fiberApp := newApp(t, routes, true)
+
+		for range 3 {
+			statusCode, body := sendRequest(t, fiberApp)
+			assert.Equal(t, fiber.StatusOK, statusCode)
+			assert.Equal(t, "healthy", body)
+		}
+
+		// The failed first attempt ejected the failing host, so later
+		// requests skip it.
+		assert.Equal(t, int32(1), failingAttempts.Load())
+	})
+
+	t.Run("error path - every host ejected", func(t *testing.T) {
+		failingServer := newHostServer("failing", http.StatusBadGateway)
+		defer failingServer.Close()
+
+		fiberApp := newApp(t, newRoutes(failingServer.URL, "http://127.0.0.1:99999"), true)
+
+		for range 2 {
+			statusCode, _ := sendRequest(t, fiberApp)
+			assert.Equal(t, fiber.StatusInternalServerError, statusCode)
+		}
+
+		statusCode, body := sendRequest(t, fiberApp)
+		assert.Equal(t, fiber.StatusServiceUnavailable, statusCode)
+		assert.Equal(t, "circuit breaker is open", body)
+	})
+}
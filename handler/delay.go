@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+type delayedWriteLocalKey struct{}
+
+// DelayedWrite tells middlewares when the response of a request has been
+// written. WithDelay writes it on a hijacked connection after the handlers
+// have returned, so they cannot tell by themselves.
+type DelayedWrite struct {
+	mutex   sync.Mutex
+	pending bool
+	written bool
+	then    func()
+}
+
+// TrackDelayedWrite returns the DelayedWrite of the request, for a middleware
+// to call Then on once the handlers have returned. It is kept on the
+// underlying request, so it is seen even when the route is served by another
+// app.
+func TrackDelayedWrite(ctx *fiber.Ctx) *DelayedWrite {
+	delayedWrite := &DelayedWrite{}
+	ctx.Locals(delayedWriteLocalKey{}, delayedWrite)
+
+	return delayedWrite
+}
+
+// Then runs fn once the response has been written or the client has gone
+// away: right away, unless WithDelay holds the response back.
+func (delayedWrite *DelayedWrite) Then(fn func()) {
+	delayedWrite.mutex.Lock()
+	if !delayedWrite.pending || delayedWrite.written {
+		delayedWrite.mutex.Unlock()
+		fn()
+		return
+	}
+	delayedWrite.then = fn
+	delayedWrite.mutex.Unlock()
+}
+
+func (delayedWrite *DelayedWrite) hold() {
+	if delayedWrite == nil {
+		return
+	}
+
+	delayedWrite.mutex.Lock()
+	delayedWrite.pending = true
+	delayedWrite.mutex.Unlock()
+}
+
+func (delayedWrite *DelayedWrite) finish() {
+	if delayedWrite == nil {
+		return
+	}
+
+	delayedWrite.mutex.Lock()
+	delayedWrite.written = true
+	then := delayedWrite.then
+	delayedWrite.then = nil
+	delayedWrite.mutex.Unlock()
+
+	if then != nil {
+		then()
+	}
+}
+
+// WithDelay holds the response written by next back as configured by delay.
+// The wait happens on a hijacked connection, so the Fiber worker is released
+// right away, and it is cancelled as soon as the client disconnects. Delayed
+// responses close the connection. The DelayedWrite of the request, if any, is
+// finished once the response has been written.
+func WithDelay(delay *config.Delay, next fiber.Handler) fiber.Handler {
+	if delay == nil {
+		return next
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		if err := next(ctx); err != nil {
+			return err
+		}
+		if ctx.Context().Hijacked() {
+			return nil
+		}
+
+		wait := DelayDuration(delay)
+		if wait <= 0 && delay.Chunked == nil {
+			return nil
+		}
+
+		response := fasthttp.AcquireResponse()
+		ctx.Response().CopyTo(response)
+		response.SkipBody = ctx.Method() == fiber.MethodHead
+		response.Header.SetContentLength(len(response.Body()))
+		response.SetConnectionClose()
+
+		delayedWrite, _ := ctx.Locals(delayedWriteLocalKey{}).(*DelayedWrite)
+		delayedWrite.hold()
+		hijackConnection(ctx, func(conn net.Conn) {
+			defer fasthttp.ReleaseResponse(response)
+			defer delayedWrite.finish()
+			writeDelayedResponse(conn, response, wait, delay.Chunked)
+		})
+
+		return nil
+	}
+}
+
+// DelayDuration returns the time to wait before the response headers are
+// sent: the fixed delay plus a sample of the distribution.
+func DelayDuration(delay *config.Delay) time.Duration {
+	if delay == nil {
+		return 0
+	}
+
+	wait := time.Duration(delay.FixedMs) * time.Millisecond
+	if delay.Distribution == nil {
+		return wait
+	}
+
+	switch delay.Distribution.Type {
+	case config.DelayDistributionUniform:
+		spread := delay.Distribution.MaxMs - delay.Distribution.MinMs
+		sample := delay.Distribution.MinMs + rand.IntN(spread+1)
+		wait += time.Duration(sample) * time.Millisecond
+	case config.DelayDistributionLogNormal:
+		sample := float64(delay.Distribution.MedianMs) * math.Exp(delay.Distribution.Sigma*rand.NormFloat64())
+		wait += time.Duration(sample * float64(time.Millisecond))
+	}
+
+	return wait
+}
+
+func writeDelayedResponse(conn net.Conn, response *fasthttp.Response, wait time.Duration, chunked *config.ChunkedDelay) {
+	disconnected := make(chan struct{})
+	go func() {
+		// The request has been read already, so the read only returns once
+		// the client goes away or the connection is closed after writing.
+		_, _ = io.Copy(io.Discard, conn)
+		close(disconnected)
+	}()
+	// fasthttp reuses the reader of the connection once this returns, so
+	// the read must have stopped by then.
+	defer func() {
+		_ = conn.SetReadDeadline(time.Now())
+		<-disconnected
+	}()
+
+	if !waitOrDisconnect(wait, disconnected) {
+		return
+	}
+
+	writer := bufio.NewWriter(conn)
+	if chunked == nil || response.SkipBody {
+		if err := response.Write(writer); err == nil {
+			_ = writer.Flush()
+		}
+		return
+	}
+
+	if err := response.Header.Write(writer); err != nil || writer.Flush() != nil {
+		return
+	}
+
+	body := response.Body()
+	interval := time.Duration(chunked.DurationMs) * time.Millisecond / time.Duration(chunked.Chunks)
+	for chunkIndex := range chunked.Chunks {
+		if !waitOrDisconnect(interval, disconnected) {
+			return
+		}
+
+		start := len(body) * chunkIndex / chunked.Chunks
+		end := len(body) * (chunkIndex + 1) / chunked.Chunks
+		if _, err := writer.Write(body[start:end]); err != nil || writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+func waitOrDisconnect(wait time.Duration, disconnected <-chan struct{}) bool {
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-disconnected:
+		return false
+	}
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func startListeningTestServer(t *testing.T, app *fiber.App) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = app.Listener(listener)
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+
+	return fmt.Sprintf("http://%s", listener.Addr().String())
+}
+
+func getListeningTestBody(t *testing.T, url string) (int, string) {
+	t.Helper()
+
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func TestWithDelay(t *testing.T) {
+	sendOK := func(ctx *fiber.Ctx) error {
+		return ctx.Status(fiber.StatusCreated).SendString("delayed body")
+	}
+
+	t.Run("happy path - holds the response back for the fixed delay", func(t *testing.T) {
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Get("/slow", WithDelay(&config.Delay{FixedMs: 150}, sendOK))
+		url := startListeningTestServer(t, app)
+
+		startedAt := time.Now()
+		statusCode, body := getListeningTestBody(t, url+"/slow")
+
+		assert.GreaterOrEqual(t, time.Since(startedAt), 150*time.Millisecond)
+		assert.Equal(t, fiber.StatusCreated, statusCode)
+		assert.Equal(t, "delayed body", body)
+	})
+
+	t.Run("happy path - does not block the worker while waiting", func(t *testing.T) {
+		app := fiber.New(fiber.Config{DisableStartupMessage: true, Concurrency: 1})
+		app.Get("/slow", WithDelay(&config.Delay{FixedMs: 1000}, sendOK))
+		app.Get("/fast", sendOK)
+		url := startListeningTestServer(t, app)
+
+		slowDone := make(chan struct{})
+		go func() {
+			defer close(slowDone)
+			response, err := http.Get(url + "/slow")
+			if err == nil {
+				_ = response.Body.Close()
+			}
+		}()
+		time.Sleep(100 * time.Millisecond)
+
+		startedAt := time.Now()
+		statusCode, _ := getListeningTestBody(t, url+"/fast")
+
+		assert.Equal(t, fiber.StatusCreated, statusCode)
+		assert.Less(t, time.Since(startedAt), 500*time.Millisecond)
+		<-slowDone
+	})
+
+	t.Run("happy path - dribbles the body in chunks", func(t *testing.T) {
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Get("/dribble", WithDelay(&config.Delay{
+			Chunked: &config.ChunkedDelay{Chunks: 4, DurationMs: 200},
+		}, sendOK))
+		url := startListeningTestServer(t, app)
+
+		startedAt := time.Now()
+		statusCode, body := getListeningTestBody(t, url+"/dribble")
+
+		assert.GreaterOrEqual(t, time.Since(startedAt), 200*time.Millisecond)
+		assert.Equal(t, fiber.StatusCreated, statusCode)
+		assert.Equal(t, "delayed body", body)
+	})
+
+	t.Run("happy path - without delay the handler is used as is", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/fast", WithDelay(nil, sendOK))
+
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/fast", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+	})
+
+	t.Run("error path - handler errors are returned without delay", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/fail", WithDelay(&config.Delay{FixedMs: 1000}, func(ctx *fiber.Ctx) error {
+			return fiber.ErrTeapot
+		}))
+
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusTeapot, response.StatusCode)
+	})
+}
+
+// readTrackingConn counts the reads in progress on a connection.
+type readTrackingConn struct {
+	net.Conn
+	reading atomic.Int32
+}
+
+func (conn *readTrackingConn) Read(data []byte) (int, error) {
+	conn.reading.Add(1)
+	defer conn.reading.Add(-1)
+
+	return conn.Conn.Read(data)
+}
+
+func TestWriteDelayedResponse(t *testing.T) {
+	newResponse := func() *fasthttp.Response {
+		response := fasthttp.AcquireResponse()
+		response.SetBodyString("delayed body")
+		response.SetConnectionClose()
+		return response
+	}
+
+	readAll := func(clientConn net.Conn) <-chan string {
+		received := make(chan string, 1)
+		go func() {
+			data, _ := io.ReadAll(clientConn)
+			received <- string(data)
+		}()
+		return received
+	}
+
+	t.Run("happy path - stops reading the connection before returning", func(t *testing.T) {
+		serverConn, clientConn := net.Pipe()
+		defer clientConn.Close()
+		trackedConn := &readTrackingConn{Conn: serverConn}
+		response := newResponse()
+		defer fasthttp.ReleaseResponse(response)
+		received := readAll(clientConn)
+
+		writeDelayedResponse(trackedConn, response, 10*time.Millisecond, nil)
+
+		assert.Zero(t, trackedConn.reading.Load())
+		require.NoError(t, serverConn.Close())
+		assert.Contains(t, <-received, "delayed body")
+	})
+
+	t.Run("happy path - stops reading the connection after a chunked body", func(t *testing.T) {
+		serverConn, clientConn := net.Pipe()
+		defer clientConn.Close()
+		trackedConn := &readTrackingConn{Conn: serverConn}
+		response := newResponse()
+		defer fasthttp.ReleaseResponse(response)
+		received := readAll(clientConn)
+
+		writeDelayedResponse(trackedConn, response, 0, &config.ChunkedDelay{Chunks: 3, DurationMs: 30})
+
+		assert.Zero(t, trackedConn.reading.Load())
+		require.NoError(t, serverConn.Close())
+		assert.Contains(t, <-received, "delayed body")
+	})
+
+	t.Run("error path - stops reading the connection when a write fails", func(t *testing.T) {
+		serverConn, clientConn := net.Pipe()
+		trackedConn := &readTrackingConn{Conn: serverConn}
+		response := newResponse()
+		defer fasthttp.ReleaseResponse(response)
+		require.NoError(t, clientConn.Close())
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			writeDelayedResponse(trackedConn, response, 0, &config.ChunkedDelay{Chunks: 3, DurationMs: 30})
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("delayed response did not return")
+		}
+		assert.Zero(t, trackedConn.reading.Load())
+		_ = serverConn.Close()
+	})
+
+	t.Run("error path - stops waiting when the client disconnects", func(t *testing.T) {
+		serverConn, clientConn := net.Pipe()
+		response := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(response)
+		response.SetBodyString("never sent")
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			writeDelayedResponse(serverConn, response, 10*time.Second, nil)
+		}()
+		require.NoError(t, clientConn.Close())
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("delayed response kept waiting after the client disconnected")
+		}
+		_ = serverConn.Close()
+	})
+}
+
+func TestDelayDuration(t *testing.T) {
+	t.Run("happy path - nil delay", func(t *testing.T) {
+		assert.Zero(t, DelayDuration(nil))
+	})
+
+	t.Run("happy path - fixed delay", func(t *testing.T) {
+		assert.Equal(t, 250*time.Millisecond, DelayDuration(&config.Delay{FixedMs: 250}))
+	})
+
+	t.Run("happy path - uniform delay stays within bounds and adds the fixed delay", func(t *testing.T) {
+		delay := &config.Delay{
+			FixedMs: 100,
+			Distribution: &config.DelayDistribution{
+				Type:  config.DelayDistributionUniform,
+				MinMs: 10,
+				MaxMs: 20,
+			},
+		}
+
+		for range 100 {
+			wait := DelayDuration(delay)
+			assert.GreaterOrEqual(t, wait, 110*time.Millisecond)
+			assert.LessOrEqual(t, wait, 120*time.Millisecond)
+		}
+	})
+
+	t.Run("happy path - log-normal delay without spread is the median", func(t *testing.T) {
+		delay := &config.Delay{
+			Distribution: &config.DelayDistribution{
+				Type:     config.DelayDistributionLogNormal,
+				MedianMs: 80,
+			},
+		}
+
+		assert.Equal(t, 80*time.Millisecond, DelayDuration(delay))
+	})
+
+	t.Run("happy path - log-normal delay is positive", func(t *testing.T) {
+		delay := &config.Delay{
+			Distribution: &config.DelayDistribution{
+				Type:     config.DelayDistributionLogNormal,
+				MedianMs: 80,
+				Sigma:    0.5,
+			},
+		}
+
+		for range 100 {
+			assert.Positive(t, DelayDuration(delay))
+		}
+	})
+}
@@ -9,28 +9,50 @@ import (
 )
 
 type EndpointHandler struct {
-	RouteConfig *[]config.Route
+	RouteConfig       *[]config.Route
+	ResponseTemplates map[int]*ResponseTemplate
 }
 
 func (mockRoute *EndpointHandler) CreateHandler(routeIndex int) func(ctx *fiber.Ctx) error {
+	responseTemplate, isTemplated := mockRoute.ResponseTemplates[routeIndex]
+
 	return func(ctx *fiber.Ctx) error {
 		resp := (*mockRoute.RouteConfig)[routeIndex].FakeResponse
-		ctx = ctx.Status(resp.StatusCode)
-
-		if len(resp.Headers) > 0 {
-			for headerKey, headerValue := range resp.Headers {
-				ctx.Set(headerKey, strings.Join(headerValue, ","))
+		if isTemplated {
+			renderedResp, err := responseTemplate.Render(ctx)
+			if err != nil {
+				return err
 			}
+			resp = renderedResp
 		}
 
-		if len(resp.BodyString) > 0 {
-			return ctx.SendString(resp.BodyString)
-		}
+		return WriteFakeResponse(ctx, resp)
+	}
+}
+
+func WriteFakeResponse(ctx *fiber.Ctx, resp *config.FakeResponse) error {
+	ctx = ctx.Status(resp.StatusCode)
 
-		if len(resp.Body) > 0 {
-			return ctx.JSON(resp.Body)
+	if len(resp.Headers) > 0 {
+		for headerKey, headerValue := range resp.Headers {
+			ctx.Set(headerKey, strings.Join(headerValue, ","))
 		}
+	}
 
-		return nil
+	if len(resp.BodyString) > 0 {
+		return ctx.SendString(resp.BodyString)
 	}
+
+	if resp.Body != nil {
+		return ctx.JSON(resp.Body)
+	}
+
+	if resp.BodyFile != "" {
+		if resp.Headers.Get(fiber.HeaderContentType) == "" {
+			ctx.Set(fiber.HeaderContentType, DetectContentType(resp.BodyFile, resp.BodyFileContent))
+		}
+		return ctx.Send(resp.BodyFileContent)
+	}
+
+	return nil
 }
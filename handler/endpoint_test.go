@@ -97,5 +97,117 @@ func TestMockRoute_CreateRoute(t *testing.T) {
 			assert.Equal(t, "abcd.abcd.abcd", body["token"])
 			assert.Equal(t, "test-header-value", response.Header["X-Test-Header"][0])
 		})
+
+		t.Run("null body", func(t *testing.T) {
+			mockRoute := &EndpointHandler{
+				RouteConfig: &[]config.Route{
+					{
+						Method: fiber.MethodGet,
+						Path:   "/route-one",
+						FakeResponse: &config.FakeResponse{
+							Body:       config.NullBody,
+							StatusCode: 200,
+						},
+					},
+				},
+			}
+			handler := mockRoute.CreateHandler(0)
+
+			fiberApp := fiber.New()
+			fiberApp.Get("/user", handler)
+
+			request := httptest.NewRequest(fiber.MethodGet, "/user", nil)
+			response, err := fiberApp.Test(request)
+			require.NoError(t, err)
+
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, fiber.StatusOK, response.StatusCode)
+			assert.Equal(t, "null", string(responseBody))
+			assert.Equal(t, fiber.MIMEApplicationJSON, response.Header.Get(fiber.HeaderContentType))
+		})
+	})
+}
+
+func TestWriteFakeResponse_BodyFile(t *testing.T) {
+	sendFakeResponse := func(t *testing.T, resp *config.FakeResponse) *http.Response {
+		fiberApp := fiber.New()
+		fiberApp.Get("/file", func(ctx *fiber.Ctx) error {
+			return WriteFakeResponse(ctx, resp)
+		})
+
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/file", nil))
+		require.NoError(t, err)
+
+		return response
+	}
+
+	t.Run("happy path - serves binary content with a detected content type", func(t *testing.T) {
+		content := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0xff}
+		response := sendFakeResponse(t, &config.FakeResponse{
+			StatusCode:      fiber.StatusOK,
+			BodyFile:        "fixtures/logo.png",
+			BodyFileContent: content,
+		})
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "image/png", response.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, content, responseBody)
+	})
+
+	t.Run("happy path - configured content type wins", func(t *testing.T) {
+		response := sendFakeResponse(t, &config.FakeResponse{
+			StatusCode:      fiber.StatusOK,
+			Headers:         http.Header{fiber.HeaderContentType: {"application/x-protobuf"}},
+			BodyFile:        "fixtures/user.bin",
+			BodyFileContent: []byte{0x08, 0x01},
+		})
+
+		assert.Equal(t, "application/x-protobuf", response.Header.Get(fiber.HeaderContentType))
+	})
+}
+
+func TestWriteFakeResponse_Body(t *testing.T) {
+	sendFakeResponse := func(t *testing.T, resp *config.FakeResponse) string {
+		fiberApp := fiber.New()
+		fiberApp.Get("/body", func(ctx *fiber.Ctx) error {
+			return WriteFakeResponse(ctx, resp)
+		})
+
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/body", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.MIMEApplicationJSON, response.Header.Get(fiber.HeaderContentType))
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return string(responseBody)
+	}
+
+	t.Run("happy path - array body", func(t *testing.T) {
+		body := sendFakeResponse(t, &config.FakeResponse{
+			StatusCode: fiber.StatusOK,
+			Body:       []any{map[string]any{"id": 1}, map[string]any{"id": 2}},
+		})
+
+		assert.JSONEq(t, `[{"id":1},{"id":2}]`, body)
+	})
+
+	t.Run("happy path - empty array body", func(t *testing.T) {
+		body := sendFakeResponse(t, &config.FakeResponse{
+			StatusCode: fiber.StatusOK,
+			Body:       []any{},
+		})
+
+		assert.Equal(t, `[]`, body)
+	})
+
+	t.Run("happy path - scalar bodies", func(t *testing.T) {
+		assert.Equal(t, `"ok"`, sendFakeResponse(t, &config.FakeResponse{StatusCode: fiber.StatusOK, Body: "ok"}))
+		assert.Equal(t, `42`, sendFakeResponse(t, &config.FakeResponse{StatusCode: fiber.StatusOK, Body: float64(42)}))
+		assert.Equal(t, `false`, sendFakeResponse(t, &config.FakeResponse{StatusCode: fiber.StatusOK, Body: false}))
 	})
 }
@@ -0,0 +1,10 @@
+package handler
+
+import (
+	"errors"
+)
+
+var (
+	ErrorCircuitBreakerNotFound     = errors.New("circuit breaker record not found")
+	ErrorInvalidCircuitBreakerState = errors.New("circuit breaker state must be closed, open or half-open")
+)
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"bufio"
+	"crypto/tls"
+	"math/rand/v2"
+	"net"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/valyala/fasthttp"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+const malformedResponseSize = 512
+
+var defaultFaultStatusCodes = []int{
+	fiber.StatusInternalServerError,
+	fiber.StatusBadGateway,
+	fiber.StatusServiceUnavailable,
+	fiber.StatusGatewayTimeout,
+}
+
+// WithFaults breaks the response of next with one of faults, picked with a
+// single roll against their probabilities. Faults other than serverError work
+// on the hijacked connection, so middlewares still see a regular response.
+func WithFaults(faults []config.Fault, next fiber.Handler) fiber.Handler {
+	if len(faults) == 0 {
+		return next
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		fault := pickFault(faults, rand.Float64())
+		if fault == nil {
+			return next(ctx)
+		}
+
+		switch fault.Type {
+		case config.FaultServerError:
+			statusCode := pickStatusCode(fault.StatusCodes)
+			return ctx.Status(statusCode).SendString(utils.StatusMessage(statusCode))
+		case config.FaultTruncated:
+			if err := next(ctx); err != nil {
+				return err
+			}
+
+			response := fasthttp.AcquireResponse()
+			ctx.Response().CopyTo(response)
+			hijackConnection(ctx, func(conn net.Conn) {
+				defer fasthttp.ReleaseResponse(response)
+				writeTruncatedResponse(conn, response)
+			})
+		case config.FaultConnectionReset:
+			rawConn := ctx.Context().Conn()
+			hijackConnection(ctx, func(net.Conn) {
+				resetConnection(rawConn)
+			})
+		case config.FaultEmptyReply:
+			hijackConnection(ctx, func(net.Conn) {})
+		case config.FaultMalformed:
+			hijackConnection(ctx, func(conn net.Conn) {
+				_, _ = conn.Write(malformedResponse())
+			})
+		}
+
+		return nil
+	}
+}
+
+func pickFault(faults []config.Fault, roll float64) *config.Fault {
+	var threshold float64
+	for faultIndex := range faults {
+		threshold += faults[faultIndex].Probability
+		if roll < threshold {
+			return &faults[faultIndex]
+		}
+	}
+
+	return nil
+}
+
+func pickStatusCode(statusCodes []int) int {
+	if len(statusCodes) == 0 {
+		statusCodes = defaultFaultStatusCodes
+	}
+
+	return statusCodes[rand.IntN(len(statusCodes))]
+}
+
+// hijackConnection takes the connection over once the handler chain returns.
+// Nothing is written to the client except what handle writes, and the
+// connection is closed when handle returns.
+func hijackConnection(ctx *fiber.Ctx, handle fasthttp.HijackHandler) {
+	ctx.Context().HijackSetNoResponse(true)
+	ctx.Context().Hijack(handle)
+}
+
+// writeTruncatedResponse declares the full body length but sends only half of
+// the body.
+func writeTruncatedResponse(conn net.Conn, response *fasthttp.Response) {
+	body := response.Body()
+	response.Header.SetContentLength(max(len(body), 1))
+
+	writer := bufio.NewWriter(conn)
+	if err := response.Header.Write(writer); err != nil {
+		return
+	}
+	if _, err := writer.Write(body[:len(body)/2]); err != nil {
+		return
+	}
+	_ = writer.Flush()
+}
+
+// resetConnection closes the connection with a TCP RST instead of a FIN when
+// the connection supports it. TLS connections are reset below the TLS layer.
+func resetConnection(conn net.Conn) {
+	if tlsConn, isTLS := conn.(*tls.Conn); isTLS {
+		conn = tlsConn.NetConn()
+	}
+	if lingerConn, ok := conn.(interface{ SetLinger(sec int) error }); ok {
+		_ = lingerConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+func malformedResponse() []byte {
+	garbage := make([]byte, malformedResponseSize)
+	for byteIndex := range garbage {
+		garbage[byteIndex] = byte(rand.IntN(256))
+	}
+
+	return garbage
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestWithFaults(t *testing.T) {
+	sendBody := func(ctx *fiber.Ctx) error {
+		return ctx.Status(fiber.StatusOK).SendString("a complete response body")
+	}
+
+	newFaultServer := func(t *testing.T, fault config.Fault) string {
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Get("/faulty", WithFaults([]config.Fault{fault}, sendBody))
+
+		return startListeningTestServer(t, app) + "/faulty"
+	}
+
+	t.Run("happy path - serverError returns one of the configured status codes", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/faulty", WithFaults([]config.Fault{
+			{Type: config.FaultServerError, Probability: 1, StatusCodes: []int{fiber.StatusBadGateway}},
+		}, sendBody))
+
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/faulty", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusBadGateway, response.StatusCode)
+	})
+
+	t.Run("happy path - serverError defaults to common 5xx codes", func(t *testing.T) {
+		for range 20 {
+			assert.Contains(t, defaultFaultStatusCodes, pickStatusCode(nil))
+		}
+	})
+
+	t.Run("happy path - no fault is picked above the summed probability", func(t *testing.T) {
+		faults := []config.Fault{
+			{Type: config.FaultEmptyReply, Probability: 0.2},
+			{Type: config.FaultServerError, Probability: 0.3},
+		}
+
+		assert.Equal(t, config.FaultEmptyReply, pickFault(faults, 0.1).Type)
+		assert.Equal(t, config.FaultServerError, pickFault(faults, 0.4).Type)
+		assert.Nil(t, pickFault(faults, 0.5))
+	})
+
+	t.Run("happy path - without faults the handler is used as is", func(t *testing.T) {
+		app := fiber.New()
+		app.Get("/faulty", WithFaults(nil, sendBody))
+
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/faulty", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+	})
+
+	t.Run("error path - emptyReply closes the connection without a response", func(t *testing.T) {
+		url := newFaultServer(t, config.Fault{Type: config.FaultEmptyReply, Probability: 1})
+
+		_, err := http.Get(url)
+
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("error path - connectionReset fails the request", func(t *testing.T) {
+		url := newFaultServer(t, config.Fault{Type: config.FaultConnectionReset, Probability: 1})
+
+		_, err := http.Get(url)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error path - malformed sends bytes that are not HTTP", func(t *testing.T) {
+		url := newFaultServer(t, config.Fault{Type: config.FaultMalformed, Probability: 1})
+
+		_, err := http.Get(url)
+
+		assert.ErrorContains(t, err, "malformed HTTP")
+	})
+
+	t.Run("error path - truncated sends less body than declared", func(t *testing.T) {
+		url := newFaultServer(t, config.Fault{Type: config.FaultTruncated, Probability: 1})
+
+		response, err := http.Get(url)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		body, err := io.ReadAll(response.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, int64(len("a complete response body")), response.ContentLength)
+		assert.Equal(t, "a complete", string(body)[:10])
+		assert.Less(t, len(body), len("a complete response body"))
+	})
+}
+
+func TestResetConnection(t *testing.T) {
+	t.Run("happy path - closes the connection below TLS", func(t *testing.T) {
+		serverConn, clientConn := net.Pipe()
+		defer clientConn.Close()
+
+		resetConnection(tls.Server(serverConn, &tls.Config{}))
+
+		_, err := clientConn.Read(make([]byte, 1))
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
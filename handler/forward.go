@@ -0,0 +1,361 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+)
+
+var hopByHopHeaders = []string{
+	fiber.HeaderConnection,
+	fiber.HeaderKeepAlive,
+	fiber.HeaderProxyAuthenticate,
+	fiber.HeaderProxyAuthorization,
+	"Proxy-Connection",
+	fiber.HeaderTE,
+	fiber.HeaderTrailer,
+	fiber.HeaderTransferEncoding,
+	fiber.HeaderUpgrade,
+	fiber.HeaderHost,
+	fiber.HeaderContentLength,
+}
+
+func resolveRequestTo(ctx *fiber.Ctx, requestTo *config.RequestTo) *config.RequestTo {
+	routeParams := ctx.AllParams()
+	if len(routeParams) == 0 {
+		return requestTo
+	}
+
+	resolvedRequestTo := *requestTo
+	resolvedRequestTo.Host = substituteRouteParams(requestTo.Host, routeParams, nil)
+	resolvedRequestTo.Path = substituteRouteParams(requestTo.Path, routeParams, escapePathParam)
+
+	if len(requestTo.Headers) > 0 {
+		resolvedRequestTo.Headers = make(http.Header, len(requestTo.Headers))
+		for headerKey, headerValues := range requestTo.Headers {
+			for _, headerValue := range headerValues {
+				resolvedRequestTo.Headers[headerKey] = append(
+					resolvedRequestTo.Headers[headerKey],
+					substituteRouteParams(headerValue, routeParams, nil),
+				)
+			}
+		}
+	}
+
+	return &resolvedRequestTo
+}
+
+// substituteRouteParams replaces the route params in text, such as :id,
+// :id<int>, :id? and *2, with their values passed through escape, if set.
+// Params without a value are kept as they are.
+func substituteRouteParams(text string, routeParams map[string]string, escape func(string) string) string {
+	var builder strings.Builder
+	for position := 0; position < len(text); {
+		paramName, tokenEnd := scanRouteParam(text, position)
+		paramValue, exists := routeParams[paramName]
+		if tokenEnd == position || !exists {
+			builder.WriteByte(text[position])
+			position++
+			continue
+		}
+
+		if escape != nil {
+			paramValue = escape(paramValue)
+		}
+		builder.WriteString(paramValue)
+		position = tokenEnd
+	}
+
+	return builder.String()
+}
+
+// scanRouteParam reads the route param starting at position and returns its
+// name and where it ends, or position itself when none starts there. The
+// constraint of a named param is skipped up to its matching '>', so
+// constraints holding '<', '>' or parentheses, as regex() ones may, are read
+// whole.
+func scanRouteParam(text string, position int) (string, int) {
+	switch text[position] {
+	case ':':
+		nameEnd := position + 1
+		for nameEnd < len(text) && isRouteParamNameByte(text[nameEnd]) {
+			nameEnd++
+		}
+		if nameEnd == position+1 {
+			return "", position
+		}
+
+		tokenEnd := nameEnd
+		if constraintEnd := scanRouteParamConstraint(text, nameEnd); constraintEnd > 0 {
+			tokenEnd = constraintEnd
+		}
+		if tokenEnd < len(text) && text[tokenEnd] == '?' {
+			tokenEnd++
+		}
+		return text[position+1 : nameEnd], tokenEnd
+	case '*', '+':
+		tokenEnd := position + 1
+		for tokenEnd < len(text) && text[tokenEnd] >= '0' && text[tokenEnd] <= '9' {
+			tokenEnd++
+		}
+		paramIndex := text[position+1 : tokenEnd]
+		if paramIndex == "" {
+			paramIndex = "1"
+		}
+		return text[position:position+1] + paramIndex, tokenEnd
+	}
+
+	return "", position
+}
+
+// scanRouteParamConstraint returns the end of the constraint starting at
+// position, or 0 when there is none or it is not closed.
+func scanRouteParamConstraint(text string, position int) int {
+	if position >= len(text) || text[position] != '<' {
+		return 0
+	}
+
+	var angleDepth, parenDepth int
+	for index := position; index < len(text); index++ {
+		switch text[index] {
+		case '\\':
+			index++
+		case '(':
+			parenDepth++
+		case ')':
+			parenDepth = max(parenDepth-1, 0)
+		case '<':
+			if parenDepth == 0 {
+				angleDepth++
+			}
+		case '>':
+			if parenDepth > 0 {
+				continue
+			}
+			angleDepth--
+			if angleDepth == 0 {
+				return index + 1
+			}
+		}
+	}
+
+	return 0
+}
+
+func isRouteParamNameByte(char byte) bool {
+	return char == '_' ||
+		(char >= 'a' && char <= 'z') ||
+		(char >= 'A' && char <= 'Z') ||
+		(char >= '0' && char <= '9')
+}
+
+// escapePathParam escapes every segment of a route param value that goes
+// into an upstream path, so values holding '?', '#' or spaces cannot change
+// the URL. Segments already escaped by the client are not escaped twice.
+func escapePathParam(paramValue string) string {
+	segments := strings.Split(paramValue, "/")
+	for segmentIndex, segment := range segments {
+		if unescapedSegment, err := url.PathUnescape(segment); err == nil {
+			segment = unescapedSegment
+		}
+		segments[segmentIndex] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func buildForwardHeaders(ctx *fiber.Ctx, requestTo *config.RequestTo) http.Header {
+	headers := make(http.Header)
+
+	if requestTo.PassWithRequestHeaders {
+		for headerKey, headerValues := range ctx.GetReqHeaders() {
+			for _, headerValue := range headerValues {
+				headers.Add(headerKey, headerValue)
+			}
+		}
+		removeHopByHopHeaders(headers)
+	}
+
+	for headerKey, headerValues := range requestTo.Headers {
+		headers.Del(headerKey)
+		headers[headerKey] = append([]string(nil), headerValues...)
+	}
+
+	return headers
+}
+
+// incomingRequest is a copy of a request that outlives its fiber context.
+type incomingRequest struct {
+	method     string
+	requestUri string
+	headers    http.Header
+	body       []byte
+}
+
+// copyIncomingRequest copies the request as it should be sent on, without
+// its hop-by-hop headers and without a body for GET and HEAD.
+func copyIncomingRequest(ctx *fiber.Ctx) incomingRequest {
+	request := ctx.Request()
+	incoming := incomingRequest{
+		method:     string(request.Header.Method()),
+		requestUri: string(request.RequestURI()),
+		headers:    make(http.Header),
+	}
+
+	for key, value := range request.Header.All() {
+		incoming.headers.Add(string(key), string(value))
+	}
+	removeHopByHopHeaders(incoming.headers)
+
+	if incoming.method != fiber.MethodGet && incoming.method != fiber.MethodHead {
+		incoming.body = append([]byte(nil), request.Body()...)
+	}
+
+	return incoming
+}
+
+func removeHopByHopHeaders(headers http.Header) {
+	for _, connectionValue := range headers.Values(fiber.HeaderConnection) {
+		for _, connectionToken := range strings.Split(connectionValue, ",") {
+			if connectionToken = strings.TrimSpace(connectionToken); connectionToken != "" {
+				headers.Del(connectionToken)
+			}
+		}
+	}
+
+	for _, headerKey := range hopByHopHeaders {
+		headers.Del(headerKey)
+	}
+}
+
+// errUnmergeableRequestBody is returned when requestTo.body has to be merged
+// into an incoming body that is not a JSON object.
+var errUnmergeableRequestBody = errors.New("request body is not a JSON object, requestTo.body cannot be merged into it")
+
+// buildForwardBody returns the body to send upstream: requestTo.body, the
+// incoming body when passWithRequestBody is set, or both merged when both are
+// JSON objects.
+func buildForwardBody(ctx *fiber.Ctx, requestTo *config.RequestTo) ([]byte, error) {
+	incomingBody := append([]byte(nil), ctx.Body()...)
+	if !requestTo.PassWithRequestBody || len(incomingBody) == 0 {
+		return marshalRequestBody(requestTo.Body)
+	}
+
+	if len(requestTo.Body) == 0 {
+		return incomingBody, nil
+	}
+
+	var mergedBody map[string]any
+	if err := json.Unmarshal(incomingBody, &mergedBody); err != nil || mergedBody == nil {
+		return nil, errUnmergeableRequestBody
+	}
+
+	for bodyKey, bodyValue := range requestTo.Body {
+		mergedBody[bodyKey] = bodyValue
+	}
+
+	return json.Marshal(mergedBody)
+}
+
+func marshalRequestBody(body config.HttpBody) ([]byte, error) {
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(body)
+}
+
+func buildUpstreamUrl(ctx *fiber.Ctx, requestTo *config.RequestTo) (string, error) {
+	parsedUrl, err := requestTo.GetParsedUrl()
+	if err != nil {
+		return "", err
+	}
+
+	queryRewrite := requestTo.Query
+	if queryRewrite == nil {
+		queryRewrite = &config.QueryRewrite{}
+	}
+
+	queryValues := make(url.Values)
+	if !queryRewrite.DropIncoming {
+		for queryKey, queryValue := range ctx.Context().QueryArgs().All() {
+			queryValues.Add(string(queryKey), string(queryValue))
+		}
+	}
+
+	for queryKey, configuredValues := range parsedUrl.Query() {
+		queryValues[queryKey] = configuredValues
+	}
+
+	for _, queryKey := range queryRewrite.Remove {
+		queryValues.Del(queryKey)
+	}
+
+	for queryKey, queryValue := range queryRewrite.Set {
+		queryValues.Set(queryKey, queryValue)
+	}
+
+	parsedUrl.RawQuery = queryValues.Encode()
+
+	return parsedUrl.String(), nil
+}
+
+func buildRequestOptions(requestTo *config.RequestTo) httpPkg.RequestOptions {
+	requestOptions := httpPkg.RequestOptions{
+		Timeout: time.Duration(requestTo.TimeoutMs) * time.Millisecond,
+	}
+
+	if retryPolicy := requestTo.Retry; retryPolicy != nil {
+		requestOptions.Retry = &httpPkg.RetryConfig{
+			MaxRetries:         max(retryPolicy.MaxAttempts-1, 0),
+			InitialBackoff:     time.Duration(retryPolicy.InitialBackoffMs) * time.Millisecond,
+			MaxBackoff:         time.Duration(retryPolicy.MaxBackoffMs) * time.Millisecond,
+			BackoffMultiplier:  retryPolicy.BackoffMultiplier,
+			Jitter:             retryPolicy.Jitter,
+			RetryOnStatusCodes: retryPolicy.RetryOn,
+			IdempotentOnly:     retryPolicy.IdempotentOnly == nil || *retryPolicy.IdempotentOnly,
+		}
+	}
+
+	return requestOptions
+}
+
+func writeUpstreamHeaders(ctx *fiber.Ctx, upstreamHeaders http.Header, headerFilter *config.HeaderFilter) {
+	headers := upstreamHeaders.Clone()
+	removeHopByHopHeaders(headers)
+
+	for headerKey, headerValues := range headers {
+		if !isHeaderAllowed(headerKey, headerFilter) {
+			continue
+		}
+
+		for _, headerValue := range headerValues {
+			ctx.Response().Header.Add(headerKey, headerValue)
+		}
+	}
+}
+
+func isHeaderAllowed(headerKey string, headerFilter *config.HeaderFilter) bool {
+	if headerFilter == nil {
+		return true
+	}
+
+	sameHeader := func(filterKey string) bool {
+		return strings.EqualFold(filterKey, headerKey)
+	}
+
+	if slices.ContainsFunc(headerFilter.Deny, sameHeader) {
+		return false
+	}
+
+	return len(headerFilter.Allow) == 0 || slices.ContainsFunc(headerFilter.Allow, sameHeader)
+}
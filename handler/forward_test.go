@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+)
+
+func runWithCtx(t *testing.T, request *http.Request, handlerFn func(ctx *fiber.Ctx)) {
+	t.Helper()
+
+	fiberApp := fiber.New()
+	fiberApp.All("/*", func(ctx *fiber.Ctx) error {
+		handlerFn(ctx)
+		return nil
+	})
+
+	_, err := fiberApp.Test(request)
+	require.NoError(t, err)
+}
+
+func TestBuildForwardHeaders(t *testing.T) {
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(fiber.MethodPost, "/users", nil)
+		request.Header.Set("X-Request-Id", "abc")
+		request.Header.Set("X-Tenant", "from-request")
+		request.Header.Set("Connection", "keep-alive, X-Hop")
+		request.Header.Set("X-Hop", "secret")
+		request.Header.Set("Keep-Alive", "timeout=5")
+		request.Header.Set("Proxy-Authorization", "Basic abc")
+		return request
+	}
+
+	t.Run("happy path - only static headers when pass through is disabled", func(t *testing.T) {
+		var headers http.Header
+		runWithCtx(t, newRequest(), func(ctx *fiber.Ctx) {
+			headers = buildForwardHeaders(ctx, &config.RequestTo{
+				Headers: http.Header{"X-Static": {"static"}},
+			})
+		})
+
+		assert.Equal(t, http.Header{"X-Static": {"static"}}, headers)
+	})
+
+	t.Run("happy path - forwards request headers without hop-by-hop headers", func(t *testing.T) {
+		var headers http.Header
+		runWithCtx(t, newRequest(), func(ctx *fiber.Ctx) {
+			headers = buildForwardHeaders(ctx, &config.RequestTo{
+				PassWithRequestHeaders: true,
+				Headers: http.Header{
+					"X-Tenant": {"from-config"},
+					"xStatic":  {"static"},
+				},
+			})
+		})
+
+		assert.Equal(t, "abc", headers.Get("X-Request-Id"))
+		assert.Equal(t, []string{"from-config"}, headers.Values("X-Tenant"))
+		assert.Equal(t, []string{"static"}, headers["xStatic"])
+		for _, headerKey := range []string{"Connection", "X-Hop", "Keep-Alive", "Proxy-Authorization", "Host", "Content-Length"} {
+			assert.Empty(t, headers.Get(headerKey), headerKey)
+		}
+	})
+}
+
+func TestBuildForwardBody(t *testing.T) {
+	testCases := []struct {
+		name        string
+		requestBody string
+		requestTo   *config.RequestTo
+		expected    string
+	}{
+		{
+			name:        "static body when pass through is disabled",
+			requestBody: `{"name":"incoming"}`,
+			requestTo:   &config.RequestTo{Body: config.HttpBody{"name": "static"}},
+			expected:    `{"name":"static"}`,
+		},
+		{
+			name:        "no body when nothing is configured",
+			requestBody: `{"name":"incoming"}`,
+			requestTo:   &config.RequestTo{},
+			expected:    "",
+		},
+		{
+			name:        "incoming body as is without static body",
+			requestBody: `{"name":"incoming"}`,
+			requestTo:   &config.RequestTo{PassWithRequestBody: true},
+			expected:    `{"name":"incoming"}`,
+		},
+		{
+			name:        "static body when the request has no body",
+			requestBody: "",
+			requestTo:   &config.RequestTo{PassWithRequestBody: true, Body: config.HttpBody{"name": "static"}},
+			expected:    `{"name":"static"}`,
+		},
+		{
+			name:        "static fields override incoming object fields",
+			requestBody: `{"name":"incoming","id":1}`,
+			requestTo:   &config.RequestTo{PassWithRequestBody: true, Body: config.HttpBody{"name": "static"}},
+			expected:    `{"id":1,"name":"static"}`,
+		},
+		{
+			name:        "non object bodies are passed as is without static body",
+			requestBody: `plain text`,
+			requestTo:   &config.RequestTo{PassWithRequestBody: true},
+			expected:    `plain text`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var (
+				body []byte
+				err  error
+			)
+			request := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(testCase.requestBody))
+			runWithCtx(t, request, func(ctx *fiber.Ctx) {
+				body, err = buildForwardBody(ctx, testCase.requestTo)
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, string(body))
+		})
+	}
+
+	t.Run("error path - static body with a non object incoming body", func(t *testing.T) {
+		requestTo := &config.RequestTo{PassWithRequestBody: true, Body: config.HttpBody{"name": "static"}}
+
+		for _, requestBody := range []string{`plain text`, `[1,2]`} {
+			var err error
+			request := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(requestBody))
+			runWithCtx(t, request, func(ctx *fiber.Ctx) {
+				_, err = buildForwardBody(ctx, requestTo)
+			})
+
+			assert.ErrorIs(t, err, errUnmergeableRequestBody, requestBody)
+		}
+	})
+}
+
+func TestBuildUpstreamUrl(t *testing.T) {
+	testCases := []struct {
+		name      string
+		target    string
+		requestTo *config.RequestTo
+		expected  string
+	}{
+		{
+			name:      "without query string",
+			target:    "/users",
+			requestTo: &config.RequestTo{Host: "http://localhost:8081", Path: "/v2/users"},
+			expected:  "http://localhost:8081/v2/users",
+		},
+		{
+			name:      "forwards the incoming query string",
+			target:    "/users?page=2&tag=a&tag=b",
+			requestTo: &config.RequestTo{Host: "http://localhost:8081", Path: "/v2/users"},
+			expected:  "http://localhost:8081/v2/users?page=2&tag=a&tag=b",
+		},
+		{
+			name:      "configured path query overrides incoming values",
+			target:    "/users?page=2&limit=5",
+			requestTo: &config.RequestTo{Host: "http://localhost:8081", Path: "/v2/users?limit=10"},
+			expected:  "http://localhost:8081/v2/users?limit=10&page=2",
+		},
+		{
+			name:   "rewrites the query string",
+			target: "/users?page=2&debug=true",
+			requestTo: &config.RequestTo{
+				Host: "http://localhost:8081",
+				Path: "/v2/users",
+				Query: &config.QueryRewrite{
+					Set:    map[string]string{"page": "1", "source": "inzibat"},
+					Remove: []string{"debug"},
+				},
+			},
+			expected: "http://localhost:8081/v2/users?page=1&source=inzibat",
+		},
+		{
+			name:   "drops the incoming query string",
+			target: "/users?page=2",
+			requestTo: &config.RequestTo{
+				Host:  "http://localhost:8081",
+				Path:  "/v2/users",
+				Query: &config.QueryRewrite{DropIncoming: true},
+			},
+			expected: "http://localhost:8081/v2/users",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var (
+				upstreamUrl string
+				err         error
+			)
+			runWithCtx(t, httptest.NewRequest(fiber.MethodGet, testCase.target, nil), func(ctx *fiber.Ctx) {
+				upstreamUrl, err = buildUpstreamUrl(ctx, testCase.requestTo)
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, upstreamUrl)
+		})
+	}
+}
+
+func TestBuildRequestOptions(t *testing.T) {
+	t.Run("happy path - keeps client defaults without timeout and retry", func(t *testing.T) {
+		requestOptions := buildRequestOptions(&config.RequestTo{})
+
+		assert.Equal(t, httpPkg.RequestOptions{}, requestOptions)
+	})
+
+	t.Run("happy path - converts timeout and retry policy", func(t *testing.T) {
+		requestOptions := buildRequestOptions(&config.RequestTo{
+			TimeoutMs: 750,
+			Retry: &config.RetryPolicy{
+				MaxAttempts:       3,
+				InitialBackoffMs:  20,
+				MaxBackoffMs:      400,
+				BackoffMultiplier: 3,
+				Jitter:            0.1,
+				RetryOn:           []int{http.StatusTooManyRequests},
+				IdempotentOnly:    config.BoolPointer(false),
+			},
+		})
+
+		assert.Equal(t, 750*time.Millisecond, requestOptions.Timeout)
+		assert.Equal(t, &httpPkg.RetryConfig{
+			MaxRetries:         2,
+			InitialBackoff:     20 * time.Millisecond,
+			MaxBackoff:         400 * time.Millisecond,
+			BackoffMultiplier:  3,
+			Jitter:             0.1,
+			RetryOnStatusCodes: []int{http.StatusTooManyRequests},
+			IdempotentOnly:     false,
+		}, requestOptions.Retry)
+	})
+
+	t.Run("happy path - retries idempotent methods only unless told otherwise", func(t *testing.T) {
+		requestOptions := buildRequestOptions(&config.RequestTo{
+			Retry: &config.RetryPolicy{MaxAttempts: 1},
+		})
+
+		assert.Equal(t, 0, requestOptions.Retry.MaxRetries)
+		assert.True(t, requestOptions.Retry.IdempotentOnly)
+	})
+}
+
+func TestIsHeaderAllowed(t *testing.T) {
+	assert.True(t, isHeaderAllowed("X-Anything", nil))
+
+	denyOnly := &config.HeaderFilter{Deny: []string{"set-cookie"}}
+	assert.False(t, isHeaderAllowed("Set-Cookie", denyOnly))
+	assert.True(t, isHeaderAllowed("Content-Type", denyOnly))
+
+	allowAndDeny := &config.HeaderFilter{
+		Allow: []string{"Content-Type", "Cache-Control"},
+		Deny:  []string{"Cache-Control"},
+	}
+	assert.True(t, isHeaderAllowed("content-type", allowAndDeny))
+	assert.False(t, isHeaderAllowed("Cache-Control", allowAndDeny))
+	assert.False(t, isHeaderAllowed("ETag", allowAndDeny))
+}
+
+func TestSubstituteRouteParams(t *testing.T) {
+	routeParams := map[string]string{
+		"id":     "42",
+		"org_id": "acme",
+		"*1":     "files/report.pdf",
+		"*2":     "second",
+		"+1":     "a/b",
+	}
+
+	testCases := map[string]string{
+		"/v2/users/:id":                "/v2/users/42",
+		"/v2/orgs/:org_id/users/:id":   "/v2/orgs/acme/users/42",
+		`/v2/users/:id<regex(\d+)>`:    "/v2/users/42",
+		`/v2/users/:id<regex(a>b)>/x`:  "/v2/users/42/x",
+		`/v2/users/:id<regex(\>)>/x`:   "/v2/users/42/x",
+		`/v2/users/:id<min(1);max(9)>`: "/v2/users/42",
+		"/v2/users/:id?":               "/v2/users/42",
+		"/static/*":                    "/static/files/report.pdf",
+		"/static/*1/*2":                "/static/files/report.pdf/second",
+		"/plus/+":                      "/plus/a/b",
+		"/v2/users/:unknown":           "/v2/users/:unknown",
+		"http://localhost:8081/:id":    "http://localhost:8081/42",
+		"http://:org_id.internal:8081": "http://acme.internal:8081",
+		"Bearer :id":                   "Bearer 42",
+	}
+
+	for text, expected := range testCases {
+		assert.Equal(t, expected, substituteRouteParams(text, routeParams, nil), text)
+	}
+
+	t.Run("happy path - escapes path values", func(t *testing.T) {
+		escapedParams := map[string]string{
+			"id": "a b?c#d",
+			"*1": "dir/file name.txt",
+			"+1": "a%2Fb/c",
+		}
+
+		assert.Equal(t, "/users/a%20b%3Fc%23d", substituteRouteParams("/users/:id", escapedParams, escapePathParam))
+		assert.Equal(t, "/files/dir/file%20name.txt", substituteRouteParams("/files/*", escapedParams, escapePathParam))
+		assert.Equal(t, "/plus/a%2Fb/c", substituteRouteParams("/plus/+", escapedParams, escapePathParam))
+	})
+}
+
+func TestResolveRequestTo(t *testing.T) {
+	requestTo := &config.RequestTo{
+		Host: "http://:tenant.localhost:8081",
+		Path: "/v2/users/:id/*",
+		Headers: http.Header{
+			"X-User-Id": {":id"},
+		},
+	}
+
+	t.Run("happy path - substitutes route params into host, path and headers", func(t *testing.T) {
+		var resolvedRequestTo *config.RequestTo
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/:tenant/users/:id/*", func(ctx *fiber.Ctx) error {
+			resolvedRequestTo = resolveRequestTo(ctx, requestTo)
+			return nil
+		})
+
+		_, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/acme/users/42/avatar/large", nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://acme.localhost:8081", resolvedRequestTo.Host)
+		assert.Equal(t, "/v2/users/42/avatar/large", resolvedRequestTo.Path)
+		assert.Equal(t, "42", resolvedRequestTo.Headers.Get("X-User-Id"))
+		assert.Equal(t, "/v2/users/:id/*", requestTo.Path)
+	})
+
+	t.Run("happy path - returns the original config without route params", func(t *testing.T) {
+		var resolvedRequestTo *config.RequestTo
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/users", func(ctx *fiber.Ctx) error {
+			resolvedRequestTo = resolveRequestTo(ctx, requestTo)
+			return nil
+		})
+
+		_, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/users", nil))
+		require.NoError(t, err)
+
+		assert.Same(t, requestTo, resolvedRequestTo)
+	})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+)
+
+// MirrorHeader is set on mirrored requests so shadow upstreams can tell them
+// apart from live traffic.
+const MirrorHeader = "X-Inzibat-Mirror"
+
+// RequestMirror sends copies of the requests of a route to its shadow hosts.
+type RequestMirror struct {
+	client     *httpPkg.Client
+	config     config.Mirror
+	percentage float64
+	slots      chan struct{}
+	sample     func() float64
+}
+
+func NewRequestMirror(client *httpPkg.Client, mirrorConfig config.Mirror) *RequestMirror {
+	percentage := float64(config.DefaultMirrorPercentage)
+	if mirrorConfig.Percentage != nil {
+		percentage = *mirrorConfig.Percentage
+	}
+
+	return &RequestMirror{
+		client:     client,
+		config:     mirrorConfig,
+		percentage: percentage,
+		slots:      make(chan struct{}, max(mirrorConfig.MaxConcurrency, 1)),
+		sample:     rand.Float64,
+	}
+}
+
+// BuildRequestMirrors returns a mirror for every route with one, keyed by
+// route index.
+func BuildRequestMirrors(client *httpPkg.Client, routes []config.Route) map[int]*RequestMirror {
+	mirrors := make(map[int]*RequestMirror)
+	for routeIndex, route := range routes {
+		if route.Mirror == nil {
+			continue
+		}
+
+		mirrors[routeIndex] = NewRequestMirror(client, *route.Mirror)
+	}
+
+	return mirrors
+}
+
+// WithMirror sends a copy of the request to the shadow hosts of mirror, then
+// lets next answer it without waiting for them.
+func WithMirror(mirror *RequestMirror, next fiber.Handler) fiber.Handler {
+	if mirror == nil {
+		return next
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		mirror.Send(ctx)
+		return next(ctx)
+	}
+}
+
+// Send copies the request and sends it to every shadow host in the
+// background. Nothing is sent when the request is not sampled, and a host is
+// skipped when every slot is taken.
+func (mirror *RequestMirror) Send(ctx *fiber.Ctx) {
+	if mirror.sample()*100 >= mirror.percentage {
+		return
+	}
+
+	incoming := copyIncomingRequest(ctx)
+	incoming.headers.Set(MirrorHeader, "true")
+
+	requestOptions := httpPkg.RequestOptions{
+		Timeout: time.Duration(mirror.config.TimeoutMs) * time.Millisecond,
+		Retry:   &httpPkg.RetryConfig{},
+	}
+	for _, host := range mirror.config.Hosts {
+		select {
+		case mirror.slots <- struct{}{}:
+		default:
+			continue
+		}
+
+		go func(uri string) {
+			defer func() { <-mirror.slots }()
+			_, _ = mirror.client.DoWithOptions(incoming.method, uri, incoming.headers, incoming.body, requestOptions)
+		}(host + incoming.requestUri)
+	}
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+)
+
+type mirroredRequest struct {
+	method     string
+	requestUri string
+	header     http.Header
+	body       string
+}
+
+func TestWithMirror(t *testing.T) {
+	newApp := func(mirror *RequestMirror) *fiber.App {
+		fiberApp := fiber.New()
+		fiberApp.Post("/users", WithMirror(mirror, func(ctx *fiber.Ctx) error {
+			return ctx.Status(fiber.StatusCreated).SendString("created")
+		}))
+		return fiberApp
+	}
+
+	newRequest := func() *http.Request {
+		request := httptest.NewRequest(http.MethodPost, "/users?source=test", strings.NewReader(`{"name":"inzibat"}`))
+		request.Header.Set("X-Request-Id", "42")
+		request.Header.Set(fiber.HeaderConnection, "keep-alive")
+		return request
+	}
+
+	t.Run("happy path - sends a copy of the request to every shadow host", func(t *testing.T) {
+		received := make(chan mirroredRequest, 2)
+		shadowHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- mirroredRequest{method: r.Method, requestUri: r.RequestURI, header: r.Header, body: string(body)}
+		})
+		firstShadow := httptest.NewServer(shadowHandler)
+		defer firstShadow.Close()
+		secondShadow := httptest.NewServer(shadowHandler)
+		defer secondShadow.Close()
+
+		mirror := NewRequestMirror(httpPkg.NewHttpClient(), config.Mirror{
+			Hosts:          []string{firstShadow.URL, secondShadow.URL},
+			Percentage:     config.Float64Pointer(100),
+			MaxConcurrency: 2,
+			TimeoutMs:      1000,
+		})
+
+		response, err := newApp(mirror).Test(newRequest())
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+
+		for range 2 {
+			select {
+			case request := <-received:
+				assert.Equal(t, http.MethodPost, request.method)
+				assert.Equal(t, "/users?source=test", request.requestUri)
+				assert.Equal(t, `{"name":"inzibat"}`, request.body)
+				assert.Equal(t, "42", request.header.Get("X-Request-Id"))
+				assert.Equal(t, "true", request.header.Get(MirrorHeader))
+			case <-time.After(2 * time.Second):
+				t.Fatal("mirrored request was not received")
+			}
+		}
+	})
+
+	t.Run("happy path - skips requests that are not sampled", func(t *testing.T) {
+		var shadowCalls atomic.Int32
+		shadowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			shadowCalls.Add(1)
+		}))
+		defer shadowServer.Close()
+
+		mirror := NewRequestMirror(httpPkg.NewHttpClient(), config.Mirror{
+			Hosts:          []string{shadowServer.URL},
+			Percentage:     config.Float64Pointer(30),
+			MaxConcurrency: 1,
+			TimeoutMs:      1000,
+		})
+		mirror.sample = func() float64 { return 0.5 }
+
+		response, err := newApp(mirror).Test(newRequest())
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(0), shadowCalls.Load())
+	})
+
+	t.Run("happy path - mirrors nothing at a percentage of zero", func(t *testing.T) {
+		var shadowCalls atomic.Int32
+		shadowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			shadowCalls.Add(1)
+		}))
+		defer shadowServer.Close()
+
+		mirror := NewRequestMirror(httpPkg.NewHttpClient(), config.Mirror{
+			Hosts:          []string{shadowServer.URL},
+			Percentage:     config.Float64Pointer(0),
+			MaxConcurrency: 1,
+			TimeoutMs:      1000,
+		})
+		mirror.sample = func() float64 { return 0 }
+
+		response, err := newApp(mirror).Test(newRequest())
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(0), shadowCalls.Load())
+	})
+
+	t.Run("happy path - drops copies over the concurrency limit without waiting", func(t *testing.T) {
+		var shadowCalls atomic.Int32
+		release := make(chan struct{})
+		shadowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			shadowCalls.Add(1)
+			<-release
+		}))
+		defer shadowServer.Close()
+		defer close(release)
+
+		mirror := NewRequestMirror(httpPkg.NewHttpClient(), config.Mirror{
+			Hosts:          []string{shadowServer.URL},
+			Percentage:     config.Float64Pointer(100),
+			MaxConcurrency: 1,
+			TimeoutMs:      5000,
+		})
+		fiberApp := newApp(mirror)
+
+		start := time.Now()
+		for range 3 {
+			response, err := fiberApp.Test(newRequest())
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+		}
+		assert.Less(t, time.Since(start), time.Second)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, int32(1), shadowCalls.Load())
+	})
+
+	t.Run("happy path - without a mirror the handler is unchanged", func(t *testing.T) {
+		response, err := newApp(nil).Test(newRequest())
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusCreated, response.StatusCode)
+	})
+}
+
+func TestBuildRequestMirrors(t *testing.T) {
+	t.Run("happy path - builds mirrors for routes with one only", func(t *testing.T) {
+		routes := []config.Route{
+			{Method: http.MethodGet, Path: "/plain", FakeResponse: &config.FakeResponse{StatusCode: http.StatusOK}},
+			{
+				Method:       http.MethodGet,
+				Path:         "/mirrored",
+				FakeResponse: &config.FakeResponse{StatusCode: http.StatusOK},
+				Mirror:       &config.Mirror{Hosts: []string{"http://shadow:8080"}, MaxConcurrency: 4},
+			},
+		}
+
+		mirrors := BuildRequestMirrors(httpPkg.NewHttpClient(), routes)
+
+		require.Len(t, mirrors, 1)
+		assert.Equal(t, 4, cap(mirrors[1].slots))
+	})
+}
@@ -1,8 +1,7 @@
 package handler
 
-import "github.com/lynicis/inzibat/config"
-
 type RouteChannel struct {
-	RouteIndex int
-	Route      config.Route
+	Method       string
+	Path         string
+	RouteIndexes []int
 }
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+)
+
+// NewPassThroughHandler answers every request with the response of the same
+// request sent to upstream, 5xx responses included. It is meant for requests
+// no route matched, so it never calls the next handler.
+func NewPassThroughHandler(client *httpPkg.Client, upstream string) fiber.Handler {
+	upstream = strings.TrimSuffix(upstream, "/")
+	requestOptions := httpPkg.RequestOptions{
+		Retry:            &httpPkg.RetryConfig{},
+		KeepServerErrors: true,
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		incoming := copyIncomingRequest(ctx)
+		response, err := client.DoWithOptions(
+			incoming.method,
+			upstream+incoming.requestUri,
+			incoming.headers,
+			incoming.body,
+			requestOptions,
+		)
+		if err != nil {
+			return ctx.
+				Status(fiber.StatusBadGateway).
+				SendString(err.Error())
+		}
+
+		writeUpstreamHeaders(ctx, response.Headers, nil)
+
+		return ctx.
+			Status(response.Status).
+			Send(response.Body)
+	}
+}
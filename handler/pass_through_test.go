@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	httpPkg "github.com/lynicis/inzibat/client/http"
+)
+
+func TestNewPassThroughHandler(t *testing.T) {
+	newApp := func(upstream string) *fiber.App {
+		fiberApp := fiber.New()
+		fiberApp.Use(NewPassThroughHandler(httpPkg.NewHttpClient(), upstream))
+		return fiberApp
+	}
+
+	t.Run("happy path - answers with the upstream response", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("X-Upstream-Method", r.Method)
+			w.Header().Set("X-Upstream-Request-Id", r.Header.Get("X-Request-Id"))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(r.RequestURI + " " + string(body)))
+		}))
+		defer upstream.Close()
+
+		request := httptest.NewRequest(http.MethodPost, "/users?source=test", strings.NewReader(`{"name":"inzibat"}`))
+		request.Header.Set("X-Request-Id", "42")
+
+		response, err := newApp(upstream.URL + "/").Test(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, `/users?source=test {"name":"inzibat"}`, string(body))
+		assert.Equal(t, http.MethodPost, response.Header.Get("X-Upstream-Method"))
+		assert.Equal(t, "42", response.Header.Get("X-Upstream-Request-Id"))
+	})
+
+	t.Run("happy path - passes upstream server errors through", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("maintenance"))
+		}))
+		defer upstream.Close()
+
+		response, err := newApp(upstream.URL).Test(httptest.NewRequest(http.MethodGet, "/status", nil))
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		body, _ := io.ReadAll(response.Body)
+		assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+		assert.Equal(t, "maintenance", string(body))
+	})
+
+	t.Run("error path - answers bad gateway when the upstream is unreachable", func(t *testing.T) {
+		upstream := httptest.NewServer(http.NotFoundHandler())
+		upstream.Close()
+
+		response, err := newApp(upstream.URL).Test(httptest.NewRequest(http.MethodGet, "/status", nil))
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		assert.Equal(t, http.StatusBadGateway, response.StatusCode)
+	})
+}
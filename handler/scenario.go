@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+const ScenarioStateStarted = "Started"
+
+type ScenarioStore struct {
+	mu                sync.Mutex
+	states            map[string]string
+	sequencePositions map[string]int
+}
+
+func NewScenarioStore() *ScenarioStore {
+	return &ScenarioStore{
+		states:            make(map[string]string),
+		sequencePositions: make(map[string]int),
+	}
+}
+
+func (store *ScenarioStore) Seed(routes []config.Route) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, route := range routes {
+		if route.Scenario == nil {
+			continue
+		}
+
+		if _, exists := store.states[route.Scenario.Name]; !exists {
+			store.states[route.Scenario.Name] = ScenarioStateStarted
+		}
+	}
+}
+
+func (store *ScenarioStore) Enter(scenario *config.RouteScenario) bool {
+	if scenario == nil {
+		return true
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	currentState := store.stateLocked(scenario.Name)
+	if scenario.RequiredState != "" && scenario.RequiredState != currentState {
+		return false
+	}
+
+	if scenario.NewState != "" {
+		store.states[scenario.Name] = scenario.NewState
+	}
+
+	return true
+}
+
+func (store *ScenarioStore) State(name string) string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.stateLocked(name)
+}
+
+func (store *ScenarioStore) States() map[string]string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	states := make(map[string]string, len(store.states))
+	for name, state := range store.states {
+		states[name] = state
+	}
+
+	return states
+}
+
+func (store *ScenarioStore) SetState(name string, state string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.states[name] = state
+}
+
+func (store *ScenarioStore) Reset(name string) {
+	store.SetState(name, ScenarioStateStarted)
+}
+
+func (store *ScenarioStore) ResetAll() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for name := range store.states {
+		store.states[name] = ScenarioStateStarted
+	}
+	store.sequencePositions = make(map[string]int)
+}
+
+func (store *ScenarioStore) NextSequenceIndex(sequenceKey string, sequence *config.ResponseSequence) int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	responseCount := len(sequence.Responses)
+	position := store.sequencePositions[sequenceKey]
+	store.sequencePositions[sequenceKey] = position + 1
+
+	if sequence.Mode == config.SequenceModeCycle {
+		return position % responseCount
+	}
+
+	return min(position, responseCount-1)
+}
+
+// RetainSequences drops the positions of every sequence but those under
+// sequenceKeys, so routes that are gone do not keep theirs.
+func (store *ScenarioStore) RetainSequences(sequenceKeys []string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for sequenceKey := range store.sequencePositions {
+		if !slices.Contains(sequenceKeys, sequenceKey) {
+			delete(store.sequencePositions, sequenceKey)
+		}
+	}
+}
+
+func (store *ScenarioStore) stateLocked(name string) string {
+	state, exists := store.states[name]
+	if !exists {
+		return ScenarioStateStarted
+	}
+
+	return state
+}
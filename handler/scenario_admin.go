@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+type scenarioStateRequest struct {
+	State string `json:"state"`
+}
+
+func RegisterScenarioAdminRoutes(app *fiber.App, store *ScenarioStore) {
+	group := app.Group("/_inzibat/scenarios")
+
+	group.Get("/", listScenariosHandler(store))
+	group.Post("/reset", resetAllScenariosHandler(store))
+	group.Put("/:name/state", setScenarioStateHandler(store))
+	group.Post("/:name/reset", resetScenarioHandler(store))
+}
+
+func listScenariosHandler(store *ScenarioStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(store.States())
+	}
+}
+
+func resetAllScenariosHandler(store *ScenarioStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		store.ResetAll()
+		return ctx.JSON(fiber.Map{
+			"message": "all scenarios and sequences reset",
+		})
+	}
+}
+
+func setScenarioStateHandler(store *ScenarioStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var request scenarioStateRequest
+		if err := ctx.BodyParser(&request); err != nil || request.State == "" {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "request body must contain a non-empty state",
+			})
+		}
+
+		name := ctx.Params("name")
+		store.SetState(name, request.State)
+
+		return ctx.JSON(fiber.Map{
+			"name":  name,
+			"state": request.State,
+		})
+	}
+}
+
+func resetScenarioHandler(store *ScenarioStore) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		name := ctx.Params("name")
+		store.Reset(name)
+
+		return ctx.JSON(fiber.Map{
+			"name":  name,
+			"state": ScenarioStateStarted,
+		})
+	}
+}
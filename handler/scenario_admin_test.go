@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func setupScenarioAdminApp() (*fiber.App, *ScenarioStore) {
+	store := NewScenarioStore()
+	app := fiber.New(fiber.Config{
+		JSONEncoder: json.Marshal,
+		JSONDecoder: json.Unmarshal,
+	})
+	RegisterScenarioAdminRoutes(app, store)
+
+	return app, store
+}
+
+func TestListScenariosHandler(t *testing.T) {
+	app, store := setupScenarioAdminApp()
+	store.SetState("order", "Pending")
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/_inzibat/scenarios", nil), -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"order":"Pending"}`, string(body))
+}
+
+func TestSetScenarioStateHandler(t *testing.T) {
+	t.Run("sets the scenario state", func(t *testing.T) {
+		app, store := setupScenarioAdminApp()
+
+		req := httptest.NewRequest("PUT", "/_inzibat/scenarios/order/state", strings.NewReader(`{"state":"Shipped"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "Shipped", store.State("order"))
+	})
+
+	t.Run("rejects an empty state", func(t *testing.T) {
+		app, _ := setupScenarioAdminApp()
+
+		req := httptest.NewRequest("PUT", "/_inzibat/scenarios/order/state", strings.NewReader(`{}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+}
+
+func TestResetScenarioHandlers(t *testing.T) {
+	t.Run("resets a single scenario", func(t *testing.T) {
+		app, store := setupScenarioAdminApp()
+		store.SetState("order", "Done")
+		store.SetState("user", "Deleted")
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/_inzibat/scenarios/order/reset", nil), -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, ScenarioStateStarted, store.State("order"))
+		assert.Equal(t, "Deleted", store.State("user"))
+	})
+
+	t.Run("resets all scenarios and sequences", func(t *testing.T) {
+		app, store := setupScenarioAdminApp()
+		sequence := &config.ResponseSequence{Responses: make([]config.FakeResponse, 2)}
+		store.SetState("user", "Deleted")
+		store.NextSequenceIndex("0", sequence)
+
+		resp, err := app.Test(httptest.NewRequest("POST", "/_inzibat/scenarios/reset", nil), -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, ScenarioStateStarted, store.State("user"))
+		assert.Equal(t, 0, store.NextSequenceIndex("0", sequence))
+	})
+}
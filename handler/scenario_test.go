@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestScenarioStore_Seed(t *testing.T) {
+	store := NewScenarioStore()
+	store.SetState("checkout", "Paid")
+
+	store.Seed([]config.Route{
+		{Path: "/orders", Scenario: &config.RouteScenario{Name: "orders"}},
+		{Path: "/checkout", Scenario: &config.RouteScenario{Name: "checkout"}},
+		{Path: "/plain"},
+	})
+
+	assert.Equal(t, map[string]string{
+		"orders":   ScenarioStateStarted,
+		"checkout": "Paid",
+	}, store.States())
+}
+
+func TestScenarioStore_Enter(t *testing.T) {
+	t.Run("happy path - nil scenario always enters", func(t *testing.T) {
+		store := NewScenarioStore()
+
+		assert.True(t, store.Enter(nil))
+	})
+
+	t.Run("happy path - moves through states", func(t *testing.T) {
+		store := NewScenarioStore()
+		toPending := &config.RouteScenario{Name: "order", RequiredState: ScenarioStateStarted, NewState: "Pending"}
+		toDone := &config.RouteScenario{Name: "order", RequiredState: "Pending", NewState: "Done"}
+
+		assert.False(t, store.Enter(toDone))
+		assert.True(t, store.Enter(toPending))
+		assert.Equal(t, "Pending", store.State("order"))
+		assert.False(t, store.Enter(toPending))
+		assert.True(t, store.Enter(toDone))
+		assert.Equal(t, "Done", store.State("order"))
+	})
+
+	t.Run("happy path - empty required state matches any state", func(t *testing.T) {
+		store := NewScenarioStore()
+		store.SetState("order", "Done")
+
+		assert.True(t, store.Enter(&config.RouteScenario{Name: "order"}))
+		assert.Equal(t, "Done", store.State("order"))
+	})
+
+	t.Run("happy path - only one concurrent caller wins a transition", func(t *testing.T) {
+		store := NewScenarioStore()
+		scenario := &config.RouteScenario{Name: "order", RequiredState: ScenarioStateStarted, NewState: "Pending"}
+
+		var (
+			waitGroup sync.WaitGroup
+			entered   atomic.Int32
+		)
+		for range 50 {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				if store.Enter(scenario) {
+					entered.Add(1)
+				}
+			}()
+		}
+		waitGroup.Wait()
+
+		assert.Equal(t, int32(1), entered.Load())
+	})
+}
+
+func TestScenarioStore_Reset(t *testing.T) {
+	store := NewScenarioStore()
+	sequence := &config.ResponseSequence{Responses: make([]config.FakeResponse, 3)}
+	store.SetState("order", "Done")
+	store.SetState("user", "Deleted")
+	store.NextSequenceIndex("0", sequence)
+
+	store.Reset("order")
+	assert.Equal(t, ScenarioStateStarted, store.State("order"))
+	assert.Equal(t, "Deleted", store.State("user"))
+
+	store.ResetAll()
+	assert.Equal(t, ScenarioStateStarted, store.State("user"))
+	assert.Equal(t, 0, store.NextSequenceIndex("0", sequence))
+}
+
+func TestScenarioStore_NextSequenceIndex(t *testing.T) {
+	t.Run("happy path - step mode stays on the last response", func(t *testing.T) {
+		store := NewScenarioStore()
+		sequence := &config.ResponseSequence{Responses: make([]config.FakeResponse, 2)}
+
+		var indexes []int
+		for range 4 {
+			indexes = append(indexes, store.NextSequenceIndex("0", sequence))
+		}
+
+		assert.Equal(t, []int{0, 1, 1, 1}, indexes)
+	})
+
+	t.Run("happy path - cycle mode wraps around", func(t *testing.T) {
+		store := NewScenarioStore()
+		sequence := &config.ResponseSequence{
+			Mode:      config.SequenceModeCycle,
+			Responses: make([]config.FakeResponse, 2),
+		}
+
+		var indexes []int
+		for range 5 {
+			indexes = append(indexes, store.NextSequenceIndex("3", sequence))
+		}
+
+		assert.Equal(t, []int{0, 1, 0, 1, 0}, indexes)
+	})
+}
+
+func TestScenarioStore_RetainSequences(t *testing.T) {
+	store := NewScenarioStore()
+	sequence := &config.ResponseSequence{Responses: make([]config.FakeResponse, 3)}
+	store.NextSequenceIndex("kept", sequence)
+	store.NextSequenceIndex("gone", sequence)
+
+	store.RetainSequences([]string{"kept"})
+
+	assert.Equal(t, 1, store.NextSequenceIndex("kept", sequence))
+	assert.Equal(t, 0, store.NextSequenceIndex("gone", sequence))
+}
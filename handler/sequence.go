@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+type SequenceHandler struct {
+	RouteConfig   *[]config.Route
+	ScenarioStore *ScenarioStore
+}
+
+func (sequenceRoute *SequenceHandler) CreateHandler(routeIndex int) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		route := (*sequenceRoute.RouteConfig)[routeIndex]
+		sequence := route.Sequence
+		responseIndex := sequenceRoute.ScenarioStore.NextSequenceIndex(SequenceKey(route, routeIndex), sequence)
+
+		return WriteFakeResponse(ctx, &sequence.Responses[responseIndex])
+	}
+}
+
+// SequenceKey is the key the position of a route's sequence is kept under.
+func SequenceKey(route config.Route, routeIndex int) string {
+	if route.ID != "" {
+		return route.ID
+	}
+
+	return strconv.Itoa(routeIndex)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestSequenceHandler_CreateHandler(t *testing.T) {
+	t.Run("happy path - serves responses in order", func(t *testing.T) {
+		routes := []config.Route{
+			{
+				Method: fiber.MethodGet,
+				Path:   "/jobs/1",
+				Sequence: &config.ResponseSequence{
+					Responses: []config.FakeResponse{
+						{StatusCode: fiber.StatusAccepted, BodyString: "pending"},
+						{StatusCode: fiber.StatusOK, BodyString: "done"},
+					},
+				},
+			},
+		}
+		sequenceHandler := &SequenceHandler{
+			RouteConfig:   &routes,
+			ScenarioStore: NewScenarioStore(),
+		}
+
+		fiberApp := fiber.New()
+		fiberApp.Get("/jobs/1", sequenceHandler.CreateHandler(0))
+
+		expected := []struct {
+			statusCode int
+			body       string
+		}{
+			{fiber.StatusAccepted, "pending"},
+			{fiber.StatusOK, "done"},
+			{fiber.StatusOK, "done"},
+		}
+
+		for _, expectedResponse := range expected {
+			response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/jobs/1", nil))
+			require.NoError(t, err)
+
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, expectedResponse.statusCode, response.StatusCode)
+			assert.Equal(t, expectedResponse.body, string(body))
+		}
+	})
+}
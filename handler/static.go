@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+const defaultStaticIndex = "index.html"
+
+type StaticHandler struct {
+	RouteConfig *[]config.Route
+}
+
+func (staticRoute *StaticHandler) CreateHandler(routeIndex int) func(ctx *fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		static := (*staticRoute.RouteConfig)[routeIndex].Static
+		filePath, found := resolveStaticFile(static, ctx.Params("*"))
+		if !found {
+			return ctx.Next()
+		}
+
+		// #nosec G304 - The path is cleaned and kept under the static directory
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		ctx.Set(fiber.HeaderContentType, DetectContentType(filePath, content))
+		return ctx.Status(fiber.StatusOK).Send(content)
+	}
+}
+
+func resolveStaticFile(static *config.StaticDirectory, requestPath string) (string, bool) {
+	rootDir := static.ResolvedDir
+	if rootDir == "" {
+		rootDir = static.Dir
+	}
+
+	// Cleaning the path as an absolute one drops any "..", so it stays under rootDir.
+	filePath := filepath.Join(rootDir, filepath.FromSlash(path.Clean("/"+requestPath)))
+	info, err := os.Stat(filePath)
+	if err == nil && info.IsDir() {
+		index := static.Index
+		if index == "" {
+			index = defaultStaticIndex
+		}
+
+		filePath = filepath.Join(filePath, index)
+		info, err = os.Stat(filePath)
+	}
+
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return filePath, true
+}
+
+// DetectContentType guesses the content type from the file extension, then
+// from the content itself.
+func DetectContentType(filePath string, content []byte) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(filePath)); contentType != "" {
+		return contentType
+	}
+
+	return http.DetectContentType(content)
+}
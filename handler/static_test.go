@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestStaticHandler_CreateHandler(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "docs"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "users.json"), []byte(`[{"id":1}]`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "docs", "index.html"), []byte("<p>docs</p>"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(rootDir), "secret.txt"), []byte("secret"), 0o600))
+
+	staticHandler := &StaticHandler{
+		RouteConfig: &[]config.Route{
+			{
+				Method: fiber.MethodGet,
+				Path:   "/assets",
+				Static: &config.StaticDirectory{Dir: "assets", ResolvedDir: rootDir},
+			},
+		},
+	}
+	fiberApp := fiber.New()
+	fiberApp.Get("/assets/*", staticHandler.CreateHandler(0))
+
+	sendRequest := func(t *testing.T, target string) (int, string, string) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, response.Header.Get(fiber.HeaderContentType), string(body)
+	}
+
+	t.Run("happy path - serves a file below the route path", func(t *testing.T) {
+		statusCode, contentType, body := sendRequest(t, "/assets/users.json")
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, `[{"id":1}]`, body)
+	})
+
+	t.Run("happy path - serves the index of a directory", func(t *testing.T) {
+		statusCode, contentType, body := sendRequest(t, "/assets/docs")
+
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Contains(t, contentType, "text/html")
+		assert.Equal(t, "<p>docs</p>", body)
+	})
+
+	t.Run("error path - missing file falls through", func(t *testing.T) {
+		statusCode, _, _ := sendRequest(t, "/assets/missing.json")
+
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("error path - cannot escape the directory", func(t *testing.T) {
+		statusCode, _, body := sendRequest(t, "/assets/..%2fsecret.txt")
+
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+		assert.NotEqual(t, "secret", body)
+
+		_, found := resolveStaticFile(&config.StaticDirectory{ResolvedDir: rootDir}, "../secret.txt")
+		assert.False(t, found)
+	})
+}
+
+func TestDetectContentType(t *testing.T) {
+	t.Run("happy path - uses the file extension", func(t *testing.T) {
+		assert.Equal(t, "application/pdf", DetectContentType("report.pdf", nil))
+	})
+
+	t.Run("happy path - sniffs content without a known extension", func(t *testing.T) {
+		assert.Equal(t, "application/pdf", DetectContentType("report", []byte("%PDF-1.7")))
+	})
+}
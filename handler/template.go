@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/matcher"
+)
+
+type TemplateData struct {
+	Method  string
+	Path    string
+	Params  map[string]string
+	Query   map[string]string
+	Headers map[string]string
+	Body    any
+}
+
+type ResponseTemplate struct {
+	response   *config.FakeResponse
+	headers    map[string]*template.Template
+	bodyString *template.Template
+	body       any
+}
+
+var templateFuncs = template.FuncMap{
+	"now": func(layout ...string) string {
+		if len(layout) > 0 {
+			return time.Now().Format(layout[0])
+		}
+		return time.Now().Format(time.RFC3339)
+	},
+	"uuid": uuid.NewString,
+	"randomInt": func(minValue, maxValue int) int {
+		if maxValue <= minValue {
+			return minValue
+		}
+		return minValue + rand.IntN(maxValue-minValue) // #nosec G404 - mock data, not security sensitive
+	},
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+func BuildResponseTemplates(routes []config.Route) (map[int]*ResponseTemplate, error) {
+	return buildTemplates(routes, "response", func(route config.Route) *config.FakeResponse {
+		return route.FakeResponse
+	})
+}
+
+// BuildFallbackTemplates compiles the templated fallbacks of proxy routes,
+// keyed by route index.
+func BuildFallbackTemplates(routes []config.Route) (map[int]*ResponseTemplate, error) {
+	return buildTemplates(routes, "fallback", func(route config.Route) *config.FakeResponse {
+		if route.RequestTo == nil {
+			return nil
+		}
+		return route.RequestTo.Fallback
+	})
+}
+
+func buildTemplates(
+	routes []config.Route,
+	kind string,
+	responseOf func(route config.Route) *config.FakeResponse,
+) (map[int]*ResponseTemplate, error) {
+	responseTemplates := make(map[int]*ResponseTemplate)
+	for routeIndex, route := range routes {
+		resp := responseOf(route)
+		if resp == nil || !resp.Template {
+			continue
+		}
+
+		responseTemplate, err := NewResponseTemplate(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %s template for route %s %s: %w", kind, route.Method, route.Path, err)
+		}
+
+		responseTemplates[routeIndex] = responseTemplate
+	}
+
+	return responseTemplates, nil
+}
+
+func NewResponseTemplate(resp *config.FakeResponse) (*ResponseTemplate, error) {
+	responseTemplate := &ResponseTemplate{
+		response: resp,
+		headers:  make(map[string]*template.Template, len(resp.Headers)),
+	}
+
+	for headerKey, headerValue := range resp.Headers {
+		headerTemplate, err := parseTemplate("header "+headerKey, strings.Join(headerValue, ","))
+		if err != nil {
+			return nil, err
+		}
+		responseTemplate.headers[headerKey] = headerTemplate
+	}
+
+	if resp.BodyString != "" {
+		bodyStringTemplate, err := parseTemplate("bodyString", resp.BodyString)
+		if err != nil {
+			return nil, err
+		}
+		responseTemplate.bodyString = bodyStringTemplate
+	}
+
+	if resp.Body != nil {
+		body, err := compileBodyTemplate("body", resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		responseTemplate.body = body
+	}
+
+	return responseTemplate, nil
+}
+
+func (responseTemplate *ResponseTemplate) Render(ctx *fiber.Ctx) (*config.FakeResponse, error) {
+	data := NewTemplateData(ctx)
+	rendered := *responseTemplate.response
+
+	if len(responseTemplate.headers) > 0 {
+		rendered.Headers = make(http.Header, len(responseTemplate.headers))
+		for headerKey, headerTemplate := range responseTemplate.headers {
+			headerValue, err := executeTemplate(headerTemplate, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered.Headers.Set(headerKey, headerValue)
+		}
+	}
+
+	if responseTemplate.bodyString != nil {
+		bodyString, err := executeTemplate(responseTemplate.bodyString, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered.BodyString = bodyString
+	}
+
+	if responseTemplate.body != nil {
+		body, err := renderBodyTemplate(responseTemplate.body, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Body = body
+	}
+
+	return &rendered, nil
+}
+
+func NewTemplateData(ctx *fiber.Ctx) TemplateData {
+	headers := make(map[string]string)
+	for headerKey, headerValue := range ctx.GetReqHeaders() {
+		headers[http.CanonicalHeaderKey(headerKey)] = strings.Join(headerValue, ",")
+	}
+
+	body, _ := matcher.JSONBody(ctx)
+
+	return TemplateData{
+		Method:  ctx.Method(),
+		Path:    ctx.Path(),
+		Params:  ctx.AllParams(),
+		Query:   ctx.Queries(),
+		Headers: headers,
+		Body:    body,
+	}
+}
+
+func parseTemplate(name string, text string) (*template.Template, error) {
+	parsedTemplate, err := template.New(name).
+		Option("missingkey=zero").
+		Funcs(templateFuncs).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return parsedTemplate, nil
+}
+
+func executeTemplate(parsedTemplate *template.Template, data TemplateData) (string, error) {
+	var buffer bytes.Buffer
+	if err := parsedTemplate.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buffer.String(), nil
+}
+
+func compileBodyTemplate(name string, value any) (any, error) {
+	switch typedValue := value.(type) {
+	case string:
+		if !strings.Contains(typedValue, "{{") {
+			return typedValue, nil
+		}
+		return parseTemplate(name, typedValue)
+	case config.HttpBody:
+		return compileBodyTemplate(name, map[string]any(typedValue))
+	case map[string]any:
+		compiled := make(map[string]any, len(typedValue))
+		for key, nestedValue := range typedValue {
+			compiledValue, err := compileBodyTemplate(name+"."+key, nestedValue)
+			if err != nil {
+				return nil, err
+			}
+			compiled[key] = compiledValue
+		}
+		return compiled, nil
+	case []any:
+		compiled := make([]any, len(typedValue))
+		for index, nestedValue := range typedValue {
+			compiledValue, err := compileBodyTemplate(fmt.Sprintf("%s[%d]", name, index), nestedValue)
+			if err != nil {
+				return nil, err
+			}
+			compiled[index] = compiledValue
+		}
+		return compiled, nil
+	default:
+		return value, nil
+	}
+}
+
+func renderBodyTemplate(value any, data TemplateData) (any, error) {
+	switch typedValue := value.(type) {
+	case *template.Template:
+		return executeTemplate(typedValue, data)
+	case map[string]any:
+		rendered := make(map[string]any, len(typedValue))
+		for key, nestedValue := range typedValue {
+			renderedValue, err := renderBodyTemplate(nestedValue, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[key] = renderedValue
+		}
+		return rendered, nil
+	case []any:
+		rendered := make([]any, len(typedValue))
+		for index, nestedValue := range typedValue {
+			renderedValue, err := renderBodyTemplate(nestedValue, data)
+			if err != nil {
+				return nil, err
+			}
+			rendered[index] = renderedValue
+		}
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
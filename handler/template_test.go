@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestBuildResponseTemplates(t *testing.T) {
+	t.Run("happy path - compiles only templated mock routes", func(t *testing.T) {
+		routes := []config.Route{
+			{
+				Method:       fiber.MethodGet,
+				Path:         "/static",
+				FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "{{.Path}}"},
+			},
+			{
+				Method: fiber.MethodGet,
+				Path:   "/proxy",
+				RequestTo: &config.RequestTo{
+					Method: fiber.MethodGet,
+					Host:   "http://localhost:8081",
+					Path:   "/",
+				},
+			},
+			{
+				Method:       fiber.MethodGet,
+				Path:         "/templated",
+				FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "{{.Path}}", Template: true},
+			},
+		}
+
+		responseTemplates, err := BuildResponseTemplates(routes)
+
+		require.NoError(t, err)
+		assert.Len(t, responseTemplates, 1)
+		assert.Contains(t, responseTemplates, 2)
+	})
+
+	t.Run("error path - invalid template", func(t *testing.T) {
+		routes := []config.Route{
+			{
+				Method: fiber.MethodGet,
+				Path:   "/users/:id",
+				FakeResponse: &config.FakeResponse{
+					StatusCode: 200,
+					Body:       config.HttpBody{"items": []any{"{{.Params.id"}},
+					Template:   true,
+				},
+			},
+		}
+
+		_, err := BuildResponseTemplates(routes)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to compile response template for route GET /users/:id")
+	})
+}
+
+func TestEndpointHandler_CreateHandler_WithTemplate(t *testing.T) {
+	routes := []config.Route{
+		{
+			Method: fiber.MethodPost,
+			Path:   "/users/:id",
+			FakeResponse: &config.FakeResponse{
+				StatusCode: 200,
+				Headers: http.Header{
+					"X-Request-Id": {"{{uuid}}"},
+				},
+				Body: config.HttpBody{
+					"id":     "{{.Params.id}}",
+					"page":   "{{.Query.page}}",
+					"tenant": `{{index .Headers "X-Tenant"}}`,
+					"name":   "{{.Body.name}}",
+					"lucky":  "{{randomInt 5 6}}",
+					"tags":   []any{"{{.Method}}", "static"},
+					"count":  float64(3),
+				},
+				Template: true,
+			},
+		},
+		{
+			Method: fiber.MethodGet,
+			Path:   "/echo/:id",
+			FakeResponse: &config.FakeResponse{
+				StatusCode: 200,
+				BodyString: `{"id":"{{.Params.id}}","year":"{{now "2006"}}","missing":"{{.Query.none}}"}`,
+				Template:   true,
+			},
+		},
+	}
+
+	responseTemplates, err := BuildResponseTemplates(routes)
+	require.NoError(t, err)
+
+	endpointHandler := &EndpointHandler{
+		RouteConfig:       &routes,
+		ResponseTemplates: responseTemplates,
+	}
+
+	fiberApp := fiber.New()
+	fiberApp.Post(routes[0].Path, endpointHandler.CreateHandler(0))
+	fiberApp.Get(routes[1].Path, endpointHandler.CreateHandler(1))
+
+	t.Run("happy path - renders body and headers from the request", func(t *testing.T) {
+		request := httptest.NewRequest(fiber.MethodPost, "/users/42?page=3", strings.NewReader(`{"name":"lynicis"}`))
+		request.Header.Set("X-Tenant", "acme")
+		request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+
+		response, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(responseBody, &body))
+
+		assert.Equal(t, "42", body["id"])
+		assert.Equal(t, "3", body["page"])
+		assert.Equal(t, "lynicis", body["name"])
+		assert.Equal(t, "acme", body["tenant"])
+		assert.Equal(t, "5", body["lucky"])
+		assert.Equal(t, []any{"POST", "static"}, body["tags"])
+		assert.Equal(t, float64(3), body["count"])
+
+		_, err = uuid.Parse(response.Header.Get("X-Request-Id"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("happy path - renders body string", func(t *testing.T) {
+		request := httptest.NewRequest(fiber.MethodGet, "/echo/abc", nil)
+
+		response, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		year, err := strconv.Atoi(string(responseBody)[20:24])
+		require.NoError(t, err)
+
+		assert.Greater(t, year, 2000)
+		assert.Equal(t, `{"id":"abc","year":"`, string(responseBody)[:20])
+		assert.True(t, strings.HasSuffix(string(responseBody), `","missing":""}`))
+	})
+
+	t.Run("happy path - template is not mutated between requests", func(t *testing.T) {
+		for _, id := range []string{"first", "second"} {
+			response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/echo/"+id, nil))
+			require.NoError(t, err)
+
+			responseBody, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Contains(t, string(responseBody), `"id":"`+id+`"`)
+		}
+
+		assert.Contains(t, routes[1].FakeResponse.BodyString, "{{.Params.id}}")
+	})
+}
+
+func TestEndpointHandler_CreateHandler_WithArrayTemplate(t *testing.T) {
+	routes := []config.Route{
+		{
+			Method: fiber.MethodGet,
+			Path:   "/users/:id/tags",
+			FakeResponse: &config.FakeResponse{
+				StatusCode: 200,
+				Body:       []any{"{{.Params.id}}", map[string]any{"method": "{{.Method}}"}, float64(1)},
+				Template:   true,
+			},
+		},
+	}
+
+	responseTemplates, err := BuildResponseTemplates(routes)
+	require.NoError(t, err)
+
+	endpointHandler := &EndpointHandler{
+		RouteConfig:       &routes,
+		ResponseTemplates: responseTemplates,
+	}
+
+	fiberApp := fiber.New()
+	fiberApp.Get(routes[0].Path, endpointHandler.CreateHandler(0))
+
+	t.Run("happy path - renders a top-level array body", func(t *testing.T) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/users/7/tags", nil))
+		require.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `["7",{"method":"GET"},1]`, string(responseBody))
+	})
+}
@@ -0,0 +1,168 @@
+// Package inzibattest runs Inzibat in-process for Go tests.
+package inzibattest
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/journal"
+	"github.com/lynicis/inzibat/server"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Server is an Inzibat instance listening on a random local port.
+type Server struct {
+	URL    string
+	t      testing.TB
+	server *server.Server
+}
+
+// New starts Inzibat with cfg and stops it when the test finishes. cfg may be
+// nil to start without routes; its server port is ignored.
+func New(t testing.TB, cfg *config.Cfg) *Server {
+	t.Helper()
+
+	serverCfg := config.Cfg{}
+	if cfg != nil {
+		serverCfg = *cfg
+		// Prepare assigns ids to the routes, which must not leak into cfg.
+		serverCfg.Routes = slices.Clone(cfg.Routes)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("inzibattest: failed to listen: %v", err)
+	}
+	serverCfg.ServerPort = listener.Addr().(*net.TCPAddr).Port
+
+	if serverCfg.Concurrency == 0 {
+		serverCfg.Concurrency = 1
+	}
+
+	configLoader := &config.Reader{Validator: validator.New()}
+	if len(serverCfg.Routes) > 0 {
+		if err = configLoader.Prepare(&serverCfg); err != nil {
+			_ = listener.Close()
+			t.Fatalf("inzibattest: invalid config: %v", err)
+		}
+	}
+
+	inzibatServer, err := server.New(&serverCfg, configLoader)
+	if err != nil {
+		_ = listener.Close()
+		t.Fatalf("inzibattest: failed to set up server: %v", err)
+	}
+
+	go func() {
+		_ = inzibatServer.App.Listener(listener)
+	}()
+
+	t.Cleanup(func() {
+		if err := inzibatServer.App.ShutdownWithTimeout(shutdownTimeout); err != nil {
+			t.Errorf("inzibattest: failed to shut down: %v", err)
+		}
+		// Serve may not have picked the listener up yet when the test is short.
+		_ = listener.Close()
+	})
+
+	return &Server{
+		URL:    fmt.Sprintf("http://%s", listener.Addr().String()),
+		t:      t,
+		server: inzibatServer,
+	}
+}
+
+// AddRoute registers routes on the running server, in front of the existing
+// ones so they take precedence on a shared path.
+func (testServer *Server) AddRoute(routes ...config.Route) []config.Route {
+	testServer.t.Helper()
+
+	addedRoutes := make([]config.Route, 0, len(routes))
+	for routeIndex := len(routes) - 1; routeIndex >= 0; routeIndex-- {
+		route, err := testServer.server.RouteTable.AddRoute(routes[routeIndex], true)
+		if err != nil {
+			testServer.t.Fatalf("inzibattest: failed to add route %s %s: %v",
+				routes[routeIndex].Method, routes[routeIndex].Path, err)
+		}
+		addedRoutes = append([]config.Route{route}, addedRoutes...)
+	}
+
+	return addedRoutes
+}
+
+func (testServer *Server) DeleteRoute(id string) {
+	testServer.t.Helper()
+
+	if err := testServer.server.RouteTable.DeleteRoute(id); err != nil {
+		testServer.t.Fatalf("inzibattest: failed to delete route %s: %v", id, err)
+	}
+}
+
+func (testServer *Server) Routes() []config.Route {
+	return testServer.server.RouteTable.Routes()
+}
+
+// Verify returns how many journaled requests match pattern and the closest
+// ones that did not.
+func (testServer *Server) Verify(pattern journal.RequestPattern) journal.VerifyResult {
+	testServer.t.Helper()
+
+	result, err := testServer.server.Journal.Verify(pattern)
+	if err != nil {
+		testServer.t.Fatalf("inzibattest: invalid request pattern: %v", err)
+	}
+
+	return result
+}
+
+// AssertCalled fails the test unless exactly times requests match pattern.
+// The failure message lists the closest non-matching requests.
+func (testServer *Server) AssertCalled(pattern journal.RequestPattern, times int) bool {
+	testServer.t.Helper()
+
+	result := testServer.Verify(pattern)
+	if result.Count == times {
+		return true
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "inzibattest: expected %d request(s) matching %s, got %d",
+		times, describePattern(pattern), result.Count)
+	for _, nearMiss := range result.NearMisses {
+		fmt.Fprintf(&message, "\n\tnear miss %s %s: %s mismatched",
+			nearMiss.Entry.Method, nearMiss.Entry.Path, strings.Join(nearMiss.Mismatches, ", "))
+	}
+	testServer.t.Error(message.String())
+
+	return false
+}
+
+// ResetJournal forgets the requests received so far.
+func (testServer *Server) ResetJournal() {
+	testServer.server.Journal.Clear()
+}
+
+func describePattern(pattern journal.RequestPattern) string {
+	method := pattern.Method
+	if method == "" {
+		method = config.MethodAny
+	}
+
+	path := pattern.Path
+	if pattern.PathRegex != "" {
+		path = "~" + pattern.PathRegex
+	}
+	if path == "" {
+		path = "*"
+	}
+
+	return method + " " + path
+}
@@ -0,0 +1,193 @@
+package inzibattest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/journal"
+)
+
+func getBody(t *testing.T, url string, headers map[string]string) (int, string) {
+	t.Helper()
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func TestNew(t *testing.T) {
+	t.Run("happy path - serves configured routes on a random port", func(t *testing.T) {
+		server := New(t, &config.Cfg{
+			Routes: Routes(
+				Get("/users").Status(http.StatusOK).Text("users"),
+			),
+		})
+
+		assert.True(t, strings.HasPrefix(server.URL, "http://127.0.0.1:"))
+		statusCode, body := getBody(t, server.URL+"/users", nil)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "users", body)
+	})
+
+	t.Run("happy path - starts without routes", func(t *testing.T) {
+		server := New(t, nil)
+
+		statusCode, _ := getBody(t, server.URL+"/users", nil)
+
+		assert.Equal(t, http.StatusNotFound, statusCode)
+		assert.Empty(t, server.Routes())
+	})
+
+	t.Run("happy path - two servers run side by side", func(t *testing.T) {
+		first := New(t, nil)
+		second := New(t, nil)
+
+		assert.NotEqual(t, first.URL, second.URL)
+	})
+
+	t.Run("happy path - leaves the routes of cfg untouched", func(t *testing.T) {
+		cfg := &config.Cfg{
+			Routes: Routes(
+				Get("/users").Status(http.StatusOK).Text("users"),
+			),
+		}
+
+		server := New(t, cfg)
+
+		assert.Empty(t, cfg.Routes[0].ID)
+		assert.Nil(t, cfg.Routes[0].Submitted)
+		assert.NotEmpty(t, server.Routes()[0].ID)
+	})
+
+	t.Run("happy path - applies the global delay unless the route sets its own", func(t *testing.T) {
+		server := New(t, &config.Cfg{
+			Delay: &config.Delay{FixedMs: 200},
+			Routes: Routes(
+				Get("/slow").Text("slow"),
+				Get("/fast").Delay(0).Text("fast"),
+			),
+		})
+
+		startedAt := time.Now()
+		_, body := getBody(t, server.URL+"/slow", nil)
+		assert.Equal(t, "slow", body)
+		assert.GreaterOrEqual(t, time.Since(startedAt), 200*time.Millisecond)
+
+		startedAt = time.Now()
+		_, body = getBody(t, server.URL+"/fast", nil)
+		assert.Equal(t, "fast", body)
+		assert.Less(t, time.Since(startedAt), 200*time.Millisecond)
+	})
+}
+
+func TestServer_AddRoute(t *testing.T) {
+	t.Run("happy path - added routes take precedence", func(t *testing.T) {
+		server := New(t, &config.Cfg{
+			Routes: Routes(Get("/users").Text("static")),
+		})
+
+		addedRoutes := server.AddRoute(Routes(
+			Get("/users").MatchHeader("X-Tenant", "acme").Text("acme"),
+			Get("/users").MatchHeader("X-Tenant", "globex").Text("globex"),
+		)...)
+
+		require.Len(t, addedRoutes, 2)
+		assert.Equal(t, "/users", server.Routes()[0].Path)
+		assert.Equal(t, addedRoutes[0].ID, server.Routes()[0].ID)
+
+		_, body := getBody(t, server.URL+"/users", map[string]string{"X-Tenant": "acme"})
+		assert.Equal(t, "acme", body)
+		_, body = getBody(t, server.URL+"/users", map[string]string{"X-Tenant": "globex"})
+		assert.Equal(t, "globex", body)
+		_, body = getBody(t, server.URL+"/users", nil)
+		assert.Equal(t, "static", body)
+	})
+
+	t.Run("happy path - deleted route is no longer served", func(t *testing.T) {
+		server := New(t, nil)
+		addedRoutes := server.AddRoute(Get("/users").Text("users").Route())
+
+		server.DeleteRoute(addedRoutes[0].ID)
+
+		statusCode, _ := getBody(t, server.URL+"/users", nil)
+		assert.Equal(t, http.StatusNotFound, statusCode)
+	})
+}
+
+type failureRecorder struct {
+	testing.TB
+	messages []string
+}
+
+func (recorder *failureRecorder) Helper() {}
+
+func (recorder *failureRecorder) Error(args ...any) {
+	recorder.messages = append(recorder.messages, fmt.Sprint(args...))
+}
+
+func TestServer_Verify(t *testing.T) {
+	server := New(t, &config.Cfg{
+		Routes: Routes(
+			Get("/users").Text("users"),
+		),
+	})
+
+	getBody(t, server.URL+"/users", map[string]string{"X-Tenant": "acme"})
+	getBody(t, server.URL+"/users", map[string]string{"X-Tenant": "globex"})
+
+	t.Run("happy path - counts matching requests", func(t *testing.T) {
+		result := server.Verify(journal.RequestPattern{
+			Method: http.MethodGet,
+			Path:   "/users",
+			Match: &config.RouteMatch{
+				Headers: map[string]config.ValueMatcher{"X-Tenant": {Equals: "acme"}},
+			},
+		})
+
+		assert.Equal(t, 1, result.Count)
+		require.Len(t, result.NearMisses, 1)
+		assert.Equal(t, []string{"header X-Tenant"}, result.NearMisses[0].Mismatches)
+	})
+
+	t.Run("happy path - AssertCalled passes on the expected count", func(t *testing.T) {
+		assert.True(t, server.AssertCalled(journal.RequestPattern{Path: "/users"}, 2))
+	})
+
+	t.Run("error path - AssertCalled reports near misses", func(t *testing.T) {
+		recorder := &failureRecorder{TB: t}
+		recordingServer := *server
+		recordingServer.t = recorder
+
+		ok := recordingServer.AssertCalled(journal.RequestPattern{Method: http.MethodPost, Path: "/users"}, 1)
+
+		assert.False(t, ok)
+		require.Len(t, recorder.messages, 1)
+		assert.Contains(t, recorder.messages[0], "expected 1 request(s) matching POST /users, got 0")
+		assert.Contains(t, recorder.messages[0], "near miss GET /users: method mismatched")
+	})
+
+	t.Run("happy path - ResetJournal forgets previous requests", func(t *testing.T) {
+		server.ResetJournal()
+
+		assert.Equal(t, 0, server.Verify(journal.RequestPattern{}).Count)
+	})
+}
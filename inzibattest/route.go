@@ -0,0 +1,184 @@
+package inzibattest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+// RouteBuilder builds a config.Route step by step. Mock routes answer with
+// status 200 unless Status is called.
+type RouteBuilder struct {
+	route config.Route
+}
+
+func NewRoute(method, path string) *RouteBuilder {
+	return &RouteBuilder{
+		route: config.Route{
+			Method: method,
+			Path:   path,
+		},
+	}
+}
+
+func Get(path string) *RouteBuilder {
+	return NewRoute(fiber.MethodGet, path)
+}
+
+func Post(path string) *RouteBuilder {
+	return NewRoute(fiber.MethodPost, path)
+}
+
+func Put(path string) *RouteBuilder {
+	return NewRoute(fiber.MethodPut, path)
+}
+
+func Patch(path string) *RouteBuilder {
+	return NewRoute(fiber.MethodPatch, path)
+}
+
+func Delete(path string) *RouteBuilder {
+	return NewRoute(fiber.MethodDelete, path)
+}
+
+func Any(path string) *RouteBuilder {
+	return NewRoute(config.MethodAny, path)
+}
+
+func (builder *RouteBuilder) ID(id string) *RouteBuilder {
+	builder.route.ID = id
+	return builder
+}
+
+func (builder *RouteBuilder) MatchHeader(key, value string) *RouteBuilder {
+	match := builder.match()
+	if match.Headers == nil {
+		match.Headers = make(map[string]config.ValueMatcher)
+	}
+	match.Headers[key] = config.ValueMatcher{Equals: value}
+	return builder
+}
+
+func (builder *RouteBuilder) MatchQuery(key, value string) *RouteBuilder {
+	match := builder.match()
+	if match.Query == nil {
+		match.Query = make(map[string]config.ValueMatcher)
+	}
+	match.Query[key] = config.ValueMatcher{Equals: value}
+	return builder
+}
+
+func (builder *RouteBuilder) MatchCookie(key, value string) *RouteBuilder {
+	match := builder.match()
+	if match.Cookies == nil {
+		match.Cookies = make(map[string]config.ValueMatcher)
+	}
+	match.Cookies[key] = config.ValueMatcher{Equals: value}
+	return builder
+}
+
+func (builder *RouteBuilder) MatchJSONPath(jsonPath, value string) *RouteBuilder {
+	match := builder.match()
+	match.Body = append(match.Body, config.BodyMatcher{
+		JSONPath: jsonPath,
+		Equals:   value,
+	})
+	return builder
+}
+
+func (builder *RouteBuilder) Scenario(name, requiredState, newState string) *RouteBuilder {
+	builder.route.Scenario = &config.RouteScenario{
+		Name:          name,
+		RequiredState: requiredState,
+		NewState:      newState,
+	}
+	return builder
+}
+
+func (builder *RouteBuilder) Status(statusCode int) *RouteBuilder {
+	builder.fakeResponse().StatusCode = statusCode
+	return builder
+}
+
+func (builder *RouteBuilder) Header(key, value string) *RouteBuilder {
+	fakeResponse := builder.fakeResponse()
+	if fakeResponse.Headers == nil {
+		fakeResponse.Headers = make(http.Header)
+	}
+	fakeResponse.Headers.Add(key, value)
+	return builder
+}
+
+func (builder *RouteBuilder) JSON(body any) *RouteBuilder {
+	builder.fakeResponse().Body = body
+	return builder
+}
+
+func (builder *RouteBuilder) Text(body string) *RouteBuilder {
+	builder.fakeResponse().BodyString = body
+	return builder
+}
+
+func (builder *RouteBuilder) Template() *RouteBuilder {
+	builder.fakeResponse().Template = true
+	return builder
+}
+
+// Delay holds the response back, for testing client timeouts.
+func (builder *RouteBuilder) Delay(delay time.Duration) *RouteBuilder {
+	builder.route.Delay = &config.Delay{FixedMs: int(delay.Milliseconds())}
+	return builder
+}
+
+// Fault breaks the response with the given probability, between 0 and 1.
+func (builder *RouteBuilder) Fault(faultType string, probability float64) *RouteBuilder {
+	builder.route.Faults = append(builder.route.Faults, config.Fault{
+		Type:        faultType,
+		Probability: probability,
+	})
+	return builder
+}
+
+// ProxyTo turns the route into a proxy route forwarding the incoming method,
+// headers and body to host and path.
+func (builder *RouteBuilder) ProxyTo(host, path string) *RouteBuilder {
+	builder.route.FakeResponse = nil
+	builder.route.RequestTo = &config.RequestTo{
+		Method:                 config.MethodAny,
+		Host:                   host,
+		Path:                   path,
+		PassWithRequestBody:    true,
+		PassWithRequestHeaders: true,
+	}
+	return builder
+}
+
+func (builder *RouteBuilder) Route() config.Route {
+	return builder.route
+}
+
+func (builder *RouteBuilder) match() *config.RouteMatch {
+	if builder.route.Match == nil {
+		builder.route.Match = &config.RouteMatch{}
+	}
+	return builder.route.Match
+}
+
+func (builder *RouteBuilder) fakeResponse() *config.FakeResponse {
+	if builder.route.FakeResponse == nil {
+		builder.route.FakeResponse = &config.FakeResponse{StatusCode: http.StatusOK}
+	}
+	return builder.route.FakeResponse
+}
+
+// Routes builds every builder, for use as config.Cfg.Routes.
+func Routes(builders ...*RouteBuilder) []config.Route {
+	routes := make([]config.Route, 0, len(builders))
+	for _, builder := range builders {
+		routes = append(routes, builder.Route())
+	}
+	return routes
+}
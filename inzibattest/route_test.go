@@ -0,0 +1,87 @@
+package inzibattest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestRouteBuilder(t *testing.T) {
+	t.Run("happy path - builds a mock route", func(t *testing.T) {
+		route := Post("/users").
+			ID("create-user").
+			MatchQuery("dryRun", "true").
+			MatchCookie("session", "abc").
+			MatchJSONPath("$.name", "lynicis").
+			Scenario("signup", "Started", "Created").
+			Status(http.StatusCreated).
+			Header("X-Request-Id", "42").
+			JSON(config.HttpBody{"id": 1}).
+			Template().
+			Route()
+
+		assert.Equal(t, "create-user", route.ID)
+		assert.Equal(t, http.MethodPost, route.Method)
+		assert.Equal(t, "/users", route.Path)
+		assert.Equal(t, "true", route.Match.Query["dryRun"].Equals)
+		assert.Equal(t, "abc", route.Match.Cookies["session"].Equals)
+		assert.Equal(t, "$.name", route.Match.Body[0].JSONPath)
+		assert.Equal(t, "signup", route.Scenario.Name)
+		assert.Equal(t, http.StatusCreated, route.FakeResponse.StatusCode)
+		assert.Equal(t, "42", route.FakeResponse.Headers.Get("X-Request-Id"))
+		assert.Equal(t, config.HttpBody{"id": 1}, route.FakeResponse.Body)
+		assert.True(t, route.FakeResponse.Template)
+	})
+
+	t.Run("happy path - sets a fixed delay", func(t *testing.T) {
+		route := Get("/users").Delay(1500 * time.Millisecond).Route()
+
+		assert.Equal(t, &config.Delay{FixedMs: 1500}, route.Delay)
+	})
+
+	t.Run("happy path - adds faults", func(t *testing.T) {
+		route := Get("/users").
+			Fault(config.FaultEmptyReply, 0.1).
+			Fault(config.FaultServerError, 0.2).
+			Route()
+
+		assert.Equal(t, []config.Fault{
+			{Type: config.FaultEmptyReply, Probability: 0.1},
+			{Type: config.FaultServerError, Probability: 0.2},
+		}, route.Faults)
+	})
+
+	t.Run("happy path - accepts an array body", func(t *testing.T) {
+		route := Get("/users").JSON([]any{"alice", "bob"}).Route()
+
+		assert.Equal(t, []any{"alice", "bob"}, route.FakeResponse.Body)
+	})
+
+	t.Run("happy path - defaults to status 200", func(t *testing.T) {
+		route := Get("/users").Text("users").Route()
+
+		assert.Equal(t, http.StatusOK, route.FakeResponse.StatusCode)
+		assert.Equal(t, "users", route.FakeResponse.BodyString)
+	})
+
+	t.Run("happy path - builds a proxy route", func(t *testing.T) {
+		route := Any("/api/*").ProxyTo("http://localhost:8081", "/*").Route()
+
+		assert.Equal(t, config.MethodAny, route.Method)
+		assert.Nil(t, route.FakeResponse)
+		assert.Equal(t, config.MethodAny, route.RequestTo.Method)
+		assert.Equal(t, "http://localhost:8081", route.RequestTo.Host)
+		assert.True(t, route.RequestTo.PassWithRequestBody)
+	})
+
+	t.Run("happy path - method helpers", func(t *testing.T) {
+		assert.Equal(t, http.MethodPut, Put("/").Route().Method)
+		assert.Equal(t, http.MethodPatch, Patch("/").Route().Method)
+		assert.Equal(t, http.MethodDelete, Delete("/").Route().Method)
+		assert.Equal(t, "PURGE", NewRoute("PURGE", "/").Route().Method)
+	})
+}
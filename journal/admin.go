@@ -0,0 +1,46 @@
+package journal
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+func RegisterAdminRoutes(app *fiber.App, journal *Journal) {
+	app.Get("/_inzibat/journal", listEntriesHandler(journal))
+	app.Post("/_inzibat/journal/clear", clearEntriesHandler(journal))
+	app.Post("/_inzibat/verify", verifyHandler(journal))
+}
+
+func listEntriesHandler(journal *Journal) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(journal.List())
+	}
+}
+
+func clearEntriesHandler(journal *Journal) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		journal.Clear()
+		return ctx.JSON(fiber.Map{
+			"message": "request journal cleared",
+		})
+	}
+}
+
+func verifyHandler(journal *Journal) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var pattern RequestPattern
+		if err := ctx.BodyParser(&pattern); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "request body must be a valid request pattern",
+			})
+		}
+
+		result, err := journal.Verify(pattern)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": err.Error(),
+			})
+		}
+
+		return ctx.JSON(result)
+	}
+}
@@ -0,0 +1,95 @@
+package journal
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAdminApp() (*fiber.App, *Journal) {
+	journal := New(10, 0)
+	app := fiber.New(fiber.Config{
+		JSONEncoder: json.Marshal,
+		JSONDecoder: json.Unmarshal,
+	})
+	RegisterAdminRoutes(app, journal)
+
+	return app, journal
+}
+
+func sendAdminRequest(t *testing.T, app *fiber.App, method, target, body string) (int, string) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	response, err := app.Test(request, -1)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(responseBody)
+}
+
+func TestListEntriesHandler(t *testing.T) {
+	app, journal := setupAdminApp()
+	journal.Add(Entry{ID: "1", Method: fiber.MethodGet, Path: "/users"})
+
+	statusCode, body := sendAdminRequest(t, app, fiber.MethodGet, "/_inzibat/journal", "")
+
+	assert.Equal(t, fiber.StatusOK, statusCode)
+	assert.Contains(t, body, `"id":"1"`)
+}
+
+func TestClearEntriesHandler(t *testing.T) {
+	app, journal := setupAdminApp()
+	journal.Add(Entry{ID: "1"})
+
+	statusCode, _ := sendAdminRequest(t, app, fiber.MethodPost, "/_inzibat/journal/clear", "")
+
+	assert.Equal(t, fiber.StatusOK, statusCode)
+	assert.Empty(t, journal.List())
+}
+
+func TestVerifyHandler(t *testing.T) {
+	t.Run("happy path - returns the count and near misses", func(t *testing.T) {
+		app, journal := setupAdminApp()
+		journal.Add(Entry{ID: "match", Method: fiber.MethodGet, Path: "/users"})
+		journal.Add(Entry{ID: "miss", Method: fiber.MethodPost, Path: "/users"})
+
+		statusCode, body := sendAdminRequest(t, app, fiber.MethodPost, "/_inzibat/verify",
+			`{"method":"GET","path":"/users"}`)
+		require.Equal(t, fiber.StatusOK, statusCode)
+
+		var result VerifyResult
+		require.NoError(t, json.Unmarshal([]byte(body), &result))
+		assert.Equal(t, 1, result.Count)
+		require.Len(t, result.NearMisses, 1)
+		assert.Equal(t, "miss", result.NearMisses[0].Entry.ID)
+		assert.Equal(t, []string{"method"}, result.NearMisses[0].Mismatches)
+	})
+
+	t.Run("error path - invalid body", func(t *testing.T) {
+		app, _ := setupAdminApp()
+
+		statusCode, _ := sendAdminRequest(t, app, fiber.MethodPost, "/_inzibat/verify", "{")
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+	})
+
+	t.Run("error path - invalid pattern", func(t *testing.T) {
+		app, _ := setupAdminApp()
+
+		statusCode, body := sendAdminRequest(t, app, fiber.MethodPost, "/_inzibat/verify", `{"pathRegex":"("}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+		assert.Contains(t, body, "invalid path regex")
+	})
+}
@@ -0,0 +1,149 @@
+package journal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/matcher"
+)
+
+// Journal is a thread-safe, capacity-limited log of received requests. When
+// the capacity is exceeded, the oldest entries are dropped.
+type Journal struct {
+	mu           sync.RWMutex
+	entries      []Entry
+	capacity     int
+	maxBodyBytes int
+}
+
+// New returns a journal holding up to capacity entries, with request bodies
+// truncated to maxBodyBytes. Zero values take the defaults.
+func New(capacity, maxBodyBytes int) *Journal {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return &Journal{
+		entries:      make([]Entry, 0, min(capacity, 1024)),
+		capacity:     capacity,
+		maxBodyBytes: maxBodyBytes,
+	}
+}
+
+func (journal *Journal) Add(entry Entry) {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	if len(journal.entries) >= journal.capacity {
+		journal.entries = journal.entries[1:]
+	}
+
+	journal.entries = append(journal.entries, entry)
+}
+
+func (journal *Journal) List() []Entry {
+	journal.mu.RLock()
+	defer journal.mu.RUnlock()
+
+	result := make([]Entry, len(journal.entries))
+	copy(result, journal.entries)
+
+	return result
+}
+
+func (journal *Journal) Clear() {
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+
+	journal.entries = make([]Entry, 0, min(journal.capacity, 1024))
+}
+
+// Verify counts the entries matching pattern and returns the closest
+// non-matching ones, fewest mismatches and most recent first.
+func (journal *Journal) Verify(pattern RequestPattern) (VerifyResult, error) {
+	compiled, err := compilePattern(pattern)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+
+	entries := journal.List()
+	result := VerifyResult{NearMisses: []NearMiss{}}
+	for entryIndex := len(entries) - 1; entryIndex >= 0; entryIndex-- {
+		mismatches := compiled.mismatches(entries[entryIndex])
+		if len(mismatches) == 0 {
+			result.Count++
+			continue
+		}
+
+		result.NearMisses = append(result.NearMisses, NearMiss{
+			Entry:      entries[entryIndex],
+			Mismatches: mismatches,
+		})
+	}
+
+	sort.SliceStable(result.NearMisses, func(i, j int) bool {
+		return len(result.NearMisses[i].Mismatches) < len(result.NearMisses[j].Mismatches)
+	})
+	if len(result.NearMisses) > DefaultNearMissLimit {
+		result.NearMisses = result.NearMisses[:DefaultNearMissLimit]
+	}
+
+	return result, nil
+}
+
+type compiledPattern struct {
+	method    string
+	path      string
+	pathRegex *regexp.Regexp
+	matcher   *matcher.RequestMatcher
+}
+
+func compilePattern(pattern RequestPattern) (*compiledPattern, error) {
+	compiled := &compiledPattern{
+		method: strings.ToUpper(pattern.Method),
+		path:   pattern.Path,
+	}
+	if compiled.method == config.MethodAny {
+		compiled.method = ""
+	}
+
+	if pattern.PathRegex != "" {
+		pathRegex, err := regexp.Compile(pattern.PathRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path regex: %w", err)
+		}
+		compiled.pathRegex = pathRegex
+	}
+
+	requestMatcher, err := matcher.New(pattern.Match)
+	if err != nil {
+		return nil, err
+	}
+	compiled.matcher = requestMatcher
+
+	return compiled, nil
+}
+
+func (compiled *compiledPattern) mismatches(entry Entry) []string {
+	var mismatches []string
+	if compiled.method != "" && compiled.method != entry.Method {
+		mismatches = append(mismatches, "method")
+	}
+
+	if compiled.path != "" && compiled.path != entry.Path {
+		mismatches = append(mismatches, "path")
+	}
+
+	if compiled.pathRegex != nil && !compiled.pathRegex.MatchString(entry.Path) {
+		mismatches = append(mismatches, "path")
+	}
+
+	return append(mismatches, compiled.matcher.Mismatches(newEntryRequest(entry))...)
+}
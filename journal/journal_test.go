@@ -0,0 +1,150 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func TestJournal_Add(t *testing.T) {
+	t.Run("happy path - drops the oldest entries when full", func(t *testing.T) {
+		journal := New(2, 0)
+		journal.Add(Entry{ID: "1"})
+		journal.Add(Entry{ID: "2"})
+		journal.Add(Entry{ID: "3"})
+
+		entries := journal.List()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "2", entries[0].ID)
+		assert.Equal(t, "3", entries[1].ID)
+	})
+
+	t.Run("happy path - uses the default capacity when none is set", func(t *testing.T) {
+		journal := New(0, 0)
+
+		assert.Equal(t, DefaultCapacity, journal.capacity)
+		assert.Equal(t, DefaultMaxBodyBytes, journal.maxBodyBytes)
+	})
+}
+
+func TestJournal_Clear(t *testing.T) {
+	journal := New(10, 0)
+	journal.Add(Entry{ID: "1"})
+
+	journal.Clear()
+
+	assert.Empty(t, journal.List())
+}
+
+func TestJournal_Verify(t *testing.T) {
+	newJournal := func() *Journal {
+		journal := New(10, 0)
+		journal.Add(Entry{
+			ID:     "create-acme",
+			Method: "POST",
+			Path:   "/users",
+			Headers: map[string][]string{
+				"X-Tenant": {"acme"},
+			},
+			Body: `{"name":"john"}`,
+		})
+		journal.Add(Entry{
+			ID:     "create-globex",
+			Method: "POST",
+			Path:   "/users",
+			Headers: map[string][]string{
+				"X-Tenant": {"globex"},
+			},
+			Body: `{"name":"jane"}`,
+		})
+		journal.Add(Entry{
+			ID:     "list",
+			Method: "GET",
+			Path:   "/users",
+			Query:  "page=2",
+		})
+
+		return journal
+	}
+
+	t.Run("happy path - counts requests matching method and path", func(t *testing.T) {
+		result, err := newJournal().Verify(RequestPattern{Method: "post", Path: "/users"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Count)
+		require.Len(t, result.NearMisses, 1)
+		assert.Equal(t, "list", result.NearMisses[0].Entry.ID)
+		assert.Equal(t, []string{"method"}, result.NearMisses[0].Mismatches)
+	})
+
+	t.Run("happy path - ANY and empty fields match every request", func(t *testing.T) {
+		result, err := newJournal().Verify(RequestPattern{Method: config.MethodAny})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Count)
+		assert.Empty(t, result.NearMisses)
+	})
+
+	t.Run("happy path - applies header, query and body matchers", func(t *testing.T) {
+		result, err := newJournal().Verify(RequestPattern{
+			Method: "POST",
+			Match: &config.RouteMatch{
+				Headers: map[string]config.ValueMatcher{"x-tenant": {Equals: "acme"}},
+				Body:    []config.BodyMatcher{{JSONPath: "$.name", Equals: "john"}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Count)
+		require.Len(t, result.NearMisses, 2)
+		assert.Equal(t, "create-globex", result.NearMisses[0].Entry.ID)
+		assert.Equal(t, []string{"header x-tenant", "body $.name"}, result.NearMisses[0].Mismatches)
+		assert.Equal(t, "list", result.NearMisses[1].Entry.ID)
+	})
+
+	t.Run("happy path - matches path regex and query", func(t *testing.T) {
+		result, err := newJournal().Verify(RequestPattern{
+			PathRegex: "^/us",
+			Match: &config.RouteMatch{
+				Query: map[string]config.ValueMatcher{"page": {Equals: "2"}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Count)
+	})
+
+	t.Run("happy path - limits near misses to the closest ones", func(t *testing.T) {
+		journal := New(10, 0)
+		for range 5 {
+			journal.Add(Entry{Method: "GET", Path: "/other"})
+		}
+		journal.Add(Entry{ID: "closest", Method: "GET", Path: "/users"})
+
+		result, err := journal.Verify(RequestPattern{Method: "POST", Path: "/users"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Count)
+		require.Len(t, result.NearMisses, DefaultNearMissLimit)
+		assert.Equal(t, "closest", result.NearMisses[0].Entry.ID)
+	})
+
+	t.Run("error path - invalid path regex", func(t *testing.T) {
+		_, err := newJournal().Verify(RequestPattern{PathRegex: "("})
+
+		assert.ErrorContains(t, err, "invalid path regex")
+	})
+
+	t.Run("error path - invalid matcher", func(t *testing.T) {
+		_, err := newJournal().Verify(RequestPattern{
+			Match: &config.RouteMatch{
+				Headers: map[string]config.ValueMatcher{"X-Tenant": {Regex: "("}},
+			},
+		})
+
+		assert.Error(t, err)
+	})
+}
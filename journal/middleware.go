@@ -0,0 +1,62 @@
+package journal
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+)
+
+const adminPathPrefix = "/_inzibat/"
+
+// NewMiddleware records every request except admin ones (/_inzibat/*) into
+// the journal once it has been handled.
+func NewMiddleware(journal *Journal) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if strings.HasPrefix(ctx.Path(), adminPathPrefix) {
+			return ctx.Next()
+		}
+
+		entry := Entry{
+			ID:        uuid.NewString(),
+			Timestamp: time.Now(),
+			Method:    utils.CopyString(ctx.Method()),
+			Path:      utils.CopyString(ctx.Path()),
+			Query:     string(ctx.Request().URI().QueryString()),
+			Headers:   captureHeaders(ctx),
+			Body:      captureBody(ctx.Body(), journal.maxBodyBytes),
+		}
+
+		err := ctx.Next()
+
+		entry.StatusCode = ctx.Response().StatusCode()
+		journal.Add(entry)
+
+		return err
+	}
+}
+
+func captureHeaders(ctx *fiber.Ctx) map[string][]string {
+	headers := map[string][]string{}
+	for key, value := range ctx.Request().Header.All() {
+		headerKey := http.CanonicalHeaderKey(string(key))
+		headers[headerKey] = append(headers[headerKey], string(value))
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	return headers
+}
+
+func captureBody(body []byte, maxBodyBytes int) string {
+	if len(body) > maxBodyBytes {
+		body = body[:maxBodyBytes]
+	}
+
+	return string(body)
+}
@@ -0,0 +1,75 @@
+package journal
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMiddleware(t *testing.T) {
+	newApp := func() (*fiber.App, *Journal) {
+		journal := New(10, 0)
+		app := fiber.New()
+		app.Use(NewMiddleware(journal))
+		app.Post("/users", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusCreated)
+		})
+		app.Get("/_inzibat/journal", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+
+		return app, journal
+	}
+
+	t.Run("happy path - records the request and response status", func(t *testing.T) {
+		app, journal := newApp()
+
+		request := httptest.NewRequest(fiber.MethodPost, "/users?page=2", strings.NewReader(`{"name":"john"}`))
+		request.Header.Set("X-Tenant", "acme")
+		_, err := app.Test(request)
+		require.NoError(t, err)
+
+		entries := journal.List()
+		require.Len(t, entries, 1)
+		assert.NotEmpty(t, entries[0].ID)
+		assert.Equal(t, fiber.MethodPost, entries[0].Method)
+		assert.Equal(t, "/users", entries[0].Path)
+		assert.Equal(t, "page=2", entries[0].Query)
+		assert.Equal(t, []string{"acme"}, entries[0].Headers["X-Tenant"])
+		assert.Equal(t, `{"name":"john"}`, entries[0].Body)
+		assert.Equal(t, fiber.StatusCreated, entries[0].StatusCode)
+	})
+
+	t.Run("happy path - skips admin requests", func(t *testing.T) {
+		app, journal := newApp()
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/_inzibat/journal", nil))
+		require.NoError(t, err)
+
+		assert.Empty(t, journal.List())
+	})
+
+	t.Run("happy path - truncates bodies to the size of the journal", func(t *testing.T) {
+		journal := New(10, 4)
+		app := fiber.New()
+		app.Use(NewMiddleware(journal))
+		app.Post("/users", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusCreated)
+		})
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader(`{"name":"john"}`)))
+		require.NoError(t, err)
+
+		entries := journal.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, `{"na`, entries[0].Body)
+	})
+
+	t.Run("happy path - truncates large bodies", func(t *testing.T) {
+		assert.Len(t, captureBody(make([]byte, DefaultMaxBodyBytes+1), DefaultMaxBodyBytes), DefaultMaxBodyBytes)
+	})
+}
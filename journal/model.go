@@ -0,0 +1,53 @@
+package journal
+
+import (
+	"time"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+// DefaultCapacity is the number of requests the journal keeps when the config
+// does not set journalSize.
+const DefaultCapacity = 1000
+
+// DefaultMaxBodyBytes is the size a request body is truncated to in the
+// journal when the config does not set journalMaxBodyBytes. It keeps a full
+// journal in the tens of megabytes.
+const DefaultMaxBodyBytes = 64 << 10
+
+// DefaultNearMissLimit is the number of closest non-matching requests returned
+// by a verification.
+const DefaultNearMissLimit = 3
+
+// Entry is a request received by the server.
+type Entry struct {
+	ID         string              `json:"id"`
+	Timestamp  time.Time           `json:"timestamp"`
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Query      string              `json:"query,omitempty"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body,omitempty"`
+	StatusCode int                 `json:"statusCode"`
+}
+
+// RequestPattern describes the requests to count. Empty fields match any
+// request; match uses the same predicates as route matchers.
+type RequestPattern struct {
+	Method    string             `json:"method,omitempty"`
+	Path      string             `json:"path,omitempty"`
+	PathRegex string             `json:"pathRegex,omitempty"`
+	Match     *config.RouteMatch `json:"match,omitempty"`
+}
+
+type VerifyResult struct {
+	Count      int        `json:"count"`
+	NearMisses []NearMiss `json:"nearMisses"`
+}
+
+// NearMiss is a request that failed the pattern, with the parts that did not
+// match, such as "method", "path" or "header X-Tenant".
+type NearMiss struct {
+	Entry      Entry    `json:"entry"`
+	Mismatches []string `json:"mismatches"`
+}
@@ -0,0 +1,72 @@
+package journal
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/goccy/go-json"
+)
+
+// entryRequest adapts a journal entry to the matcher.Request interface.
+type entryRequest struct {
+	entry   Entry
+	headers http.Header
+	query   url.Values
+}
+
+func newEntryRequest(entry Entry) *entryRequest {
+	query, _ := url.ParseQuery(entry.Query)
+
+	return &entryRequest{
+		entry:   entry,
+		headers: http.Header(entry.Headers),
+		query:   query,
+	}
+}
+
+func (request *entryRequest) Header(name string) (string, bool) {
+	values := request.headers.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+func (request *entryRequest) Query(name string) (string, bool) {
+	if !request.query.Has(name) {
+		return "", false
+	}
+
+	return request.query.Get(name), true
+}
+
+func (request *entryRequest) Cookie(name string) (string, bool) {
+	for _, line := range request.headers.Values("Cookie") {
+		cookies, err := http.ParseCookie(line)
+		if err != nil {
+			continue
+		}
+
+		for _, cookie := range cookies {
+			if cookie.Name == name {
+				return cookie.Value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func (request *entryRequest) JSONBody() (any, bool) {
+	if request.entry.Body == "" {
+		return nil, false
+	}
+
+	var document any
+	if err := json.Unmarshal([]byte(request.entry.Body), &document); err != nil {
+		return nil, false
+	}
+
+	return document, true
+}
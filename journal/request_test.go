@@ -0,0 +1,57 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryRequest(t *testing.T) {
+	request := newEntryRequest(Entry{
+		Query: "page=2&empty=",
+		Headers: map[string][]string{
+			"X-Tenant": {"acme"},
+			"Cookie":   {"session=abc; theme=dark"},
+		},
+		Body: `{"name":"john"}`,
+	})
+
+	t.Run("happy path - looks headers up case-insensitively", func(t *testing.T) {
+		value, ok := request.Header("x-tenant")
+		assert.True(t, ok)
+		assert.Equal(t, "acme", value)
+
+		_, ok = request.Header("X-Missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("happy path - reads query parameters", func(t *testing.T) {
+		value, ok := request.Query("page")
+		assert.True(t, ok)
+		assert.Equal(t, "2", value)
+
+		value, ok = request.Query("empty")
+		assert.True(t, ok)
+		assert.Empty(t, value)
+	})
+
+	t.Run("happy path - reads cookies", func(t *testing.T) {
+		value, ok := request.Cookie("theme")
+		assert.True(t, ok)
+		assert.Equal(t, "dark", value)
+
+		_, ok = request.Cookie("missing")
+		assert.False(t, ok)
+	})
+
+	t.Run("happy path - decodes the JSON body", func(t *testing.T) {
+		document, ok := request.JSONBody()
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"name": "john"}, document)
+	})
+
+	t.Run("error path - body is not JSON", func(t *testing.T) {
+		_, ok := newEntryRequest(Entry{Body: "plain"}).JSONBody()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,68 @@
+package matcher
+
+import (
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+)
+
+const jsonBodyLocalKey = "inzibat.matcher.jsonBody"
+
+type cachedJSONBody struct {
+	document any
+	ok       bool
+}
+
+// FiberRequest adapts a Fiber context to the Request interface.
+type FiberRequest struct {
+	ctx *fiber.Ctx
+}
+
+func NewFiberRequest(ctx *fiber.Ctx) *FiberRequest {
+	return &FiberRequest{ctx: ctx}
+}
+
+func (request *FiberRequest) Header(name string) (string, bool) {
+	value := request.ctx.Request().Header.Peek(name)
+	if value == nil {
+		return "", false
+	}
+
+	return string(value), true
+}
+
+func (request *FiberRequest) Query(name string) (string, bool) {
+	queryArgs := request.ctx.Context().QueryArgs()
+	if !queryArgs.Has(name) {
+		return "", false
+	}
+
+	return string(queryArgs.Peek(name)), true
+}
+
+func (request *FiberRequest) Cookie(name string) (string, bool) {
+	value := request.ctx.Request().Header.Cookie(name)
+	if value == nil {
+		return "", false
+	}
+
+	return string(value), true
+}
+
+func (request *FiberRequest) JSONBody() (any, bool) {
+	return JSONBody(request.ctx)
+}
+
+// JSONBody decodes the request body as JSON once per request and caches the result
+// in the context locals, so several matchers on one path share a single decode.
+func JSONBody(ctx *fiber.Ctx) (any, bool) {
+	if cached, ok := ctx.Locals(jsonBodyLocalKey).(cachedJSONBody); ok {
+		return cached.document, cached.ok
+	}
+
+	var document any
+	decoded := len(ctx.Body()) > 0 && json.Unmarshal(ctx.Body(), &document) == nil
+
+	ctx.Locals(jsonBodyLocalKey, cachedJSONBody{document: document, ok: decoded})
+
+	return document, decoded
+}
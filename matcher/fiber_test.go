@@ -0,0 +1,65 @@
+package matcher
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiberRequest(t *testing.T) {
+	t.Run("happy path - reads headers, query, cookies and body", func(t *testing.T) {
+		var (
+			header, query, cookie string
+			headerOk, missingOk   bool
+			body                  any
+			bodyOk                bool
+		)
+
+		fiberApp := fiber.New()
+		fiberApp.Post("/users", func(ctx *fiber.Ctx) error {
+			request := NewFiberRequest(ctx)
+			header, headerOk = request.Header("X-Tenant")
+			_, missingOk = request.Header("X-Missing")
+			query, _ = request.Query("page")
+			cookie, _ = request.Cookie("session")
+			body, bodyOk = request.JSONBody()
+			return nil
+		})
+
+		request := httptest.NewRequest(fiber.MethodPost, "/users?page=2", strings.NewReader(`{"id":1}`))
+		request.Header.Set("X-Tenant", "acme")
+		request.Header.Set("Cookie", "session=abc")
+
+		_, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		assert.Equal(t, "acme", header)
+		assert.True(t, headerOk)
+		assert.False(t, missingOk)
+		assert.Equal(t, "2", query)
+		assert.Equal(t, "abc", cookie)
+		assert.True(t, bodyOk)
+		assert.Equal(t, map[string]any{"id": float64(1)}, body)
+	})
+
+	t.Run("happy path - non json body is reported as missing", func(t *testing.T) {
+		var bodyOk bool
+
+		fiberApp := fiber.New()
+		fiberApp.Post("/users", func(ctx *fiber.Ctx) error {
+			_, bodyOk = JSONBody(ctx)
+			_, bodyOk = JSONBody(ctx)
+			return nil
+		})
+
+		request := httptest.NewRequest(fiber.MethodPost, "/users", strings.NewReader("plain text"))
+		_, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		assert.False(t, bodyOk)
+	})
+}
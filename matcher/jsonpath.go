@@ -0,0 +1,98 @@
+package matcher
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// compileJSONPath parses the supported JSONPath subset: a leading "$" followed by
+// ".field", "['field']" and "[index]" segments, e.g. "$.items[0].id".
+func compileJSONPath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %q must start with '$'", path)
+	}
+
+	var segments []pathSegment
+	rest := path[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("json path %q has an empty field name", path)
+			}
+
+			segments = append(segments, pathSegment{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("json path %q has an unclosed bracket", path)
+			}
+
+			segment, err := parseBracketSegment(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("json path %q: %w", path, err)
+			}
+
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("json path %q has an unexpected character %q", path, rest[0])
+		}
+	}
+
+	return segments, nil
+}
+
+func parseBracketSegment(content string) (pathSegment, error) {
+	if len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0] {
+		return pathSegment{key: content[1 : len(content)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(content)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("invalid array index %q", content)
+	}
+
+	return pathSegment{index: index, isIndex: true}, nil
+}
+
+func lookupJSONPath(document any, segments []pathSegment) (any, bool) {
+	current := document
+	for _, segment := range segments {
+		if segment.isIndex {
+			array, ok := current.([]any)
+			if !ok || segment.index >= len(array) {
+				return nil, false
+			}
+			current = array[segment.index]
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		value, exists := object[segment.key]
+		if !exists {
+			return nil, false
+		}
+		current = value
+	}
+
+	return current, true
+}
@@ -0,0 +1,81 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileJSONPath(t *testing.T) {
+	t.Run("happy path - parses fields, quoted fields and indexes", func(t *testing.T) {
+		segments, err := compileJSONPath(`$.items[1]['display name'].id`)
+
+		require.NoError(t, err)
+		assert.Equal(t, []pathSegment{
+			{key: "items"},
+			{index: 1, isIndex: true},
+			{key: "display name"},
+			{key: "id"},
+		}, segments)
+	})
+
+	t.Run("happy path - root only", func(t *testing.T) {
+		segments, err := compileJSONPath("$")
+
+		require.NoError(t, err)
+		assert.Empty(t, segments)
+	})
+
+	t.Run("error path - invalid paths", func(t *testing.T) {
+		testCases := []string{
+			"items.id",
+			"$..id",
+			"$.items[",
+			"$.items[-1]",
+			"$.items[abc]",
+			"$items",
+		}
+
+		for _, path := range testCases {
+			_, err := compileJSONPath(path)
+			assert.Error(t, err, path)
+		}
+	})
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	document := map[string]any{
+		"user": map[string]any{
+			"id":    float64(7),
+			"roles": []any{"admin", "viewer"},
+		},
+	}
+
+	t.Run("happy path - finds nested values", func(t *testing.T) {
+		segments, err := compileJSONPath("$.user.roles[1]")
+		require.NoError(t, err)
+
+		value, exists := lookupJSONPath(document, segments)
+
+		assert.True(t, exists)
+		assert.Equal(t, "viewer", value)
+	})
+
+	t.Run("happy path - reports missing values", func(t *testing.T) {
+		testCases := []string{
+			"$.user.name",
+			"$.user.roles[5]",
+			"$.user.id.value",
+			"$.user[0]",
+		}
+
+		for _, path := range testCases {
+			segments, err := compileJSONPath(path)
+			require.NoError(t, err)
+
+			_, exists := lookupJSONPath(document, segments)
+			assert.False(t, exists, path)
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+// RequestMatcher evaluates the predicates of a route's match block.
+// All predicates must hold for a request to match. A nil matcher matches every request.
+type RequestMatcher struct {
+	headers []valueRule
+	query   []valueRule
+	cookies []valueRule
+	body    []bodyRule
+}
+
+type valueRule struct {
+	name     string
+	equals   string
+	contains string
+	regex    *regexp.Regexp
+}
+
+type bodyRule struct {
+	valueRule
+	segments []pathSegment
+	exists   *bool
+}
+
+// New compiles the given match block. Regexes and JSON paths are compiled once here,
+// so a nil error means the matcher can be evaluated without further failures.
+func New(match *config.RouteMatch) (*RequestMatcher, error) {
+	if match == nil {
+		return nil, nil
+	}
+
+	headers, err := compileValueRules(match.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header matcher: %w", err)
+	}
+
+	query, err := compileValueRules(match.Query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query matcher: %w", err)
+	}
+
+	cookies, err := compileValueRules(match.Cookies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cookie matcher: %w", err)
+	}
+
+	body := make([]bodyRule, 0, len(match.Body))
+	for _, bodyMatcher := range match.Body {
+		rule, err := compileBodyRule(bodyMatcher)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body matcher: %w", err)
+		}
+		body = append(body, rule)
+	}
+
+	return &RequestMatcher{
+		headers: headers,
+		query:   query,
+		cookies: cookies,
+		body:    body,
+	}, nil
+}
+
+// Matches reports whether the request satisfies every predicate of the matcher.
+func (requestMatcher *RequestMatcher) Matches(request Request) bool {
+	if requestMatcher == nil {
+		return true
+	}
+
+	return matchValueRules(requestMatcher.headers, request.Header) &&
+		matchValueRules(requestMatcher.query, request.Query) &&
+		matchValueRules(requestMatcher.cookies, request.Cookie) &&
+		requestMatcher.matchBody(request)
+}
+
+// Mismatches lists the predicates the request fails, such as "header X-Tenant"
+// or "body $.name". It is empty when the request matches.
+func (requestMatcher *RequestMatcher) Mismatches(request Request) []string {
+	if requestMatcher == nil {
+		return nil
+	}
+
+	var mismatches []string
+	mismatches = appendValueMismatches(mismatches, "header", requestMatcher.headers, request.Header)
+	mismatches = appendValueMismatches(mismatches, "query", requestMatcher.query, request.Query)
+	mismatches = appendValueMismatches(mismatches, "cookie", requestMatcher.cookies, request.Cookie)
+
+	if len(requestMatcher.body) > 0 {
+		document, decoded := request.JSONBody()
+		for _, rule := range requestMatcher.body {
+			if !rule.matchesDocument(document, decoded) {
+				mismatches = append(mismatches, "body "+rule.name)
+			}
+		}
+	}
+
+	return mismatches
+}
+
+func (requestMatcher *RequestMatcher) matchBody(request Request) bool {
+	if len(requestMatcher.body) == 0 {
+		return true
+	}
+
+	document, decoded := request.JSONBody()
+	for _, rule := range requestMatcher.body {
+		if !rule.matchesDocument(document, decoded) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (rule bodyRule) matchesDocument(document any, decoded bool) bool {
+	var (
+		value  any
+		exists bool
+	)
+	if decoded {
+		value, exists = lookupJSONPath(document, rule.segments)
+	}
+
+	if rule.exists != nil {
+		if exists != *rule.exists {
+			return false
+		}
+		if !exists {
+			return true
+		}
+	}
+
+	return exists && rule.matches(StringifyJSONValue(value))
+}
+
+func compileValueRules(valueMatchers map[string]config.ValueMatcher) ([]valueRule, error) {
+	rules := make([]valueRule, 0, len(valueMatchers))
+	for name, valueMatcher := range valueMatchers {
+		rule, err := compileValueRule(name, valueMatcher)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].name < rules[j].name
+	})
+
+	return rules, nil
+}
+
+func compileValueRule(name string, valueMatcher config.ValueMatcher) (valueRule, error) {
+	rule := valueRule{
+		name:     name,
+		equals:   valueMatcher.Equals,
+		contains: valueMatcher.Contains,
+	}
+
+	if valueMatcher.Regex != "" {
+		regex, err := regexp.Compile(valueMatcher.Regex)
+		if err != nil {
+			return valueRule{}, fmt.Errorf("%s: %w", name, err)
+		}
+		rule.regex = regex
+	}
+
+	return rule, nil
+}
+
+func compileBodyRule(bodyMatcher config.BodyMatcher) (bodyRule, error) {
+	segments, err := compileJSONPath(bodyMatcher.JSONPath)
+	if err != nil {
+		return bodyRule{}, err
+	}
+
+	rule, err := compileValueRule(bodyMatcher.JSONPath, bodyMatcher.ValueMatcher())
+	if err != nil {
+		return bodyRule{}, err
+	}
+
+	return bodyRule{
+		valueRule: rule,
+		segments:  segments,
+		exists:    bodyMatcher.Exists,
+	}, nil
+}
+
+func matchValueRules(rules []valueRule, lookup func(name string) (string, bool)) bool {
+	for _, rule := range rules {
+		value, exists := lookup(rule.name)
+		if !exists || !rule.matches(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func appendValueMismatches(
+	mismatches []string,
+	kind string,
+	rules []valueRule,
+	lookup func(name string) (string, bool),
+) []string {
+	for _, rule := range rules {
+		value, exists := lookup(rule.name)
+		if !exists || !rule.matches(value) {
+			mismatches = append(mismatches, kind+" "+rule.name)
+		}
+	}
+
+	return mismatches
+}
+
+func (rule valueRule) matches(value string) bool {
+	if rule.equals != "" && value != rule.equals {
+		return false
+	}
+
+	if rule.contains != "" && !strings.Contains(value, rule.contains) {
+		return false
+	}
+
+	if rule.regex != nil && !rule.regex.MatchString(value) {
+		return false
+	}
+
+	return true
+}
+
+// StringifyJSONValue renders a decoded JSON value the way body matchers
+// compare it: strings as they are, anything else as JSON.
+func StringifyJSONValue(value any) string {
+	if stringValue, ok := value.(string); ok {
+		return stringValue
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,186 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+type fakeRequest struct {
+	headers map[string]string
+	query   map[string]string
+	cookies map[string]string
+	body    any
+}
+
+func (request fakeRequest) Header(name string) (string, bool) {
+	value, ok := request.headers[name]
+	return value, ok
+}
+
+func (request fakeRequest) Query(name string) (string, bool) {
+	value, ok := request.query[name]
+	return value, ok
+}
+
+func (request fakeRequest) Cookie(name string) (string, bool) {
+	value, ok := request.cookies[name]
+	return value, ok
+}
+
+func (request fakeRequest) JSONBody() (any, bool) {
+	return request.body, request.body != nil
+}
+
+func TestNew(t *testing.T) {
+	t.Run("happy path - nil match returns nil matcher", func(t *testing.T) {
+		requestMatcher, err := New(nil)
+
+		assert.NoError(t, err)
+		assert.Nil(t, requestMatcher)
+		assert.True(t, requestMatcher.Matches(fakeRequest{}))
+	})
+
+	t.Run("error path - invalid regex", func(t *testing.T) {
+		_, err := New(&config.RouteMatch{
+			Headers: map[string]config.ValueMatcher{
+				"X-Tenant": {Regex: "("},
+			},
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid header matcher")
+	})
+
+	t.Run("error path - invalid json path", func(t *testing.T) {
+		_, err := New(&config.RouteMatch{
+			Body: []config.BodyMatcher{
+				{JSONPath: "$.items["},
+			},
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid body matcher")
+	})
+}
+
+func TestRequestMatcher_Matches(t *testing.T) {
+	requestMatcher, err := New(&config.RouteMatch{
+		Headers: map[string]config.ValueMatcher{
+			"X-Tenant": {Equals: "acme"},
+		},
+		Query: map[string]config.ValueMatcher{
+			"page": {Regex: `^\d+$`},
+		},
+		Cookies: map[string]config.ValueMatcher{
+			"session": {Contains: "beta"},
+		},
+		Body: []config.BodyMatcher{
+			{JSONPath: "$.user.id", Equals: "42"},
+			{JSONPath: "$.user.email", Exists: config.BoolPointer(true)},
+			{JSONPath: "$.user.deletedAt", Exists: config.BoolPointer(false)},
+		},
+	})
+	require.NoError(t, err)
+
+	matchingRequest := func() fakeRequest {
+		return fakeRequest{
+			headers: map[string]string{"X-Tenant": "acme"},
+			query:   map[string]string{"page": "3"},
+			cookies: map[string]string{"session": "user-beta-1"},
+			body: map[string]any{
+				"user": map[string]any{
+					"id":    float64(42),
+					"email": "user@example.com",
+				},
+			},
+		}
+	}
+
+	t.Run("happy path - all predicates hold", func(t *testing.T) {
+		assert.True(t, requestMatcher.Matches(matchingRequest()))
+	})
+
+	t.Run("happy path - any failing predicate rejects the request", func(t *testing.T) {
+		testCases := map[string]func(request *fakeRequest){
+			"header differs": func(request *fakeRequest) {
+				request.headers["X-Tenant"] = "other"
+			},
+			"header missing": func(request *fakeRequest) {
+				delete(request.headers, "X-Tenant")
+			},
+			"query regex fails": func(request *fakeRequest) {
+				request.query["page"] = "three"
+			},
+			"cookie missing": func(request *fakeRequest) {
+				request.cookies = nil
+			},
+			"body value differs": func(request *fakeRequest) {
+				request.body.(map[string]any)["user"].(map[string]any)["id"] = float64(7)
+			},
+			"body field must not exist": func(request *fakeRequest) {
+				request.body.(map[string]any)["user"].(map[string]any)["deletedAt"] = "yesterday"
+			},
+			"body is not json": func(request *fakeRequest) {
+				request.body = nil
+			},
+		}
+
+		for name, mutate := range testCases {
+			request := matchingRequest()
+			mutate(&request)
+			assert.False(t, requestMatcher.Matches(request), name)
+		}
+	})
+}
+
+func TestRequestMatcher_Mismatches(t *testing.T) {
+	requestMatcher, err := New(&config.RouteMatch{
+		Headers: map[string]config.ValueMatcher{
+			"X-Tenant": {Equals: "acme"},
+		},
+		Query: map[string]config.ValueMatcher{
+			"page": {Regex: `^\d+$`},
+		},
+		Body: []config.BodyMatcher{
+			{JSONPath: "$.name", Equals: "lynicis"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("happy path - matching request has no mismatches", func(t *testing.T) {
+		mismatches := requestMatcher.Mismatches(fakeRequest{
+			headers: map[string]string{"X-Tenant": "acme"},
+			query:   map[string]string{"page": "1"},
+			body:    map[string]any{"name": "lynicis"},
+		})
+
+		assert.Empty(t, mismatches)
+	})
+
+	t.Run("happy path - lists every failing predicate", func(t *testing.T) {
+		mismatches := requestMatcher.Mismatches(fakeRequest{
+			headers: map[string]string{"X-Tenant": "globex"},
+			query:   map[string]string{"page": "1"},
+		})
+
+		assert.Equal(t, []string{"header X-Tenant", "body $.name"}, mismatches)
+	})
+
+	t.Run("happy path - nil matcher has no mismatches", func(t *testing.T) {
+		var nilMatcher *RequestMatcher
+
+		assert.Nil(t, nilMatcher.Mismatches(fakeRequest{}))
+	})
+}
+
+func TestStringifyJSONValue(t *testing.T) {
+	assert.Equal(t, "text", StringifyJSONValue("text"))
+	assert.Equal(t, "1.5", StringifyJSONValue(1.5))
+	assert.Equal(t, "true", StringifyJSONValue(true))
+	assert.Equal(t, "null", StringifyJSONValue(nil))
+	assert.Equal(t, `{"a":1}`, StringifyJSONValue(map[string]any{"a": 1}))
+}
@@ -0,0 +1,10 @@
+package matcher
+
+// Request is the view of an incoming HTTP request that route matchers evaluate.
+// Each lookup reports whether the value was present at all.
+type Request interface {
+	Header(name string) (string, bool)
+	Query(name string) (string, bool)
+	Cookie(name string) (string, bool)
+	JSONBody() (any, bool)
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func RegisterAdminRoutes(app *fiber.App, registry *Registry) {
+	app.Get("/_inzibat/metrics", metricsHandler(registry))
+}
+
+func metricsHandler(registry *Registry) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		ctx.Set(fiber.HeaderContentType, contentType)
+		_, err := registry.WriteTo(ctx)
+		return err
+	}
+}
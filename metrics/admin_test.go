@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterAdminRoutes(t *testing.T) {
+	t.Run("happy path - serves the metrics in the Prometheus text format", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.ObserveRequest(fiber.MethodGet, "/users", fiber.StatusOK, time.Millisecond)
+		app := fiber.New()
+		RegisterAdminRoutes(app, registry)
+
+		response, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/_inzibat/metrics", nil))
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", response.Header.Get(fiber.HeaderContentType))
+		assert.Contains(t, string(body), `inzibat_http_requests_total{method="GET",route="/users",status_code="200"} 1`)
+	})
+}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lynicis/inzibat/handler"
+)
+
+// DurationBuckets are the upper bounds, in seconds, of the request duration
+// histogram.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry collects the metrics of a server and writes them in the Prometheus
+// text format.
+type Registry struct {
+	mu                        sync.Mutex
+	requests                  map[string]uint64
+	requestDurations          map[string]*histogram
+	upstreamRequests          map[string]uint64
+	circuitBreakerTransitions map[string]uint64
+	circuitBreakerStore       *handler.CircuitBreakerStore
+}
+
+type histogram struct {
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		requests:                  make(map[string]uint64),
+		requestDurations:          make(map[string]*histogram),
+		upstreamRequests:          make(map[string]uint64),
+		circuitBreakerTransitions: make(map[string]uint64),
+	}
+}
+
+func (registry *Registry) ObserveRequest(method, route string, statusCode int, duration time.Duration) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.requests[formatLabels("method", method, "route", route, "status_code", strconv.Itoa(statusCode))]++
+
+	durationLabels := formatLabels("method", method, "route", route)
+	requestDuration, exists := registry.requestDurations[durationLabels]
+	if !exists {
+		requestDuration = &histogram{bucketCounts: make([]uint64, len(DurationBuckets))}
+		registry.requestDurations[durationLabels] = requestDuration
+	}
+
+	seconds := duration.Seconds()
+	for bucketIndex, upperBound := range DurationBuckets {
+		if seconds <= upperBound {
+			requestDuration.bucketCounts[bucketIndex]++
+		}
+	}
+	requestDuration.sum += seconds
+	requestDuration.count++
+}
+
+// ObserveUpstream counts an upstream request outcome. It matches
+// http.UpstreamObserver.
+func (registry *Registry) ObserveUpstream(upstream, outcome string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.upstreamRequests[formatLabels("upstream", upstream, "outcome", outcome)]++
+}
+
+// ObserveCircuitBreakerTransition counts a state change of a circuit breaker.
+// It matches handler.CircuitBreakerTransitionObserver.
+func (registry *Registry) ObserveCircuitBreakerTransition(routeKey string, from, to handler.CircuitBreakerState) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.circuitBreakerTransitions[formatLabels("route_key", routeKey, "from", string(from), "to", string(to))]++
+}
+
+// WatchCircuitBreakers exports the state of the breakers in store and counts
+// their transitions.
+func (registry *Registry) WatchCircuitBreakers(store *handler.CircuitBreakerStore) {
+	registry.mu.Lock()
+	registry.circuitBreakerStore = store
+	registry.mu.Unlock()
+
+	store.SetTransitionObserver(registry.ObserveCircuitBreakerTransition)
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format.
+func (registry *Registry) WriteTo(writer io.Writer) (int64, error) {
+	registry.mu.Lock()
+	circuitBreakerStore := registry.circuitBreakerStore
+	registry.mu.Unlock()
+
+	var circuitBreakerRecords []handler.CircuitBreakerRecord
+	if circuitBreakerStore != nil {
+		var err error
+		if circuitBreakerRecords, err = circuitBreakerStore.List(); err != nil {
+			return 0, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	registry.mu.Lock()
+	writeCounter(&buffer, "inzibat_http_requests_total", "Requests answered, by route and status code.", registry.requests)
+	writeHistogram(&buffer, "inzibat_http_request_duration_seconds", "Time taken to answer requests, by route.", registry.requestDurations)
+	writeCounter(&buffer, "inzibat_upstream_requests_total", "Upstream request outcomes, by upstream.", registry.upstreamRequests)
+	writeCounter(&buffer, "inzibat_circuit_breaker_transitions_total", "Circuit breaker state changes, by route key.", registry.circuitBreakerTransitions)
+	registry.mu.Unlock()
+	writeCircuitBreakerStates(&buffer, circuitBreakerRecords)
+
+	return buffer.WriteTo(writer)
+}
+
+func writeCounter(buffer *bytes.Buffer, name, help string, values map[string]uint64) {
+	writeHeader(buffer, name, help, "counter")
+	for _, labels := range sortedKeys(values) {
+		fmt.Fprintf(buffer, "%s{%s} %d\n", name, labels, values[labels])
+	}
+}
+
+func writeHistogram(buffer *bytes.Buffer, name, help string, histograms map[string]*histogram) {
+	writeHeader(buffer, name, help, "histogram")
+	for _, labels := range sortedKeys(histograms) {
+		requestDuration := histograms[labels]
+		for bucketIndex, upperBound := range DurationBuckets {
+			fmt.Fprintf(buffer, "%s_bucket{%s,le=\"%s\"} %d\n",
+				name, labels, formatFloat(upperBound), requestDuration.bucketCounts[bucketIndex])
+		}
+		fmt.Fprintf(buffer, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, requestDuration.count)
+		fmt.Fprintf(buffer, "%s_sum{%s} %s\n", name, labels, formatFloat(requestDuration.sum))
+		fmt.Fprintf(buffer, "%s_count{%s} %d\n", name, labels, requestDuration.count)
+	}
+}
+
+func writeCircuitBreakerStates(buffer *bytes.Buffer, records []handler.CircuitBreakerRecord) {
+	name := "inzibat_circuit_breaker_state"
+	writeHeader(buffer, name, "Current circuit breaker state, 1 for the state the breaker is in.", "gauge")
+	for _, record := range records {
+		for _, state := range handler.CircuitBreakerStates {
+			value := 0
+			if record.State == state {
+				value = 1
+			}
+			fmt.Fprintf(buffer, "%s{%s} %d\n", name, formatLabels("route_key", record.RouteKey, "state", string(state)), value)
+		}
+	}
+}
+
+func writeHeader(buffer *bytes.Buffer, name, help, metricType string) {
+	fmt.Fprintf(buffer, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// formatLabels renders name and value pairs as the label set of a sample.
+func formatLabels(namesAndValues ...string) string {
+	labels := make([]string, 0, len(namesAndValues)/2)
+	for labelIndex := 0; labelIndex+1 < len(namesAndValues); labelIndex += 2 {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, namesAndValues[labelIndex], labelValueEscaper.Replace(namesAndValues[labelIndex+1])))
+	}
+
+	return strings.Join(labels, ",")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
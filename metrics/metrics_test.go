@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+)
+
+func writeMetrics(t *testing.T, registry *Registry) string {
+	t.Helper()
+
+	var builder strings.Builder
+	_, err := registry.WriteTo(&builder)
+	require.NoError(t, err)
+
+	return builder.String()
+}
+
+func TestRegistry_ObserveRequest(t *testing.T) {
+	t.Run("happy path - counts requests and fills the duration buckets", func(t *testing.T) {
+		registry := NewRegistry()
+
+		registry.ObserveRequest("GET", "/users", 200, 20*time.Millisecond)
+		registry.ObserveRequest("GET", "/users", 200, 2*time.Second)
+		registry.ObserveRequest("GET", "/users", 503, time.Millisecond)
+
+		output := writeMetrics(t, registry)
+
+		assert.Contains(t, output, "# TYPE inzibat_http_requests_total counter\n")
+		assert.Contains(t, output, `inzibat_http_requests_total{method="GET",route="/users",status_code="200"} 2`)
+		assert.Contains(t, output, `inzibat_http_requests_total{method="GET",route="/users",status_code="503"} 1`)
+		assert.Contains(t, output, "# TYPE inzibat_http_request_duration_seconds histogram\n")
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/users",le="0.005"} 1`)
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/users",le="0.025"} 2`)
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/users",le="2.5"} 3`)
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/users",le="+Inf"} 3`)
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_sum{method="GET",route="/users"} 2.021`)
+		assert.Contains(t, output, `inzibat_http_request_duration_seconds_count{method="GET",route="/users"} 3`)
+	})
+
+	t.Run("happy path - escapes label values", func(t *testing.T) {
+		registry := NewRegistry()
+
+		registry.ObserveRequest("GET", "/say/\"hi\"\\\n", 200, 0)
+
+		assert.Contains(t, writeMetrics(t, registry), `route="/say/\"hi\"\\\n"`)
+	})
+}
+
+func TestRegistry_ObserveUpstream(t *testing.T) {
+	t.Run("happy path - counts outcomes by upstream", func(t *testing.T) {
+		registry := NewRegistry()
+
+		registry.ObserveUpstream("http://users:8080", "retry")
+		registry.ObserveUpstream("http://users:8080", "retry")
+		registry.ObserveUpstream("http://users:8080", "success")
+		registry.ObserveUpstream("http://orders:8080", "failure")
+
+		output := writeMetrics(t, registry)
+
+		assert.Contains(t, output, `inzibat_upstream_requests_total{upstream="http://users:8080",outcome="retry"} 2`)
+		assert.Contains(t, output, `inzibat_upstream_requests_total{upstream="http://users:8080",outcome="success"} 1`)
+		assert.Contains(t, output, `inzibat_upstream_requests_total{upstream="http://orders:8080",outcome="failure"} 1`)
+	})
+}
+
+func TestRegistry_WatchCircuitBreakers(t *testing.T) {
+	t.Run("happy path - exports breaker states and transitions", func(t *testing.T) {
+		breakerStore, err := handler.NewCircuitBreakerStore()
+		require.NoError(t, err)
+		registry := NewRegistry()
+		registry.WatchCircuitBreakers(breakerStore)
+
+		breakerConfig := config.CircuitBreakerConfig{
+			Enabled:          config.BoolPointer(true),
+			FailureThreshold: 1,
+			MinimumRequests:  1,
+			OpenTimeoutMs:    60000,
+		}
+		require.NoError(t, breakerStore.Seed("GET /users", breakerConfig))
+		require.NoError(t, breakerStore.Seed("GET /orders", breakerConfig))
+		require.NoError(t, breakerStore.OnFailure("GET /users"))
+
+		output := writeMetrics(t, registry)
+
+		assert.Contains(t, output, "# TYPE inzibat_circuit_breaker_state gauge\n")
+		assert.Contains(t, output, `inzibat_circuit_breaker_state{route_key="GET /users",state="open"} 1`)
+		assert.Contains(t, output, `inzibat_circuit_breaker_state{route_key="GET /users",state="closed"} 0`)
+		assert.Contains(t, output, `inzibat_circuit_breaker_state{route_key="GET /orders",state="closed"} 1`)
+		assert.Contains(t, output, `inzibat_circuit_breaker_transitions_total{route_key="GET /users",from="closed",to="open"} 1`)
+	})
+
+	t.Run("happy path - no breakers to export", func(t *testing.T) {
+		output := writeMetrics(t, NewRegistry())
+
+		assert.Contains(t, output, "# TYPE inzibat_circuit_breaker_state gauge\n")
+		assert.NotContains(t, output, "inzibat_circuit_breaker_state{")
+	})
+}
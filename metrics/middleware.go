@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/lynicis/inzibat/handler"
+)
+
+const (
+	adminPathPrefix = "/_inzibat/"
+	unmatchedRoute  = "unmatched"
+)
+
+type routeLocalKey struct{}
+
+// SetRoute labels the request with the path of the route answering it. The
+// label is kept on the underlying request, so it is seen by the middleware
+// even when the route is served by another app.
+func SetRoute(ctx *fiber.Ctx, routePath string) {
+	ctx.Locals(routeLocalKey{}, routePath)
+}
+
+// NewMiddleware counts and times every request except admin ones
+// (/_inzibat/*) once its response has been written, including the delay of
+// delayed routes.
+func NewMiddleware(registry *Registry) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if strings.HasPrefix(ctx.Path(), adminPathPrefix) {
+			return ctx.Next()
+		}
+
+		start := time.Now()
+		delayedWrite := handler.TrackDelayedWrite(ctx)
+		err := ctx.Next()
+
+		route, isRouted := ctx.Locals(routeLocalKey{}).(string)
+		if !isRouted {
+			route = unmatchedRoute
+		}
+		method := utils.CopyString(ctx.Method())
+		statusCode := ctx.Response().StatusCode()
+		delayedWrite.Then(func() {
+			registry.ObserveRequest(method, route, statusCode, time.Since(start))
+		})
+
+		return err
+	}
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+)
+
+func TestNewMiddleware(t *testing.T) {
+	newApp := func() (*fiber.App, *Registry) {
+		registry := NewRegistry()
+		routeApp := fiber.New()
+		routeApp.Post("/users/:id", func(ctx *fiber.Ctx) error {
+			SetRoute(ctx, "/users/:id")
+			return ctx.SendStatus(fiber.StatusCreated)
+		})
+
+		app := fiber.New()
+		app.Use(NewMiddleware(registry))
+		app.Get("/_inzibat/journal", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+		app.Use(func(ctx *fiber.Ctx) error {
+			routeApp.Handler()(ctx.Context())
+			return nil
+		})
+
+		return app, registry
+	}
+
+	t.Run("happy path - labels requests with the route set by another app", func(t *testing.T) {
+		app, registry := newApp()
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/users/42", nil))
+		require.NoError(t, err)
+
+		assert.Contains(t, writeMetrics(t, registry), `inzibat_http_requests_total{method="POST",route="/users/:id",status_code="201"} 1`)
+	})
+
+	t.Run("happy path - requests without a route are unmatched", func(t *testing.T) {
+		app, registry := newApp()
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/orders", nil))
+		require.NoError(t, err)
+
+		assert.Contains(t, writeMetrics(t, registry), `inzibat_http_requests_total{method="GET",route="unmatched",status_code="404"} 1`)
+	})
+
+	t.Run("happy path - skips admin requests", func(t *testing.T) {
+		app, registry := newApp()
+
+		_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/_inzibat/journal", nil))
+		require.NoError(t, err)
+
+		assert.NotContains(t, writeMetrics(t, registry), "inzibat_http_requests_total{")
+	})
+
+	t.Run("happy path - times delayed responses until they are written", func(t *testing.T) {
+		registry := NewRegistry()
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Use(NewMiddleware(registry))
+		app.Get("/slow", handler.WithDelay(&config.Delay{FixedMs: 150}, func(ctx *fiber.Ctx) error {
+			SetRoute(ctx, "/slow")
+			return ctx.SendString("slow")
+		}))
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go func() {
+			_ = app.Listener(listener)
+		}()
+		t.Cleanup(func() {
+			_ = app.Shutdown()
+		})
+
+		response, err := http.Get("http://" + listener.Addr().String() + "/slow")
+		require.NoError(t, err)
+		_, err = io.ReadAll(response.Body)
+		require.NoError(t, err)
+		require.NoError(t, response.Body.Close())
+
+		assert.Eventually(t, func() bool {
+			return strings.Contains(writeMetrics(t, registry), `inzibat_http_requests_total{method="GET",route="/slow",status_code="200"} 1`)
+		}, time.Second, 10*time.Millisecond)
+		metricsOutput := writeMetrics(t, registry)
+		assert.Contains(t, metricsOutput, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/slow",le="0.1"} 0`)
+		assert.Contains(t, metricsOutput, `inzibat_http_request_duration_seconds_bucket{method="GET",route="/slow",le="0.25"} 1`)
+	})
+}
@@ -1,42 +1,67 @@
 package recorder
 
 import (
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/goccy/go-json"
 
 	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/matcher"
 )
 
 // ConvertToInzibatConfig converts a recorded session into an inzibat mock configuration.
-// Duplicate (method, path) pairs are deduplicated — the last recording wins.
+// Recordings of one (method, path) pair that differ in query or JSON body become
+// separate routes matching on them, ahead of the catch-all route of the pair.
+// Duplicate recordings are deduplicated — the last recording wins.
 func ConvertToInzibatConfig(session RecordedSession, serverPort int) *config.Cfg {
 	if serverPort <= 0 {
 		serverPort = 8080
 	}
 
 	routeMap := map[string]*config.Route{}
-	routeOrder := []string{}
+	routeKeys := map[string][]string{}
+	endpointOrder := []string{}
 
 	for _, entry := range session.Entries {
-		key := entry.Request.Method + " " + entry.Request.Path
+		endpoint := entry.Request.Method + " " + entry.Request.Path
+		match := buildRouteMatch(entry.Request)
+		key := endpoint + " " + routeMatchKey(match)
 
 		route := &config.Route{
 			Method:       entry.Request.Method,
 			Path:         entry.Request.Path,
+			Match:        match,
 			FakeResponse: buildFakeResponse(entry.Response),
 		}
 
+		if _, exists := routeKeys[endpoint]; !exists {
+			endpointOrder = append(endpointOrder, endpoint)
+		}
 		if _, exists := routeMap[key]; !exists {
-			routeOrder = append(routeOrder, key)
+			routeKeys[endpoint] = append(routeKeys[endpoint], key)
 		}
 
 		routeMap[key] = route
 	}
 
-	routes := make([]config.Route, 0, len(routeOrder))
-	for _, key := range routeOrder {
-		routes = append(routes, *routeMap[key])
+	routes := make([]config.Route, 0, len(routeMap))
+	for _, endpoint := range endpointOrder {
+		var catchAll *config.Route
+		for _, key := range routeKeys[endpoint] {
+			if routeMap[key].Match == nil {
+				catchAll = routeMap[key]
+				continue
+			}
+			routes = append(routes, *routeMap[key])
+		}
+
+		if catchAll != nil {
+			routes = append(routes, *catchAll)
+		}
 	}
 
 	return &config.Cfg{
@@ -45,6 +70,92 @@ func ConvertToInzibatConfig(session RecordedSession, serverPort int) *config.Cfg
 	}
 }
 
+// buildRouteMatch matches the query parameters and the JSON body of the
+// recorded request: every top-level field of an object body, or the whole
+// body otherwise. String bodies are not matched on.
+func buildRouteMatch(req RecordedRequest) *config.RouteMatch {
+	match := &config.RouteMatch{}
+
+	for key, values := range req.Query {
+		if len(values) == 0 {
+			continue
+		}
+		if match.Query == nil {
+			match.Query = map[string]config.ValueMatcher{}
+		}
+		match.Query[key] = equalsValueMatcher(values[0])
+	}
+
+	var body any
+	if len(req.Body) > 0 && json.Unmarshal(req.Body, &body) == nil {
+		switch document := body.(type) {
+		case nil, string:
+		case map[string]any:
+			for _, key := range slices.Sorted(maps.Keys(document)) {
+				jsonPath, ok := fieldJSONPath(key)
+				if !ok {
+					continue
+				}
+				match.Body = append(match.Body, equalsBodyMatcher(jsonPath, document[key]))
+			}
+		default:
+			match.Body = append(match.Body, equalsBodyMatcher("$", document))
+		}
+	}
+
+	if len(match.Query) == 0 && len(match.Body) == 0 {
+		return nil
+	}
+
+	return match
+}
+
+// equalsValueMatcher matches value exactly. An empty equals matches anything,
+// so empty values are matched with a regex instead.
+func equalsValueMatcher(value string) config.ValueMatcher {
+	if value == "" {
+		return config.ValueMatcher{Regex: "^$"}
+	}
+
+	return config.ValueMatcher{Equals: value}
+}
+
+func equalsBodyMatcher(jsonPath string, value any) config.BodyMatcher {
+	valueMatcher := equalsValueMatcher(matcher.StringifyJSONValue(value))
+
+	return config.BodyMatcher{
+		JSONPath: jsonPath,
+		Equals:   valueMatcher.Equals,
+		Regex:    valueMatcher.Regex,
+	}
+}
+
+// fieldJSONPath returns the JSON path of a top-level field. Fields the path
+// syntax cannot express are reported as not ok.
+func fieldJSONPath(key string) (string, bool) {
+	if key == "" || strings.ContainsAny(key, "]'") {
+		return "", false
+	}
+	if strings.ContainsAny(key, ".[") {
+		return "$['" + key + "']", true
+	}
+
+	return "$." + key, true
+}
+
+func routeMatchKey(match *config.RouteMatch) string {
+	if match == nil {
+		return ""
+	}
+
+	encoded, err := json.Marshal(match)
+	if err != nil {
+		return fmt.Sprint(*match)
+	}
+
+	return string(encoded)
+}
+
 func buildFakeResponse(resp RecordedResponse) *config.FakeResponse {
 	fakeResponse := &config.FakeResponse{
 		StatusCode: resp.StatusCode,
@@ -55,21 +166,22 @@ func buildFakeResponse(resp RecordedResponse) *config.FakeResponse {
 		return fakeResponse
 	}
 
-	// Try to unmarshal as a JSON object for Body (map[string]any)
-	var bodyMap config.HttpBody
-	if err := json.Unmarshal(resp.Body, &bodyMap); err == nil && bodyMap != nil {
-		fakeResponse.Body = bodyMap
-		return fakeResponse
-	}
+	// JSON strings become BodyString, any other JSON value becomes Body
+	var body any
+	if err := json.Unmarshal(resp.Body, &body); err == nil {
+		if bodyStr, isString := body.(string); isString {
+			fakeResponse.BodyString = bodyStr
+			return fakeResponse
+		}
+		if body == nil {
+			body = config.NullBody
+		}
 
-	// Fall back to BodyString
-	var bodyStr string
-	if err := json.Unmarshal(resp.Body, &bodyStr); err == nil {
-		fakeResponse.BodyString = bodyStr
+		fakeResponse.Body = body
 		return fakeResponse
 	}
 
-	// Raw fallback: store the JSON literal as a string
+	// Raw fallback: store non-JSON bodies as a string
 	fakeResponse.BodyString = string(resp.Body)
 
 	return fakeResponse
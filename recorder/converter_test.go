@@ -7,6 +7,8 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
 )
 
 func TestConvertToInzibatConfig(t *testing.T) {
@@ -58,7 +60,7 @@ func TestConvertToInzibatConfig(t *testing.T) {
 
 		cfg := ConvertToInzibatConfig(session, 8080)
 		require.Len(t, cfg.Routes, 1)
-		assert.Equal(t, "v2", cfg.Routes[0].FakeResponse.Body["version"])
+		assert.Equal(t, map[string]any{"version": "v2"}, cfg.Routes[0].FakeResponse.Body)
 	})
 
 	t.Run("preserves different methods on same path", func(t *testing.T) {
@@ -183,6 +185,93 @@ func TestConvertToInzibatConfig(t *testing.T) {
 		assert.Equal(t, "/a", cfg.Routes[1].Path)
 		assert.Equal(t, "/b", cfg.Routes[2].Path)
 	})
+
+	t.Run("splits recordings by query and body", func(t *testing.T) {
+		session := RecordedSession{
+			Entries: []RecordedEntry{
+				{
+					Request:  RecordedRequest{Method: "GET", Path: "/users"},
+					Response: RecordedResponse{StatusCode: 200, Body: json.RawMessage(`"all"`)},
+				},
+				{
+					Request:  RecordedRequest{Method: "GET", Path: "/users", Query: map[string][]string{"page": {"2"}}},
+					Response: RecordedResponse{StatusCode: 200, Body: json.RawMessage(`"page 2"`)},
+				},
+				{
+					Request:  RecordedRequest{Method: "POST", Path: "/users", Body: json.RawMessage(`{"name":"ali","age":30}`)},
+					Response: RecordedResponse{StatusCode: 201, Body: json.RawMessage(`"ali"`)},
+				},
+				{
+					Request:  RecordedRequest{Method: "POST", Path: "/users", Body: json.RawMessage(`{"age":30,"name":"veli"}`)},
+					Response: RecordedResponse{StatusCode: 201, Body: json.RawMessage(`"veli"`)},
+				},
+				{
+					Request:  RecordedRequest{Method: "POST", Path: "/users", Body: json.RawMessage(`{"name":"ali","age":30}`)},
+					Response: RecordedResponse{StatusCode: 409, Body: json.RawMessage(`"ali again"`)},
+				},
+			},
+		}
+
+		cfg := ConvertToInzibatConfig(session, 8080)
+		require.Len(t, cfg.Routes, 4)
+
+		assert.Equal(t, "GET", cfg.Routes[0].Method)
+		assert.Equal(t, map[string]config.ValueMatcher{"page": {Equals: "2"}}, cfg.Routes[0].Match.Query)
+		assert.Equal(t, "page 2", cfg.Routes[0].FakeResponse.BodyString)
+
+		assert.Equal(t, "GET", cfg.Routes[1].Method)
+		assert.Nil(t, cfg.Routes[1].Match)
+		assert.Equal(t, "all", cfg.Routes[1].FakeResponse.BodyString)
+
+		assert.Equal(t, []config.BodyMatcher{
+			{JSONPath: "$.age", Equals: "30"},
+			{JSONPath: "$.name", Equals: "ali"},
+		}, cfg.Routes[2].Match.Body)
+		assert.Equal(t, 409, cfg.Routes[2].FakeResponse.StatusCode)
+
+		assert.Equal(t, "veli", cfg.Routes[3].FakeResponse.BodyString)
+	})
+}
+
+func TestBuildRouteMatch(t *testing.T) {
+	t.Run("nil without query and body", func(t *testing.T) {
+		assert.Nil(t, buildRouteMatch(RecordedRequest{Method: "GET", Path: "/"}))
+	})
+
+	t.Run("matches the first value of a query parameter", func(t *testing.T) {
+		match := buildRouteMatch(RecordedRequest{
+			Query: map[string][]string{"tag": {"a", "b"}, "empty": {""}},
+		})
+
+		require.NotNil(t, match)
+		assert.Equal(t, map[string]config.ValueMatcher{
+			"tag":   {Equals: "a"},
+			"empty": {Regex: "^$"},
+		}, match.Query)
+	})
+
+	t.Run("matches nested values and awkward field names", func(t *testing.T) {
+		match := buildRouteMatch(RecordedRequest{
+			Body: json.RawMessage(`{"a.b":1,"it's":2,"filter":{"b":2,"a":1}}`),
+		})
+
+		require.NotNil(t, match)
+		assert.Equal(t, []config.BodyMatcher{
+			{JSONPath: "$['a.b']", Equals: "1"},
+			{JSONPath: "$.filter", Equals: `{"a":1,"b":2}`},
+		}, match.Body)
+	})
+
+	t.Run("matches the whole body when it is not an object", func(t *testing.T) {
+		match := buildRouteMatch(RecordedRequest{Body: json.RawMessage(`[1,2]`)})
+
+		require.NotNil(t, match)
+		assert.Equal(t, []config.BodyMatcher{{JSONPath: "$", Equals: "[1,2]"}}, match.Body)
+	})
+
+	t.Run("skips string bodies", func(t *testing.T) {
+		assert.Nil(t, buildRouteMatch(RecordedRequest{Body: json.RawMessage(`"plain text"`)}))
+	})
 }
 
 func TestBuildFakeResponse(t *testing.T) {
@@ -194,19 +283,39 @@ func TestBuildFakeResponse(t *testing.T) {
 
 		fake := buildFakeResponse(resp)
 		assert.Equal(t, 200, fake.StatusCode)
-		assert.Equal(t, "test", fake.Body["name"])
-		assert.Equal(t, float64(42), fake.Body["count"])
+		assert.Equal(t, map[string]any{"name": "test", "count": float64(42)}, fake.Body)
 	})
 
-	t.Run("JSON array falls back to bodyString", func(t *testing.T) {
+	t.Run("JSON array goes to Body field", func(t *testing.T) {
 		resp := RecordedResponse{
 			StatusCode: 200,
 			Body:       json.RawMessage(`[1,2,3]`),
 		}
 
 		fake := buildFakeResponse(resp)
-		assert.Nil(t, fake.Body)
-		assert.Equal(t, "[1,2,3]", fake.BodyString)
+		assert.Equal(t, []any{float64(1), float64(2), float64(3)}, fake.Body)
+		assert.Empty(t, fake.BodyString)
+	})
+
+	t.Run("JSON scalar goes to Body field", func(t *testing.T) {
+		resp := RecordedResponse{
+			StatusCode: 200,
+			Body:       json.RawMessage(`true`),
+		}
+
+		fake := buildFakeResponse(resp)
+		assert.Equal(t, true, fake.Body)
+	})
+
+	t.Run("JSON null", func(t *testing.T) {
+		resp := RecordedResponse{
+			StatusCode: 200,
+			Body:       json.RawMessage(`null`),
+		}
+
+		fake := buildFakeResponse(resp)
+		assert.Equal(t, config.NullBody, fake.Body)
+		assert.Empty(t, fake.BodyString)
 	})
 }
 
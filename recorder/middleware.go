@@ -6,7 +6,9 @@ import (
 
 	"github.com/goccy/go-json"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 )
 
 const adminPathPrefix = "/_inzibat/"
@@ -23,9 +25,19 @@ func NewRecorderMiddleware(store *Store) fiber.Handler {
 
 		reqBody := captureBody(ctx.Body())
 		reqHeaders := captureRequestHeaders(ctx)
+		reqQuery := captureQuery(ctx)
 
 		err := ctx.Next()
 
+		if len(ctx.Body()) > MaxBodyCaptureBytes || len(ctx.Response().Body()) > MaxBodyCaptureBytes {
+			zap.L().Warn("request not recorded, its body is over the capture limit",
+				zap.String("method", ctx.Method()),
+				zap.String("path", ctx.Path()),
+				zap.Int("max_body_bytes", MaxBodyCaptureBytes),
+			)
+			return err
+		}
+
 		duration := time.Since(start).Milliseconds()
 		respBody := captureBody(ctx.Response().Body())
 		respHeaders := captureResponseHeaders(ctx)
@@ -34,8 +46,9 @@ func NewRecorderMiddleware(store *Store) fiber.Handler {
 			ID:        uuid.NewString(),
 			Timestamp: start,
 			Request: RecordedRequest{
-				Method:  ctx.Method(),
-				Path:    ctx.Path(),
+				Method:  utils.CopyString(ctx.Method()),
+				Path:    utils.CopyString(ctx.Path()),
+				Query:   reqQuery,
 				Headers: reqHeaders,
 				Body:    reqBody,
 			},
@@ -58,10 +71,6 @@ func captureBody(body []byte) json.RawMessage {
 		return nil
 	}
 
-	if len(body) > MaxBodyCaptureBytes {
-		body = body[:MaxBodyCaptureBytes]
-	}
-
 	// If it's valid JSON, store as-is
 	if json.Valid(body) {
 		result := make(json.RawMessage, len(body))
@@ -93,6 +102,21 @@ func captureRequestHeaders(c *fiber.Ctx) map[string][]string {
 	return headers
 }
 
+func captureQuery(c *fiber.Ctx) map[string][]string {
+	query := map[string][]string{}
+
+	for key, value := range c.Request().URI().QueryArgs().All() {
+		k := string(key)
+		query[k] = append(query[k], string(value))
+	}
+
+	if len(query) == 0 {
+		return nil
+	}
+
+	return query
+}
+
 func captureResponseHeaders(c *fiber.Ctx) map[string][]string {
 	headers := map[string][]string{}
 
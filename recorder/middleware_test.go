@@ -61,6 +61,25 @@ func TestNewRecorderMiddleware(t *testing.T) {
 		assert.Equal(t, `{"key":"value"}`, string(entries[0].Request.Body))
 	})
 
+	t.Run("captures query parameters", func(t *testing.T) {
+		store := NewStore(100)
+		app := fiber.New()
+		app.Use(NewRecorderMiddleware(store))
+		app.Get("/search", func(c *fiber.Ctx) error {
+			return c.Status(200).SendString("found")
+		})
+
+		req := httptest.NewRequest("GET", "/search?q=go&tag=a&tag=b", nil)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		entries := store.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/search", entries[0].Request.Path)
+		assert.Equal(t, map[string][]string{"q": {"go"}, "tag": {"a", "b"}}, entries[0].Request.Query)
+	})
+
 	t.Run("captures response body", func(t *testing.T) {
 		store := NewStore(100)
 		app := fiber.New()
@@ -146,6 +165,33 @@ func TestNewRecorderMiddleware(t *testing.T) {
 		require.Len(t, entries, 1)
 		assert.NotNil(t, entries[0].Request.Body)
 	})
+
+	t.Run("skips requests whose body is over the capture limit", func(t *testing.T) {
+		store := NewStore(100)
+		app := fiber.New()
+		app.Use(NewRecorderMiddleware(store))
+		app.Get("/large", func(c *fiber.Ctx) error {
+			return c.Status(200).SendString(strings.Repeat("a", MaxBodyCaptureBytes+1))
+		})
+		app.Get("/small", func(c *fiber.Ctx) error {
+			return c.Status(200).SendString("small")
+		})
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/large", nil), -1)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Len(t, body, MaxBodyCaptureBytes+1)
+
+		resp2, err := app.Test(httptest.NewRequest("GET", "/small", nil), -1)
+		require.NoError(t, err)
+		defer resp2.Body.Close()
+
+		entries := store.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/small", entries[0].Request.Path)
+	})
 }
 
 func TestCaptureBody(t *testing.T) {
@@ -170,15 +216,6 @@ func TestCaptureBody(t *testing.T) {
 		result := captureBody(body)
 		assert.Equal(t, `"hello world"`, string(result))
 	})
-
-	t.Run("truncates oversized body", func(t *testing.T) {
-		body := make([]byte, MaxBodyCaptureBytes+100)
-		for i := range body {
-			body[i] = 'a'
-		}
-		result := captureBody(body)
-		assert.NotNil(t, result)
-	})
 }
 
 func BenchmarkMiddleware(b *testing.B) {
@@ -7,6 +7,8 @@ import (
 )
 
 // MaxBodyCaptureBytes is the maximum size of request/response body that will be captured.
+// Requests whose request or response body is larger are not recorded, since a
+// cut body would be played back corrupted.
 const MaxBodyCaptureBytes = 1 << 20 // 1 MB
 
 // DefaultStoreCapacity is the maximum number of entries the store will hold.
@@ -19,12 +21,16 @@ type RecordedEntry struct {
 	Request    RecordedRequest  `json:"request"`
 	Response   RecordedResponse `json:"response"`
 	DurationMs int64            `json:"durationMs"`
+	// Service is the name of the service that recorded the entry, empty for
+	// the top-level routes.
+	Service string `json:"service,omitempty"`
 }
 
 // RecordedRequest captures the incoming HTTP request metadata.
 type RecordedRequest struct {
 	Method  string              `json:"method"`
 	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query,omitempty"`
 	Headers map[string][]string `json:"headers,omitempty"`
 	Body    json.RawMessage     `json:"body,omitempty"`
 }
@@ -0,0 +1,36 @@
+package recorder
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-json"
+)
+
+const sessionFilePerm = 0644
+
+// ReadSessionFile reads a session written by WriteSessionFile or exported by
+// "inzibat record export".
+func ReadSessionFile(filePath string) (*RecordedSession, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var session RecordedSession
+	if err = json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("failed to parse session file %s: %w", filePath, err)
+	}
+
+	return &session, nil
+}
+
+func WriteSessionFile(filePath string, session RecordedSession) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	// #nosec G306 - Session files are user-visible data, not secrets
+	return os.WriteFile(filePath, data, sessionFilePerm)
+}
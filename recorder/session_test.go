@@ -0,0 +1,48 @@
+package recorder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionFile(t *testing.T) {
+	t.Run("round trips a session", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "session.json")
+		session := RecordedSession{
+			StartedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			EntryCount: 1,
+			Entries: []RecordedEntry{
+				{
+					ID:       "1",
+					Request:  RecordedRequest{Method: "GET", Path: "/users", Query: map[string][]string{"page": {"2"}}},
+					Response: RecordedResponse{StatusCode: 200},
+				},
+			},
+		}
+
+		require.NoError(t, WriteSessionFile(filePath, session))
+
+		readSession, err := ReadSessionFile(filePath)
+		require.NoError(t, err)
+		assert.Equal(t, session.StartedAt, readSession.StartedAt.UTC())
+		assert.Equal(t, session.Entries[0].Request, readSession.Entries[0].Request)
+	})
+
+	t.Run("fails for a missing file", func(t *testing.T) {
+		_, err := ReadSessionFile(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("fails for an invalid file", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "session.json")
+		require.NoError(t, os.WriteFile(filePath, []byte("not json"), 0644))
+
+		_, err := ReadSessionFile(filePath)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,279 @@
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+const (
+	// DefaultSinkName is the base name of the files of a file sink.
+	DefaultSinkName = "recorder"
+	// DefaultSinkMaxSizeBytes is the size at which a file sink rotates its file.
+	DefaultSinkMaxSizeBytes = 100 << 20 // 100 MB
+	// DefaultSinkMaxFiles is the number of rotated files a file sink keeps.
+	DefaultSinkMaxFiles = 10
+
+	sinkFileExtension   = ".jsonl"
+	sinkRotatedTimeForm = "20060102T150405.000000000"
+	sinkDirPerm         = 0755
+	sinkFilePerm        = 0644
+)
+
+// Sink receives every entry added to a store.
+type Sink interface {
+	Write(entry RecordedEntry) error
+	Close() error
+}
+
+// FileSinkConfig configures where a file sink writes and when it rotates.
+// Zero values take the defaults, except RotateInterval, which disables
+// rotation by time.
+type FileSinkConfig struct {
+	Dir            string
+	Name           string
+	MaxSizeBytes   int64
+	RotateInterval time.Duration
+	MaxFiles       int
+}
+
+func (sinkConfig FileSinkConfig) withDefaults() FileSinkConfig {
+	if sinkConfig.Name == "" {
+		sinkConfig.Name = DefaultSinkName
+	}
+	if sinkConfig.MaxSizeBytes <= 0 {
+		sinkConfig.MaxSizeBytes = DefaultSinkMaxSizeBytes
+	}
+	if sinkConfig.MaxFiles <= 0 {
+		sinkConfig.MaxFiles = DefaultSinkMaxFiles
+	}
+
+	return sinkConfig
+}
+
+func (sinkConfig FileSinkConfig) activePath() string {
+	return filepath.Join(sinkConfig.Dir, sinkConfig.Name+sinkFileExtension)
+}
+
+// rotatedPaths returns the rotated files of the sink, oldest first. Files of
+// other sinks whose name starts with this one are left out.
+func (sinkConfig FileSinkConfig) rotatedPaths() ([]string, error) {
+	prefix := sinkConfig.Name + "-"
+	paths, err := filepath.Glob(filepath.Join(sinkConfig.Dir, prefix+"*"+sinkFileExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	paths = slices.DeleteFunc(paths, func(path string) bool {
+		rotatedAt := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), prefix), sinkFileExtension)
+		_, err := time.Parse(sinkRotatedTimeForm, rotatedAt)
+		return err != nil
+	})
+	slices.Sort(paths)
+
+	return paths, nil
+}
+
+// FileSink appends entries as JSON lines to <Dir>/<Name>.jsonl. The file is
+// rotated to <Name>-<UTC time>.jsonl when it would grow past MaxSizeBytes or
+// is older than RotateInterval, and only the last MaxFiles rotated files are
+// kept. Every entry is written straight to the file, so it outlives a crash.
+type FileSink struct {
+	mu       sync.Mutex
+	config   FileSinkConfig
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// NewFileSink opens the file of the sink. A file left by a previous run is
+// rotated first, so every run starts a file of its own.
+func NewFileSink(sinkConfig FileSinkConfig) (*FileSink, error) {
+	sink := &FileSink{
+		config: sinkConfig.withDefaults(),
+		now:    time.Now,
+	}
+
+	if err := os.MkdirAll(sink.config.Dir, sinkDirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create recorder directory: %w", err)
+	}
+
+	fileInfo, err := os.Stat(sink.config.activePath())
+	if err == nil && fileInfo.Size() > 0 {
+		if err = sink.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (sink *FileSink) Write(entry RecordedEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.file == nil {
+		return os.ErrClosed
+	}
+	if sink.shouldRotate(int64(len(line))) {
+		if err = sink.file.Close(); err != nil {
+			return err
+		}
+		if err = sink.rotate(); err != nil {
+			return err
+		}
+		if err = sink.open(); err != nil {
+			return err
+		}
+	}
+
+	written, err := sink.file.Write(line)
+	sink.size += int64(written)
+
+	return err
+}
+
+func (sink *FileSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.file == nil {
+		return nil
+	}
+
+	err := sink.file.Close()
+	sink.file = nil
+
+	return err
+}
+
+func (sink *FileSink) shouldRotate(lineSize int64) bool {
+	if sink.size == 0 {
+		return false
+	}
+	if sink.size+lineSize > sink.config.MaxSizeBytes {
+		return true
+	}
+
+	return sink.config.RotateInterval > 0 && sink.now().Sub(sink.openedAt) >= sink.config.RotateInterval
+}
+
+func (sink *FileSink) open() error {
+	file, err := os.OpenFile(sink.config.activePath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, sinkFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open recorder file: %w", err)
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to open recorder file: %w", err)
+	}
+
+	sink.file = file
+	sink.size = fileInfo.Size()
+	sink.openedAt = sink.now()
+
+	return nil
+}
+
+// rotate moves the active file aside and removes the rotated files beyond
+// MaxFiles.
+func (sink *FileSink) rotate() error {
+	rotatedPath := filepath.Join(
+		sink.config.Dir,
+		sink.config.Name+"-"+sink.now().UTC().Format(sinkRotatedTimeForm)+sinkFileExtension,
+	)
+	if err := os.Rename(sink.config.activePath(), rotatedPath); err != nil {
+		return fmt.Errorf("failed to rotate recorder file: %w", err)
+	}
+
+	rotatedPaths, err := sink.config.rotatedPaths()
+	if err != nil {
+		return err
+	}
+
+	var removeErrors []error
+	for len(rotatedPaths) > sink.config.MaxFiles {
+		if err = os.Remove(rotatedPaths[0]); err != nil {
+			removeErrors = append(removeErrors, err)
+		}
+		rotatedPaths = rotatedPaths[1:]
+	}
+
+	return errors.Join(removeErrors...)
+}
+
+// ReadFileSink reads back the entries a file sink wrote, oldest first. Lines
+// that cannot be parsed, such as one cut short by a crash, are skipped.
+func ReadFileSink(sinkConfig FileSinkConfig) ([]RecordedEntry, error) {
+	sinkConfig = sinkConfig.withDefaults()
+
+	paths, err := sinkConfig.rotatedPaths()
+	if err != nil {
+		return nil, err
+	}
+	paths = append(paths, sinkConfig.activePath())
+
+	var entries []RecordedEntry
+	for _, path := range paths {
+		fileEntries, err := readEntryLines(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+func readEntryLines(path string) ([]RecordedEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []RecordedEntry
+	reader := bufio.NewReader(file)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var entry RecordedEntry
+			if json.Unmarshal(line, &entry) == nil {
+				entries = append(entries, entry)
+			}
+		}
+
+		if readErr != nil {
+			if errors.Is(readErr, io.EOF) {
+				return entries, nil
+			}
+			return nil, fmt.Errorf("failed to read recorder file %s: %w", path, readErr)
+		}
+	}
+}
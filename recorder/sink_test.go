@@ -0,0 +1,159 @@
+package recorder
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listSinkFiles(t *testing.T, dir string) []string {
+	dirEntries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		names = append(names, dirEntry.Name())
+	}
+
+	return names
+}
+
+func TestFileSink(t *testing.T) {
+	t.Run("appends entries as JSON lines", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := NewFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Write(newTestEntry("1")))
+		require.NoError(t, sink.Write(newTestEntry("2")))
+		require.NoError(t, sink.Close())
+
+		data, err := os.ReadFile(filepath.Join(dir, "recorder.jsonl"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+	})
+
+	t.Run("rotates by size and keeps the last files", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := NewFileSink(FileSinkConfig{Dir: dir, MaxSizeBytes: 1, MaxFiles: 2})
+		require.NoError(t, err)
+
+		for id := range 5 {
+			require.NoError(t, sink.Write(newTestEntry(string(rune('a'+id)))))
+		}
+		require.NoError(t, sink.Close())
+
+		files := listSinkFiles(t, dir)
+		assert.Len(t, files, 3)
+		assert.Contains(t, files, "recorder.jsonl")
+
+		entries, err := ReadFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "c", entries[0].ID)
+		assert.Equal(t, "e", entries[2].ID)
+	})
+
+	t.Run("rotates by time", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := NewFileSink(FileSinkConfig{Dir: dir, RotateInterval: time.Minute})
+		require.NoError(t, err)
+
+		now := time.Now()
+		sink.now = func() time.Time { return now }
+		require.NoError(t, sink.Write(newTestEntry("1")))
+		require.NoError(t, sink.Write(newTestEntry("2")))
+
+		now = now.Add(2 * time.Minute)
+		require.NoError(t, sink.Write(newTestEntry("3")))
+		require.NoError(t, sink.Close())
+
+		assert.Len(t, listSinkFiles(t, dir), 2)
+
+		data, err := os.ReadFile(filepath.Join(dir, "recorder.jsonl"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, bytes.Count(data, []byte("\n")))
+	})
+
+	t.Run("starts a new file for every run", func(t *testing.T) {
+		dir := t.TempDir()
+		firstSink, err := NewFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, firstSink.Write(newTestEntry("1")))
+		require.NoError(t, firstSink.Close())
+
+		secondSink, err := NewFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, secondSink.Write(newTestEntry("2")))
+		require.NoError(t, secondSink.Close())
+
+		assert.Len(t, listSinkFiles(t, dir), 2)
+
+		entries, err := ReadFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "1", entries[0].ID)
+		assert.Equal(t, "2", entries[1].ID)
+	})
+
+	t.Run("fails to write after close", func(t *testing.T) {
+		sink, err := NewFileSink(FileSinkConfig{Dir: t.TempDir()})
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+
+		assert.ErrorIs(t, sink.Write(newTestEntry("1")), os.ErrClosed)
+		assert.NoError(t, sink.Close())
+	})
+}
+
+func TestReadFileSink(t *testing.T) {
+	t.Run("skips lines that cannot be parsed", func(t *testing.T) {
+		dir := t.TempDir()
+		sink, err := NewFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(newTestEntry("1")))
+		require.NoError(t, sink.Close())
+
+		file, err := os.OpenFile(filepath.Join(dir, "recorder.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"id":"2","request":`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		entries, err := ReadFileSink(FileSinkConfig{Dir: dir})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "1", entries[0].ID)
+	})
+
+	t.Run("ignores files of other sinks", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, name := range []string{"api", "api-v2"} {
+			sink, err := NewFileSink(FileSinkConfig{Dir: dir, Name: name})
+			require.NoError(t, err)
+			require.NoError(t, sink.Write(newTestEntry(name)))
+			require.NoError(t, sink.Close())
+
+			sink, err = NewFileSink(FileSinkConfig{Dir: dir, Name: name})
+			require.NoError(t, err)
+			require.NoError(t, sink.Close())
+		}
+
+		entries, err := ReadFileSink(FileSinkConfig{Dir: dir, Name: "api"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "api", entries[0].ID)
+	})
+
+	t.Run("empty for a missing directory", func(t *testing.T) {
+		entries, err := ReadFileSink(FileSinkConfig{Dir: filepath.Join(t.TempDir(), "missing")})
+
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -12,6 +12,8 @@ type Store struct {
 	entries   []RecordedEntry
 	capacity  int
 	startedAt time.Time
+	sink      Sink
+	onError   func(error)
 }
 
 // NewStore creates a new Store with the given capacity.
@@ -28,15 +30,58 @@ func NewStore(capacity int) *Store {
 }
 
 // Add appends a recorded entry. If the store is at capacity, the oldest entry is dropped.
+// The entry is also written to the sink of the store, if any.
 func (s *Store) Add(entry RecordedEntry) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if len(s.entries) >= s.capacity {
 		s.entries = s.entries[1:]
 	}
-
 	s.entries = append(s.entries, entry)
+	sink, onError := s.sink, s.onError
+	s.mu.Unlock()
+
+	if sink == nil {
+		return
+	}
+	if err := sink.Write(entry); err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+// Load appends previously recorded entries without writing them to the sink.
+// Only the newest entries are kept when they exceed the capacity.
+func (s *Store) Load(entries []RecordedEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = append(s.entries, entries...)
+	if overflow := len(s.entries) - s.capacity; overflow > 0 {
+		s.entries = append(make([]RecordedEntry, 0, s.capacity), s.entries[overflow:]...)
+	}
+}
+
+// SetSink makes the store write every added entry to sink as well. Write
+// errors are passed to onError, which may be nil.
+func (s *Store) SetSink(sink Sink, onError func(error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sink = sink
+	s.onError = onError
+}
+
+// Close closes the sink of the store, if any.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	sink := s.sink
+	s.sink = nil
+	s.mu.Unlock()
+
+	if sink == nil {
+		return nil
+	}
+
+	return sink.Close()
 }
 
 // List returns a copy of all recorded entries.
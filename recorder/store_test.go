@@ -1,6 +1,7 @@
 package recorder
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -67,6 +68,80 @@ func TestStoreAdd(t *testing.T) {
 	})
 }
 
+type failingSink struct {
+	written []RecordedEntry
+	closed  bool
+}
+
+func (sink *failingSink) Write(entry RecordedEntry) error {
+	sink.written = append(sink.written, entry)
+	return errors.New("disk full")
+}
+
+func (sink *failingSink) Close() error {
+	sink.closed = true
+	return nil
+}
+
+func TestStoreSink(t *testing.T) {
+	t.Run("writes added entries to the sink and reports errors", func(t *testing.T) {
+		store := NewStore(10)
+		sink := &failingSink{}
+		var sinkErrors []error
+		store.SetSink(sink, func(err error) {
+			sinkErrors = append(sinkErrors, err)
+		})
+
+		store.Add(newTestEntry("1"))
+
+		require.Len(t, sink.written, 1)
+		assert.Equal(t, "1", sink.written[0].ID)
+		assert.Len(t, sinkErrors, 1)
+		assert.Equal(t, 1, store.Len())
+	})
+
+	t.Run("closes the sink", func(t *testing.T) {
+		store := NewStore(10)
+		sink := &failingSink{}
+		store.SetSink(sink, nil)
+
+		require.NoError(t, store.Close())
+		store.Add(newTestEntry("1"))
+
+		assert.True(t, sink.closed)
+		assert.Empty(t, sink.written)
+	})
+
+	t.Run("close without a sink", func(t *testing.T) {
+		assert.NoError(t, NewStore(10).Close())
+	})
+}
+
+func TestStoreLoad(t *testing.T) {
+	t.Run("appends entries without writing them to the sink", func(t *testing.T) {
+		store := NewStore(10)
+		sink := &failingSink{}
+		store.SetSink(sink, nil)
+
+		store.Load([]RecordedEntry{newTestEntry("1"), newTestEntry("2")})
+
+		assert.Equal(t, 2, store.Len())
+		assert.Empty(t, sink.written)
+	})
+
+	t.Run("keeps the newest entries when over capacity", func(t *testing.T) {
+		store := NewStore(2)
+		store.Add(newTestEntry("1"))
+
+		store.Load([]RecordedEntry{newTestEntry("2"), newTestEntry("3")})
+
+		entries := store.List()
+		require.Len(t, entries, 2)
+		assert.Equal(t, "2", entries[0].ID)
+		assert.Equal(t, "3", entries[1].ID)
+	})
+}
+
 func TestStoreList(t *testing.T) {
 	t.Run("returns copy of entries", func(t *testing.T) {
 		store := NewStore(10)
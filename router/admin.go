@@ -0,0 +1,126 @@
+package router
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func RegisterRouteAdminRoutes(app *fiber.App, table *RouteTable) {
+	group := app.Group("/_inzibat/routes")
+
+	group.Get("/", listRoutesHandler(table))
+	group.Post("/", createRouteHandler(table))
+	group.Get("/:id", getRouteHandler(table))
+	group.Put("/:id", replaceRouteHandler(table))
+	group.Delete("/:id", deleteRouteHandler(table))
+}
+
+func listRoutesHandler(table *RouteTable) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(table.Routes())
+	}
+}
+
+func getRouteHandler(table *RouteTable) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		route, exists := table.Route(ctx.Params("id"))
+		if !exists {
+			return writeRouteAdminError(ctx, ErrorRouteNotFound)
+		}
+
+		return ctx.JSON(route)
+	}
+}
+
+func createRouteHandler(table *RouteTable) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var route config.Route
+		if err := ctx.BodyParser(&route); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "request body must be a valid route",
+			})
+		}
+
+		createdRoute, err := table.AddRoute(route, ctx.QueryBool("prepend"))
+		if err != nil {
+			return writeRouteAdminError(ctx, err)
+		}
+
+		if err = persistRoutes(ctx, table); err != nil {
+			return writePersistError(ctx, err)
+		}
+
+		return ctx.Status(fiber.StatusCreated).JSON(createdRoute)
+	}
+}
+
+func replaceRouteHandler(table *RouteTable) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var route config.Route
+		if err := ctx.BodyParser(&route); err != nil {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "request body must be a valid route",
+			})
+		}
+
+		replacedRoute, err := table.ReplaceRoute(ctx.Params("id"), route)
+		if err != nil {
+			return writeRouteAdminError(ctx, err)
+		}
+
+		if err = persistRoutes(ctx, table); err != nil {
+			return writePersistError(ctx, err)
+		}
+
+		return ctx.JSON(replacedRoute)
+	}
+}
+
+func deleteRouteHandler(table *RouteTable) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		id := ctx.Params("id")
+		if err := table.DeleteRoute(id); err != nil {
+			return writeRouteAdminError(ctx, err)
+		}
+
+		if err := persistRoutes(ctx, table); err != nil {
+			return writePersistError(ctx, err)
+		}
+
+		return ctx.JSON(fiber.Map{
+			"message": "route deleted",
+			"id":      id,
+		})
+	}
+}
+
+func persistRoutes(ctx *fiber.Ctx, table *RouteTable) error {
+	if !ctx.QueryBool("persist") {
+		return nil
+	}
+
+	return table.Persist()
+}
+
+func writePersistError(ctx *fiber.Ctx, err error) error {
+	return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"message": "routes were updated but could not be persisted: " + err.Error(),
+	})
+}
+
+func writeRouteAdminError(ctx *fiber.Ctx, err error) error {
+	statusCode := fiber.StatusBadRequest
+	switch {
+	case errors.Is(err, ErrorRouteNotFound):
+		statusCode = fiber.StatusNotFound
+	case errors.Is(err, ErrorRouteAlreadyExists):
+		statusCode = fiber.StatusConflict
+	}
+
+	return ctx.Status(statusCode).JSON(fiber.Map{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func sendAdminRequest(t *testing.T, fiberApp *fiber.App, method, target, body string) (int, []byte) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	response, err := fiberApp.Test(request)
+	require.NoError(t, err)
+
+	responseBody, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, responseBody
+}
+
+func TestRegisterRouteAdminRoutes(t *testing.T) {
+	const newRouteBody = `{"method":"GET","path":"/orders","fakeResponse":{"statusCode":200,"bodyString":"orders"}}`
+
+	t.Run("happy path - lists routes", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, body := sendAdminRequest(t, fiberApp, fiber.MethodGet, "/_inzibat/routes", "")
+
+		var routes []config.Route
+		require.NoError(t, json.Unmarshal(body, &routes))
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		require.Len(t, routes, 1)
+		assert.Equal(t, "/users", routes[0].Path)
+	})
+
+	t.Run("happy path - creates, replaces and deletes a route", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, body := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes", newRouteBody)
+		require.Equal(t, fiber.StatusCreated, statusCode)
+
+		var created config.Route
+		require.NoError(t, json.Unmarshal(body, &created))
+		require.NotEmpty(t, created.ID)
+
+		_, servedBody := sendTestRequest(t, fiberApp, fiber.MethodGet, "/orders")
+		assert.Equal(t, "orders", servedBody)
+
+		statusCode, _ = sendAdminRequest(t, fiberApp, fiber.MethodPut, "/_inzibat/routes/"+created.ID,
+			`{"method":"GET","path":"/orders","fakeResponse":{"statusCode":200,"bodyString":"replaced"}}`)
+		require.Equal(t, fiber.StatusOK, statusCode)
+
+		_, servedBody = sendTestRequest(t, fiberApp, fiber.MethodGet, "/orders")
+		assert.Equal(t, "replaced", servedBody)
+
+		statusCode, _ = sendAdminRequest(t, fiberApp, fiber.MethodDelete, "/_inzibat/routes/"+created.ID, "")
+		require.Equal(t, fiber.StatusOK, statusCode)
+
+		statusCode, _ = sendTestRequest(t, fiberApp, fiber.MethodGet, "/orders")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("happy path - persists when requested", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.json")
+		_, fiberApp := newTestRouteTable(t, configPath, mockRoute("/users", "users"))
+
+		statusCode, _ := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes?persist=true", newRouteBody)
+		require.Equal(t, fiber.StatusCreated, statusCode)
+
+		cfg, err := config.ReadOrCreateConfig(configPath)
+		require.NoError(t, err)
+		assert.Len(t, cfg.Routes, 2)
+	})
+
+	t.Run("error path - invalid route returns bad request", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, _ := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes",
+			`{"method":"GET","path":"/orders"}`)
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+	})
+
+	t.Run("error path - malformed body returns bad request", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, _ := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes", `{`)
+
+		assert.Equal(t, fiber.StatusBadRequest, statusCode)
+	})
+
+	t.Run("error path - duplicate id returns conflict", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		id := table.Routes()[0].ID
+
+		statusCode, _ := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes",
+			`{"id":"`+id+`","method":"GET","path":"/orders","fakeResponse":{"statusCode":200,"bodyString":"orders"}}`)
+
+		assert.Equal(t, fiber.StatusConflict, statusCode)
+	})
+
+	t.Run("error path - unknown id returns not found", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, _ := sendAdminRequest(t, fiberApp, fiber.MethodGet, "/_inzibat/routes/missing", "")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+
+		statusCode, _ = sendAdminRequest(t, fiberApp, fiber.MethodDelete, "/_inzibat/routes/missing", "")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("error path - persist failure returns internal server error", func(t *testing.T) {
+		_, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		statusCode, body := sendAdminRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes?persist=true", newRouteBody)
+
+		assert.Equal(t, fiber.StatusInternalServerError, statusCode)
+		assert.Contains(t, string(body), "could not be persisted")
+	})
+}
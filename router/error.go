@@ -0,0 +1,13 @@
+package router
+
+import (
+	"errors"
+)
+
+var (
+	ErrorRouteNotFound       = errors.New("route not found")
+	ErrorRouteAlreadyExists  = errors.New("route with the same id already exists")
+	ErrorMethodNotEnabled    = errors.New("http method was not enabled at startup")
+	ErrorPersistenceDisabled = errors.New("config file path is unknown, routes cannot be persisted")
+	ErrorPersistUnsupported  = errors.New("routes can only be persisted to JSON config files")
+)
@@ -1,6 +1,9 @@
 package router
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,10 +11,12 @@ import (
 	"github.com/lynicis/inzibat/config"
 	"github.com/lynicis/inzibat/handler"
 	_ "github.com/lynicis/inzibat/log"
+	"github.com/lynicis/inzibat/matcher"
+	"github.com/lynicis/inzibat/metrics"
 )
 
 type Router interface {
-	CreateRoutes()
+	CreateRoutes() error
 }
 
 type Handler interface {
@@ -23,11 +28,23 @@ type MainRouter struct {
 	FiberApp        *fiber.App
 	EndpointHandler Handler
 	ClientHandler   Handler
+	SequenceHandler Handler
+	StaticHandler   Handler
+	ScenarioStore   *handler.ScenarioStore
+	Mirrors         map[int]*handler.RequestMirror
 }
 
-func (mainRouter *MainRouter) CreateRoutes() {
-	routeCount := len(mainRouter.Config.Routes)
+type routeCandidate struct {
+	matcher  *matcher.RequestMatcher
+	scenario *config.RouteScenario
+	handle   fiber.Handler
+}
+
+func (mainRouter *MainRouter) CreateRoutes() error {
+	routeGroups := groupRoutes(mainRouter.Config.Routes)
+	routeCount := len(routeGroups)
 	routeChannel := make(chan *handler.RouteChannel, routeCount)
+	errorChannel := make(chan error, routeCount)
 	defer close(routeChannel)
 
 	var waitGroup sync.WaitGroup
@@ -36,37 +53,182 @@ func (mainRouter *MainRouter) CreateRoutes() {
 	for workerCount := 0; workerCount < mainRouter.Config.Concurrency; workerCount++ {
 		go func() {
 			for route := range routeChannel {
-				mainRouter.processRoute(route, &waitGroup)
+				if err := mainRouter.processRoute(route); err != nil {
+					errorChannel <- err
+				}
+				waitGroup.Done()
 			}
 		}()
 	}
 
-	for routeIndex, route := range mainRouter.Config.Routes {
-		routeChannel <- &handler.RouteChannel{
-			Route:      route,
-			RouteIndex: routeIndex,
-		}
+	for _, routeGroup := range routeGroups {
+		routeChannel <- routeGroup
 	}
 
 	waitGroup.Wait()
+
+	if mainRouter.Config.HealthCheckRoute {
+		mainRouter.addRoute(fiber.MethodGet, "/health", func(ctx *fiber.Ctx) error {
+			return ctx.SendStatus(fiber.StatusOK)
+		})
+	}
+
+	select {
+	case err := <-errorChannel:
+		return err
+	default:
+		return nil
+	}
 }
 
-func (mainRouter *MainRouter) processRoute(
-	routeChannel *handler.RouteChannel,
-	waitGroup *sync.WaitGroup,
-) {
-	defer waitGroup.Done()
+func groupRoutes(routes []config.Route) []*handler.RouteChannel {
+	routeGroups := make([]*handler.RouteChannel, 0, len(routes))
+	routeGroupByKey := make(map[string]*handler.RouteChannel)
+
+	for routeIndex, route := range routes {
+		routePath := route.Path
+		if route.Static != nil {
+			routePath = strings.TrimSuffix(routePath, "/") + "/*"
+		}
+
+		routeKey := route.Method + " " + routePath
+		if routeGroup, exists := routeGroupByKey[routeKey]; exists {
+			routeGroup.RouteIndexes = append(routeGroup.RouteIndexes, routeIndex)
+			continue
+		}
+
+		routeGroup := &handler.RouteChannel{
+			Method:       route.Method,
+			Path:         routePath,
+			RouteIndexes: []int{routeIndex},
+		}
+		routeGroupByKey[routeKey] = routeGroup
+		routeGroups = append(routeGroups, routeGroup)
+	}
+
+	return routeGroups
+}
 
-	route := routeChannel.Route
-	routeIndex := routeChannel.RouteIndex
+func (mainRouter *MainRouter) processRoute(routeChannel *handler.RouteChannel) error {
+	candidates := make([]routeCandidate, 0, len(routeChannel.RouteIndexes))
+	for _, routeIndex := range routeChannel.RouteIndexes {
+		route := mainRouter.Config.Routes[routeIndex]
+
+		routeFunction := mainRouter.createHandler(route, routeIndex)
+		if routeFunction == nil {
+			continue
+		}
+
+		requestMatcher, err := matcher.New(route.Match)
+		if err != nil {
+			return fmt.Errorf("failed to compile matcher for route %s %s: %w", route.Method, route.Path, err)
+		}
+
+		candidates = append(candidates, routeCandidate{
+			matcher:  requestMatcher,
+			scenario: route.Scenario,
+			handle:   routeFunction,
+		})
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return nil
+	case len(candidates) == 1 && candidates[0].matcher == nil && candidates[0].scenario == nil:
+		mainRouter.addRoute(routeChannel.Method, routeChannel.Path, candidates[0].handle)
+	default:
+		mainRouter.addRoute(routeChannel.Method, routeChannel.Path, mainRouter.createMatchingHandler(candidates))
+	}
+
+	return nil
+}
+
+func (mainRouter *MainRouter) addRoute(method, path string, next fiber.Handler) {
+	handle := func(ctx *fiber.Ctx) error {
+		metrics.SetRoute(ctx, path)
+		return next(ctx)
+	}
+
+	if method == config.MethodAny {
+		mainRouter.FiberApp.All(path, handle)
+		return
+	}
+
+	mainRouter.FiberApp.Add(method, path, handle)
+}
+
+func RequestMethods(routes []config.Route) []string {
+	requestMethods := make([]string, len(fiber.DefaultMethods))
+	copy(requestMethods, fiber.DefaultMethods)
+
+	for _, route := range routes {
+		if route.Method == config.MethodAny || slices.Contains(requestMethods, route.Method) {
+			continue
+		}
+		requestMethods = append(requestMethods, route.Method)
+	}
+
+	return requestMethods
+}
+
+func (mainRouter *MainRouter) createHandler(route config.Route, routeIndex int) fiber.Handler {
+	routeFunction := mainRouter.createResponseHandler(route, routeIndex)
+	if routeFunction == nil {
+		return nil
+	}
+
+	delay := route.Delay
+	if delay == nil {
+		delay = mainRouter.Config.Delay
+	}
+
+	return handler.WithMirror(
+		mainRouter.Mirrors[routeIndex],
+		handler.WithDelay(delay, handler.WithFaults(route.Faults, routeFunction)),
+	)
+}
+
+func (mainRouter *MainRouter) createResponseHandler(route config.Route, routeIndex int) fiber.Handler {
+	if route.Static != nil && mainRouter.StaticHandler != nil {
+		return mainRouter.StaticHandler.CreateHandler(routeIndex)
+	}
 
 	if route.RequestTo != nil && route.RequestTo.Method != "" {
-		routeFunction := mainRouter.ClientHandler.CreateHandler(routeIndex)
-		mainRouter.FiberApp.Add(route.Method, route.Path, routeFunction)
+		return mainRouter.ClientHandler.CreateHandler(routeIndex)
+	}
+
+	if route.Sequence != nil && len(route.Sequence.Responses) > 0 {
+		return mainRouter.SequenceHandler.CreateHandler(routeIndex)
 	}
 
 	if route.FakeResponse != nil && route.FakeResponse.StatusCode > 0 {
-		routeFunction := mainRouter.EndpointHandler.CreateHandler(routeIndex)
-		mainRouter.FiberApp.Add(route.Method, route.Path, routeFunction)
+		return mainRouter.EndpointHandler.CreateHandler(routeIndex)
 	}
+
+	return nil
+}
+
+func (mainRouter *MainRouter) createMatchingHandler(candidates []routeCandidate) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		request := matcher.NewFiberRequest(ctx)
+		for _, candidate := range candidates {
+			if candidate.matcher.Matches(request) && mainRouter.enterScenario(candidate.scenario) {
+				return candidate.handle(ctx)
+			}
+		}
+
+		if mainRouter.Config.NoMatchResponse != nil {
+			return handler.WriteFakeResponse(ctx, mainRouter.Config.NoMatchResponse)
+		}
+
+		return ctx.Next()
+	}
+}
+
+func (mainRouter *MainRouter) enterScenario(scenario *config.RouteScenario) bool {
+	if scenario == nil || mainRouter.ScenarioStore == nil {
+		return true
+	}
+
+	return mainRouter.ScenarioStore.Enter(scenario)
 }
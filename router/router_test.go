@@ -1,10 +1,15 @@
 package router
 
 import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/lynicis/inzibat/client/http"
 	"github.com/lynicis/inzibat/config"
@@ -153,3 +158,275 @@ func TestRouter_CreateRoute(t *testing.T) {
 		})
 	})
 }
+
+func TestRouter_CreateRoutes_WithMatchers(t *testing.T) {
+	newMatchingRouter := func(noMatchResponse *config.FakeResponse) (*MainRouter, *fiber.App) {
+		routes := []config.Route{
+			{
+				Method: fiber.MethodGet,
+				Path:   "/users",
+				Match: &config.RouteMatch{
+					Headers: map[string]config.ValueMatcher{
+						"X-Tenant": {Equals: "acme"},
+					},
+				},
+				FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "acme"},
+			},
+			{
+				Method: fiber.MethodGet,
+				Path:   "/users",
+				Match: &config.RouteMatch{
+					Query: map[string]config.ValueMatcher{
+						"role": {Regex: "^admin$"},
+					},
+				},
+				FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "admin"},
+			},
+		}
+
+		fiberApp := fiber.New()
+		return &MainRouter{
+			Config: &config.Cfg{
+				Routes:          routes,
+				Concurrency:     1,
+				NoMatchResponse: noMatchResponse,
+			},
+			FiberApp:        fiberApp,
+			EndpointHandler: &handler.EndpointHandler{RouteConfig: &routes},
+			ClientHandler:   &handler.ClientHandler{},
+		}, fiberApp
+	}
+
+	sendRequest := func(t *testing.T, fiberApp *fiber.App, target string, headers map[string]string) (int, string) {
+		request := httptest.NewRequest(fiber.MethodGet, target, nil)
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+
+		response, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, string(body)
+	}
+
+	t.Run("happy path - routes sharing a path are tried in order", func(t *testing.T) {
+		router, fiberApp := newMatchingRouter(nil)
+		require.NoError(t, router.CreateRoutes())
+
+		statusCode, body := sendRequest(t, fiberApp, "/users?role=admin", map[string]string{"X-Tenant": "acme"})
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "acme", body)
+
+		statusCode, body = sendRequest(t, fiberApp, "/users?role=admin", nil)
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "admin", body)
+	})
+
+	t.Run("happy path - falls through to not found when nothing matches", func(t *testing.T) {
+		router, fiberApp := newMatchingRouter(nil)
+		require.NoError(t, router.CreateRoutes())
+
+		statusCode, _ := sendRequest(t, fiberApp, "/users", nil)
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("happy path - serves no match response when nothing matches", func(t *testing.T) {
+		router, fiberApp := newMatchingRouter(&config.FakeResponse{
+			StatusCode: fiber.StatusTeapot,
+			BodyString: "no stub matched",
+		})
+		require.NoError(t, router.CreateRoutes())
+
+		statusCode, body := sendRequest(t, fiberApp, "/users", nil)
+		assert.Equal(t, fiber.StatusTeapot, statusCode)
+		assert.Equal(t, "no stub matched", body)
+	})
+
+	t.Run("error path - invalid matcher", func(t *testing.T) {
+		router, _ := newMatchingRouter(nil)
+		router.Config.Routes[1].Match.Query["role"] = config.ValueMatcher{Regex: "("}
+
+		err := router.CreateRoutes()
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to compile matcher for route GET /users")
+	})
+}
+
+func TestRouter_CreateRoutes_WithScenarios(t *testing.T) {
+	routes := []config.Route{
+		{
+			Method: fiber.MethodGet,
+			Path:   "/order",
+			Scenario: &config.RouteScenario{
+				Name:          "order",
+				RequiredState: handler.ScenarioStateStarted,
+				NewState:      "Paid",
+			},
+			FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "created"},
+		},
+		{
+			Method: fiber.MethodGet,
+			Path:   "/order",
+			Scenario: &config.RouteScenario{
+				Name:          "order",
+				RequiredState: "Paid",
+			},
+			Sequence: &config.ResponseSequence{
+				Mode: config.SequenceModeCycle,
+				Responses: []config.FakeResponse{
+					{StatusCode: 202, BodyString: "processing"},
+					{StatusCode: 200, BodyString: "paid"},
+				},
+			},
+		},
+	}
+
+	scenarioStore := handler.NewScenarioStore()
+	fiberApp := fiber.New()
+	router := &MainRouter{
+		Config:          &config.Cfg{Routes: routes, Concurrency: 1},
+		FiberApp:        fiberApp,
+		EndpointHandler: &handler.EndpointHandler{RouteConfig: &routes},
+		ClientHandler:   &handler.ClientHandler{},
+		SequenceHandler: &handler.SequenceHandler{RouteConfig: &routes, ScenarioStore: scenarioStore},
+		ScenarioStore:   scenarioStore,
+	}
+	require.NoError(t, router.CreateRoutes())
+
+	var bodies []string
+	for range 4 {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/order", nil))
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+
+	assert.Equal(t, []string{"created", "processing", "paid", "processing"}, bodies)
+	assert.Equal(t, "Paid", scenarioStore.State("order"))
+}
+
+func TestRouter_CreateRoutes_WithExtendedMethods(t *testing.T) {
+	routes := []config.Route{
+		{
+			Method:       fiber.MethodOptions,
+			Path:         "/users",
+			FakeResponse: &config.FakeResponse{StatusCode: fiber.StatusNoContent, BodyString: "preflight"},
+		},
+		{
+			Method:       "PURGE",
+			Path:         "/cache",
+			FakeResponse: &config.FakeResponse{StatusCode: fiber.StatusAccepted, BodyString: "purged"},
+		},
+		{
+			Method:       config.MethodAny,
+			Path:         "/echo",
+			FakeResponse: &config.FakeResponse{StatusCode: fiber.StatusOK, BodyString: "any"},
+		},
+	}
+
+	fiberApp := fiber.New(fiber.Config{
+		RequestMethods: RequestMethods(routes),
+	})
+	router := &MainRouter{
+		Config: &config.Cfg{
+			Routes:      routes,
+			Concurrency: 1,
+		},
+		FiberApp:        fiberApp,
+		EndpointHandler: &handler.EndpointHandler{RouteConfig: &routes},
+		ClientHandler:   &handler.ClientHandler{},
+	}
+	require.NoError(t, router.CreateRoutes())
+
+	t.Run("happy path - registers OPTIONS and custom methods", func(t *testing.T) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodOptions, "/users", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, response.StatusCode)
+
+		response, err = fiberApp.Test(httptest.NewRequest("PURGE", "/cache", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusAccepted, response.StatusCode)
+	})
+
+	t.Run("happy path - ANY matches every method", func(t *testing.T) {
+		for _, method := range []string{fiber.MethodGet, fiber.MethodDelete, fiber.MethodTrace, "PURGE"} {
+			response, err := fiberApp.Test(httptest.NewRequest(method, "/echo", nil))
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusOK, response.StatusCode, "method: %s", method)
+		}
+	})
+}
+
+func TestRequestMethods(t *testing.T) {
+	t.Run("happy path - appends custom methods once", func(t *testing.T) {
+		requestMethods := RequestMethods([]config.Route{
+			{Method: fiber.MethodGet},
+			{Method: "PURGE"},
+			{Method: "PURGE"},
+			{Method: config.MethodAny},
+		})
+
+		assert.Equal(t, append(append([]string{}, fiber.DefaultMethods...), "PURGE"), requestMethods)
+		assert.NotContains(t, fiber.DefaultMethods, "PURGE")
+	})
+}
+
+func TestRouter_CreateRoutes_HealthCheckRoute(t *testing.T) {
+	t.Run("happy path - registers health route when enabled", func(t *testing.T) {
+		fiberApp := fiber.New()
+		router := &MainRouter{
+			Config: &config.Cfg{
+				Routes:           []config.Route{},
+				Concurrency:      1,
+				HealthCheckRoute: true,
+			},
+			FiberApp: fiberApp,
+		}
+		require.NoError(t, router.CreateRoutes())
+
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/health", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+	})
+}
+
+func TestRouter_CreateRoutes_StaticRoute(t *testing.T) {
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "users.json"), []byte(`[]`), 0o600))
+
+	routes := []config.Route{
+		{
+			Method: fiber.MethodGet,
+			Path:   "/fixtures/",
+			Static: &config.StaticDirectory{Dir: rootDir, ResolvedDir: rootDir},
+		},
+	}
+	fiberApp := fiber.New()
+	router := &MainRouter{
+		Config:        &config.Cfg{Routes: routes, Concurrency: 1},
+		FiberApp:      fiberApp,
+		StaticHandler: &handler.StaticHandler{RouteConfig: &routes},
+	}
+	require.NoError(t, router.CreateRoutes())
+
+	t.Run("happy path - serves files below the route path", func(t *testing.T) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/fixtures/users.json", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, response.StatusCode)
+	})
+
+	t.Run("error path - missing files are not found", func(t *testing.T) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, "/fixtures/orders.json", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotFound, response.StatusCode)
+	})
+}
@@ -0,0 +1,444 @@
+package router
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+type BuildFunc func(cfg *config.Cfg) (*fiber.App, error)
+
+// PersistFunc writes cfg, the current config of a route table, to filePath.
+type PersistFunc func(cfg *config.Cfg, filePath string) error
+
+// SwapFunc is called with the config a route table serves after every swap.
+type SwapFunc func(cfg *config.Cfg)
+
+// routeIDNamespace is the namespace of the ids derived for routes without one.
+var routeIDNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/lynicis/inzibat/routes"))
+
+// RouteTable serves requests from an app built out of the current routes and
+// swaps it atomically whenever the routes change.
+type RouteTable struct {
+	mu             sync.Mutex
+	reader         *config.Reader
+	build          BuildFunc
+	requestMethods []string
+	persist        PersistFunc
+	onSwap         SwapFunc
+	snapshot       atomic.Pointer[routeTableSnapshot]
+	// runtimeRoutes holds the ids of the routes added through AddRoute and not
+	// persisted since, and whether they were prepended. They are not in the
+	// config file, so Replace carries them over.
+	runtimeRoutes map[string]bool
+}
+
+type routeTableSnapshot struct {
+	cfg     *config.Cfg
+	handler fasthttp.RequestHandler
+}
+
+// NewRouteTable builds the initial app from cfg. The reader validates routes
+// added later and points at the file routes are persisted to.
+func NewRouteTable(cfg *config.Cfg, reader *config.Reader, build BuildFunc) (*RouteTable, error) {
+	if err := assignRouteIDs(cfg.Routes); err != nil {
+		return nil, err
+	}
+
+	table := &RouteTable{
+		reader:         reader,
+		build:          build,
+		requestMethods: RequestMethods(cfg.Routes),
+		persist:        config.WriteConfig,
+		runtimeRoutes:  make(map[string]bool),
+	}
+	if err := table.swap(cfg); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+func (table *RouteTable) RequestMethods() []string {
+	return slices.Clone(table.requestMethods)
+}
+
+func (table *RouteTable) Config() *config.Cfg {
+	return table.snapshot.Load().cfg
+}
+
+func (table *RouteTable) Routes() []config.Route {
+	return slices.Clone(table.Config().Routes)
+}
+
+func (table *RouteTable) Route(id string) (config.Route, bool) {
+	routes := table.Config().Routes
+	routeIndex := indexOfRoute(routes, id)
+	if routeIndex == -1 {
+		return config.Route{}, false
+	}
+
+	return routes[routeIndex], true
+}
+
+func (table *RouteTable) Handler() fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		table.snapshot.Load().handler(ctx.Context())
+		return nil
+	}
+}
+
+// AddRoute appends the route, or puts it in front of the existing routes when
+// prepend is set so it is tried before them.
+func (table *RouteTable) AddRoute(route config.Route, prepend bool) (config.Route, error) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	current := table.Config()
+	if route.ID != "" && indexOfRoute(current.Routes, route.ID) != -1 {
+		return config.Route{}, fmt.Errorf("%w: %s", ErrorRouteAlreadyExists, route.ID)
+	}
+
+	route, err := table.prepareRoute(current, route)
+	if err != nil {
+		return config.Route{}, err
+	}
+	// The derived id is left out of the submitted route, so it is not
+	// persisted.
+	if route.ID == "" {
+		if route.ID, err = deriveRouteID(route, routeIDSet(current.Routes)); err != nil {
+			return config.Route{}, err
+		}
+	}
+
+	routes := make([]config.Route, 0, len(current.Routes)+1)
+	if prepend {
+		routes = append(append(routes, route), current.Routes...)
+	} else {
+		routes = append(append(routes, current.Routes...), route)
+	}
+
+	if err = table.commit(current, routes); err != nil {
+		return config.Route{}, err
+	}
+	table.runtimeRoutes[route.ID] = prepend
+
+	return route, nil
+}
+
+func (table *RouteTable) ReplaceRoute(id string, route config.Route) (config.Route, error) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	current := table.Config()
+	routeIndex := indexOfRoute(current.Routes, id)
+	if routeIndex == -1 {
+		return config.Route{}, fmt.Errorf("%w: %s", ErrorRouteNotFound, id)
+	}
+
+	route, err := table.prepareRoute(current, route)
+	if err != nil {
+		return config.Route{}, err
+	}
+	route.ID = id
+	if route.Submitted != nil {
+		route.Submitted.ID = current.Routes[routeIndex].AsSubmitted().ID
+	}
+
+	routes := slices.Clone(current.Routes)
+	routes[routeIndex] = route
+
+	if err = table.commit(current, routes); err != nil {
+		return config.Route{}, err
+	}
+
+	return route, nil
+}
+
+func (table *RouteTable) DeleteRoute(id string) error {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	current := table.Config()
+	routeIndex := indexOfRoute(current.Routes, id)
+	if routeIndex == -1 {
+		return fmt.Errorf("%w: %s", ErrorRouteNotFound, id)
+	}
+
+	if err := table.commit(current, slices.Delete(slices.Clone(current.Routes), routeIndex, routeIndex+1)); err != nil {
+		return err
+	}
+	delete(table.runtimeRoutes, id)
+
+	return nil
+}
+
+// Replace swaps in a freshly read config. Requests already in flight finish on
+// the routes they started with. Routes added at runtime and not persisted are
+// kept in front of or after the new routes, as they were added.
+func (table *RouteTable) Replace(cfg *config.Cfg) error {
+	prepared, err := table.PrepareReplace(cfg)
+	if err != nil {
+		return err
+	}
+
+	prepared.Commit()
+	return nil
+}
+
+// PrepareReplace builds the app of a freshly read config without serving it,
+// so several tables can be checked before any of them is swapped.
+func (table *RouteTable) PrepareReplace(cfg *config.Cfg) (*PreparedReplace, error) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	runtimeRoutes := table.carryRuntimeRoutes(cfg)
+	if err := assignRouteIDs(cfg.Routes); err != nil {
+		return nil, err
+	}
+
+	for _, route := range cfg.Routes {
+		if err := table.checkMethod(route.Method); err != nil {
+			return nil, err
+		}
+	}
+
+	snapshot, err := table.newSnapshot(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedReplace{table: table, snapshot: snapshot, runtimeRoutes: runtimeRoutes}, nil
+}
+
+// PreparedReplace is a config built by PrepareReplace that is not served yet.
+type PreparedReplace struct {
+	table         *RouteTable
+	snapshot      *routeTableSnapshot
+	runtimeRoutes map[string]bool
+}
+
+// Commit swaps in the prepared config.
+func (prepared *PreparedReplace) Commit() {
+	prepared.table.mu.Lock()
+	defer prepared.table.mu.Unlock()
+
+	prepared.table.store(prepared.snapshot)
+	prepared.table.runtimeRoutes = prepared.runtimeRoutes
+}
+
+// carryRuntimeRoutes adds the routes added at runtime to cfg and returns the
+// ones carried over. A route whose id is in cfg already is left to cfg.
+func (table *RouteTable) carryRuntimeRoutes(cfg *config.Cfg) map[string]bool {
+	configRouteIDs := routeIDSet(cfg.Routes)
+	runtimeRoutes := make(map[string]bool, len(table.runtimeRoutes))
+	var prependedRoutes, appendedRoutes []config.Route
+	for _, route := range table.Config().Routes {
+		prepend, isRuntimeRoute := table.runtimeRoutes[route.ID]
+		if _, inConfig := configRouteIDs[route.ID]; !isRuntimeRoute || inConfig {
+			continue
+		}
+
+		runtimeRoutes[route.ID] = prepend
+		if prepend {
+			prependedRoutes = append(prependedRoutes, route)
+		} else {
+			appendedRoutes = append(appendedRoutes, route)
+		}
+	}
+
+	cfg.Routes = slices.Concat(prependedRoutes, cfg.Routes, appendedRoutes)
+	return runtimeRoutes
+}
+
+// Persist writes the current config back to the file it was read from.
+func (table *RouteTable) Persist() error {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	if table.reader == nil || table.reader.Filepath == "" {
+		return ErrorPersistenceDisabled
+	}
+
+	fileExtension := filepath.Ext(table.reader.Filepath)
+	if fileExtension != "" && fileExtension != config.DefaultConfigExtension {
+		return ErrorPersistUnsupported
+	}
+
+	// Routes are written as they were submitted, without the defaults and
+	// generated ids they are served with.
+	persistedCfg := *table.Config()
+	persistedCfg.Routes = make([]config.Route, 0, len(persistedCfg.Routes))
+	for _, route := range table.Config().Routes {
+		persistedCfg.Routes = append(persistedCfg.Routes, route.AsSubmitted())
+	}
+
+	if err := table.persist(&persistedCfg, table.reader.Filepath); err != nil {
+		return err
+	}
+	// The routes added at runtime are in the file now.
+	clear(table.runtimeRoutes)
+
+	return nil
+}
+
+// SetPersistFunc changes how Persist writes the config, for tables that only
+// hold part of the config file.
+func (table *RouteTable) SetPersistFunc(persist PersistFunc) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	table.persist = persist
+}
+
+// SetSwapFunc sets the func called after every swap, such as one dropping the
+// state kept for routes that are gone.
+func (table *RouteTable) SetSwapFunc(onSwap SwapFunc) {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	table.onSwap = onSwap
+}
+
+func (table *RouteTable) prepareRoute(current *config.Cfg, route config.Route) (config.Route, error) {
+	if err := table.checkMethod(route.Method); err != nil {
+		return config.Route{}, err
+	}
+
+	if table.reader == nil {
+		return route, nil
+	}
+
+	candidate := &config.Cfg{
+		ServerPort:        current.ServerPort,
+		CircuitBreaker:    current.CircuitBreaker,
+		Retry:             current.Retry,
+		UpstreamTimeoutMs: current.UpstreamTimeoutMs,
+		Routes:            []config.Route{route},
+	}
+	if err := table.reader.Prepare(candidate); err != nil {
+		return config.Route{}, err
+	}
+
+	return candidate.Routes[0], nil
+}
+
+func (table *RouteTable) checkMethod(method string) error {
+	if method != config.MethodAny && !slices.Contains(table.requestMethods, method) {
+		return fmt.Errorf("%w: %s", ErrorMethodNotEnabled, method)
+	}
+
+	return nil
+}
+
+func (table *RouteTable) commit(current *config.Cfg, routes []config.Route) error {
+	next := *current
+	next.Routes = routes
+
+	return table.swap(&next)
+}
+
+func (table *RouteTable) swap(cfg *config.Cfg) error {
+	snapshot, err := table.newSnapshot(cfg)
+	if err != nil {
+		return err
+	}
+
+	table.store(snapshot)
+	return nil
+}
+
+func (table *RouteTable) store(snapshot *routeTableSnapshot) {
+	table.snapshot.Store(snapshot)
+	if table.onSwap != nil {
+		table.onSwap(snapshot.cfg)
+	}
+}
+
+func (table *RouteTable) newSnapshot(cfg *config.Cfg) (*routeTableSnapshot, error) {
+	app, err := table.build(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &routeTableSnapshot{
+		cfg:     cfg,
+		handler: app.Handler(),
+	}, nil
+}
+
+// assignRouteIDs gives every route without an id one derived from the route
+// itself, so rebuilding the table from the same routes gives the same ids.
+func assignRouteIDs(routes []config.Route) error {
+	usedIDs := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		if route.ID == "" {
+			continue
+		}
+
+		if _, exists := usedIDs[route.ID]; exists {
+			return fmt.Errorf("%w: %s", ErrorRouteAlreadyExists, route.ID)
+		}
+		usedIDs[route.ID] = struct{}{}
+	}
+
+	for routeIndex := range routes {
+		route := &routes[routeIndex]
+		if route.ID != "" {
+			continue
+		}
+
+		routeID, err := deriveRouteID(*route, usedIDs)
+		if err != nil {
+			return err
+		}
+		route.ID = routeID
+		usedIDs[routeID] = struct{}{}
+	}
+
+	return nil
+}
+
+// deriveRouteID hashes the route as it was submitted into an id. Identical
+// routes are told apart by how many of them come before, so the id only
+// changes when the route does.
+func deriveRouteID(route config.Route, usedIDs map[string]struct{}) (string, error) {
+	submittedRoute := route.AsSubmitted()
+	submittedRoute.ID = ""
+	encodedRoute, err := json.Marshal(submittedRoute)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive route id: %w", err)
+	}
+
+	for occurrence := 0; ; occurrence++ {
+		routeID := uuid.NewSHA1(routeIDNamespace, append(encodedRoute, strconv.Itoa(occurrence)...)).String()
+		if _, exists := usedIDs[routeID]; !exists {
+			return routeID, nil
+		}
+	}
+}
+
+func routeIDSet(routes []config.Route) map[string]struct{} {
+	routeIDs := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		routeIDs[route.ID] = struct{}{}
+	}
+
+	return routeIDs
+}
+
+func indexOfRoute(routes []config.Route, id string) int {
+	return slices.IndexFunc(routes, func(route config.Route) bool {
+		return route.ID == id
+	})
+}
@@ -0,0 +1,431 @@
+package router
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+)
+
+func buildTestRouteApp(cfg *config.Cfg) (*fiber.App, error) {
+	routeApp := fiber.New(fiber.Config{
+		RequestMethods: RequestMethods(cfg.Routes),
+	})
+	mainRouter := &MainRouter{
+		Config:          cfg,
+		FiberApp:        routeApp,
+		EndpointHandler: &handler.EndpointHandler{RouteConfig: &cfg.Routes},
+		ClientHandler:   &handler.ClientHandler{},
+	}
+	if err := mainRouter.CreateRoutes(); err != nil {
+		return nil, err
+	}
+
+	return routeApp, nil
+}
+
+func newTestRouteTable(t *testing.T, configPath string, routes ...config.Route) (*RouteTable, *fiber.App) {
+	t.Helper()
+
+	cfg := &config.Cfg{
+		ServerPort:  8080,
+		Concurrency: 1,
+		Routes:      routes,
+	}
+	reader := &config.Reader{
+		Validator: validator.New(),
+		Filepath:  configPath,
+	}
+
+	table, err := NewRouteTable(cfg, reader, buildTestRouteApp)
+	require.NoError(t, err)
+
+	fiberApp := fiber.New()
+	RegisterRouteAdminRoutes(fiberApp, table)
+	fiberApp.Use(table.Handler())
+
+	return table, fiberApp
+}
+
+func sendTestRequest(t *testing.T, fiberApp *fiber.App, method, target string) (int, string) {
+	t.Helper()
+
+	response, err := fiberApp.Test(httptest.NewRequest(method, target, nil))
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func mockRoute(path, body string) config.Route {
+	return config.Route{
+		Method: fiber.MethodGet,
+		Path:   path,
+		FakeResponse: &config.FakeResponse{
+			StatusCode: fiber.StatusOK,
+			BodyString: body,
+		},
+	}
+}
+
+func TestNewRouteTable(t *testing.T) {
+	t.Run("happy path - assigns ids and serves the initial routes", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		routes := table.Routes()
+		require.Len(t, routes, 1)
+		assert.NotEmpty(t, routes[0].ID)
+
+		statusCode, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "users", body)
+	})
+
+	t.Run("happy path - derives the same ids from the same routes", func(t *testing.T) {
+		firstTable, _ := newTestRouteTable(t, "", mockRoute("/users", "users"), mockRoute("/users", "users"))
+		secondTable, _ := newTestRouteTable(t, "", mockRoute("/users", "users"), mockRoute("/users", "users"))
+
+		firstRoutes := firstTable.Routes()
+		assert.NotEqual(t, firstRoutes[0].ID, firstRoutes[1].ID)
+		assert.Equal(t, firstRoutes, secondTable.Routes())
+	})
+
+	t.Run("error path - duplicate route ids", func(t *testing.T) {
+		first := mockRoute("/one", "one")
+		first.ID = "same"
+		second := mockRoute("/two", "two")
+		second.ID = "same"
+
+		table, err := NewRouteTable(&config.Cfg{Routes: []config.Route{first, second}}, nil, buildTestRouteApp)
+
+		assert.Nil(t, table)
+		assert.True(t, errors.Is(err, ErrorRouteAlreadyExists))
+	})
+}
+
+func TestRouteTable_AddRoute(t *testing.T) {
+	t.Run("happy path - new route is served immediately", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		route, err := table.AddRoute(mockRoute("/orders", "orders"), false)
+		require.NoError(t, err)
+		assert.NotEmpty(t, route.ID)
+
+		statusCode, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/orders")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "orders", body)
+	})
+
+	t.Run("happy path - prepended route takes precedence on a shared path", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "static"))
+
+		_, err := table.AddRoute(mockRoute("/users", "runtime"), true)
+		require.NoError(t, err)
+
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "runtime", body)
+	})
+
+	t.Run("error path - invalid route keeps the previous table", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		_, err := table.AddRoute(config.Route{Method: fiber.MethodGet, Path: "no-slash"}, false)
+
+		assert.Error(t, err)
+		assert.Len(t, table.Routes(), 1)
+		statusCode, _ := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+	})
+
+	t.Run("error path - method not enabled at startup", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		route := mockRoute("/cache", "purged")
+		route.Method = "PURGE"
+
+		_, err := table.AddRoute(route, false)
+
+		assert.True(t, errors.Is(err, ErrorMethodNotEnabled))
+	})
+}
+
+func TestRouteTable_ReplaceRoute(t *testing.T) {
+	t.Run("happy path - replaced route is served", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "before"))
+		id := table.Routes()[0].ID
+
+		route, err := table.ReplaceRoute(id, mockRoute("/users", "after"))
+		require.NoError(t, err)
+		assert.Equal(t, id, route.ID)
+
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "after", body)
+	})
+
+	t.Run("error path - unknown id", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		_, err := table.ReplaceRoute("missing", mockRoute("/users", "after"))
+
+		assert.True(t, errors.Is(err, ErrorRouteNotFound))
+	})
+}
+
+func TestRouteTable_DeleteRoute(t *testing.T) {
+	t.Run("happy path - deleted route is no longer served", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		require.NoError(t, table.DeleteRoute(table.Routes()[0].ID))
+
+		assert.Empty(t, table.Routes())
+		statusCode, _ := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("error path - unknown id", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		err := table.DeleteRoute("missing")
+
+		assert.True(t, errors.Is(err, ErrorRouteNotFound))
+	})
+}
+
+func TestRouteTable_Replace(t *testing.T) {
+	t.Run("happy path - swaps in the new routes", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		err := table.Replace(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{mockRoute("/orders", "orders")},
+		})
+		require.NoError(t, err)
+
+		statusCode, _ := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/orders")
+		assert.Equal(t, "orders", body)
+		assert.NotEmpty(t, table.Routes()[0].ID)
+	})
+
+	t.Run("happy path - keeps the ids of unchanged routes", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		usersID := table.Routes()[0].ID
+
+		err := table.Replace(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{mockRoute("/orders", "orders"), mockRoute("/users", "users")},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, usersID, table.Routes()[1].ID)
+	})
+
+	t.Run("happy path - carries over routes added at runtime", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		prependedRoute, err := table.AddRoute(mockRoute("/users", "stubbed"), true)
+		require.NoError(t, err)
+		appendedRoute, err := table.AddRoute(mockRoute("/carts", "carts"), false)
+		require.NoError(t, err)
+
+		err = table.Replace(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{mockRoute("/users", "new users")},
+		})
+		require.NoError(t, err)
+
+		routes := table.Routes()
+		require.Len(t, routes, 3)
+		assert.Equal(t, prependedRoute.ID, routes[0].ID)
+		assert.Equal(t, appendedRoute.ID, routes[2].ID)
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "stubbed", body)
+	})
+
+	t.Run("happy path - leaves persisted routes to the file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.json")
+		table, _ := newTestRouteTable(t, configPath, mockRoute("/users", "users"))
+		_, err := table.AddRoute(mockRoute("/carts", "carts"), false)
+		require.NoError(t, err)
+		require.NoError(t, table.Persist())
+
+		err = table.Replace(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{mockRoute("/users", "users")},
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, table.Routes(), 1)
+	})
+
+	t.Run("error path - invalid routes keep the previous table", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		route := mockRoute("/users", "users")
+		route.Match = &config.RouteMatch{
+			Query: map[string]config.ValueMatcher{"role": {Regex: "("}},
+		}
+
+		err := table.Replace(&config.Cfg{
+			Concurrency: 1,
+			Routes:      []config.Route{route},
+		})
+
+		assert.Error(t, err)
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "users", body)
+	})
+
+	t.Run("error path - method not enabled at startup", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		route := mockRoute("/cache", "purged")
+		route.Method = "PURGE"
+
+		err := table.Replace(&config.Cfg{Concurrency: 1, Routes: []config.Route{route}})
+
+		assert.ErrorIs(t, err, ErrorMethodNotEnabled)
+	})
+}
+
+func TestRouteTable_SetSwapFunc(t *testing.T) {
+	t.Run("happy path - is called with the routes after every change", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+		var swappedRoutes []config.Route
+		table.SetSwapFunc(func(cfg *config.Cfg) {
+			swappedRoutes = cfg.Routes
+		})
+
+		addedRoute, err := table.AddRoute(mockRoute("/orders", "orders"), false)
+		require.NoError(t, err)
+		require.Len(t, swappedRoutes, 2)
+		assert.Equal(t, addedRoute.ID, swappedRoutes[1].ID)
+
+		require.NoError(t, table.DeleteRoute(addedRoute.ID))
+		assert.Len(t, swappedRoutes, 1)
+	})
+}
+
+func TestRouteTable_PrepareReplace(t *testing.T) {
+	t.Run("happy path - serves the new routes only once committed", func(t *testing.T) {
+		table, fiberApp := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		prepared, err := table.PrepareReplace(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{mockRoute("/users", "new users")},
+		})
+		require.NoError(t, err)
+
+		_, body := sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "users", body)
+
+		prepared.Commit()
+
+		_, body = sendTestRequest(t, fiberApp, fiber.MethodGet, "/users")
+		assert.Equal(t, "new users", body)
+	})
+}
+
+func TestRouteTable_Persist(t *testing.T) {
+	t.Run("happy path - writes routes to the config file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.json")
+		table, _ := newTestRouteTable(t, configPath, mockRoute("/users", "users"))
+		_, err := table.AddRoute(mockRoute("/orders", "orders"), false)
+		require.NoError(t, err)
+
+		require.NoError(t, table.Persist())
+
+		readerStrategy, err := config.NewReaderStrategy(config.DefaultConfigExtension)
+		require.NoError(t, err)
+		persistedCfg, err := readerStrategy.Read(configPath)
+		require.NoError(t, err)
+		require.Len(t, persistedCfg.Routes, 2)
+		assert.Equal(t, "/orders", persistedCfg.Routes[1].Path)
+		assert.NotEmpty(t, table.Routes()[1].ID)
+		assert.Empty(t, persistedCfg.Routes[1].ID)
+	})
+
+	t.Run("happy path - writes routes without the defaults merged into them", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.json")
+		cfg := &config.Cfg{
+			ServerPort:        8080,
+			Concurrency:       1,
+			CircuitBreaker:    &config.CircuitBreakerConfig{Enabled: config.BoolPointer(true)},
+			Retry:             &config.RetryPolicy{MaxAttempts: 2},
+			UpstreamTimeoutMs: 3000,
+			Routes:            []config.Route{mockRoute("/users", "users")},
+		}
+		table, err := NewRouteTable(cfg, &config.Reader{Validator: validator.New(), Filepath: configPath}, buildTestRouteApp)
+		require.NoError(t, err)
+		_, err = table.AddRoute(config.Route{
+			Method:    fiber.MethodGet,
+			Path:      "/orders",
+			RequestTo: &config.RequestTo{Host: "http://127.0.0.1:3001", Path: "/"},
+		}, false)
+		require.NoError(t, err)
+		servedRoute := table.Routes()[1]
+		require.Equal(t, 3000, servedRoute.RequestTo.TimeoutMs)
+		require.NotNil(t, servedRoute.RequestTo.CircuitBreaker)
+
+		require.NoError(t, table.Persist())
+
+		readerStrategy, err := config.NewReaderStrategy(config.DefaultConfigExtension)
+		require.NoError(t, err)
+		persistedCfg, err := readerStrategy.Read(configPath)
+		require.NoError(t, err)
+		require.Len(t, persistedCfg.Routes, 2)
+		persistedRoute := persistedCfg.Routes[1]
+		assert.Empty(t, persistedRoute.ID)
+		assert.Zero(t, persistedRoute.RequestTo.TimeoutMs)
+		assert.Nil(t, persistedRoute.RequestTo.CircuitBreaker)
+		assert.Nil(t, persistedRoute.RequestTo.Retry)
+	})
+
+	t.Run("happy path - uses the persist func that was set", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.json")
+		table, _ := newTestRouteTable(t, configPath, mockRoute("/users", "users"))
+
+		var persistedPath string
+		var persistedRoutes []config.Route
+		table.SetPersistFunc(func(cfg *config.Cfg, filePath string) error {
+			persistedPath = filePath
+			persistedRoutes = cfg.Routes
+			return nil
+		})
+
+		require.NoError(t, table.Persist())
+		assert.Equal(t, configPath, persistedPath)
+		assert.Equal(t, table.Routes(), persistedRoutes)
+		_, err := os.Stat(configPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("error path - unknown config file", func(t *testing.T) {
+		table, _ := newTestRouteTable(t, "", mockRoute("/users", "users"))
+
+		assert.ErrorIs(t, table.Persist(), ErrorPersistenceDisabled)
+	})
+
+	t.Run("error path - non JSON config file", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "inzibat.yaml")
+		table, _ := newTestRouteTable(t, configPath, mockRoute("/users", "users"))
+
+		assert.ErrorIs(t, table.Persist(), ErrorPersistUnsupported)
+		_, err := os.Stat(configPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
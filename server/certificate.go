@@ -0,0 +1,242 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	caCertFileName     = "ca.pem"
+	caKeyFileName      = "ca-key.pem"
+	serverCertFileName = "server.pem"
+	serverKeyFileName  = "server-key.pem"
+	clientCertFileName = "client.pem"
+	clientKeyFileName  = "client-key.pem"
+
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 825 * 24 * time.Hour
+	// The CA is renewed while it still outlives every leaf it could issue.
+	caRenewBeforeExpiry   = leafValidity
+	leafRenewBeforeExpiry = 30 * 24 * time.Hour
+
+	certDirPerm  = 0700
+	certFilePerm = 0644
+	keyFilePerm  = 0600
+)
+
+var defaultCertificateHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// certificateAuthority is the local CA that signs the generated server and
+// client certificates.
+type certificateAuthority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+// loadOrCreateCA reads the CA cached in certDir, or generates and caches a new
+// one when there is none or the cached one expires soon. The certificates
+// signed by the previous CA are reissued once they are loaded.
+func loadOrCreateCA(certDir string) (*certificateAuthority, error) {
+	certPath := filepath.Join(certDir, caCertFileName)
+	keyPath := filepath.Join(certDir, caKeyFileName)
+
+	keyPair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err == nil {
+		certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CA certificate %s: %w", certPath, err)
+		}
+		key, isECDSA := keyPair.PrivateKey.(*ecdsa.PrivateKey)
+		if !isECDSA {
+			return nil, fmt.Errorf("CA key %s is not an ECDSA key", keyPath)
+		}
+
+		if time.Until(certificate.NotAfter) >= caRenewBeforeExpiry {
+			return &certificateAuthority{certificate: certificate, key: key}, nil
+		}
+		zap.L().Info("🔐 Renewing the local CA, it expires soon", zap.Time("not_after", certificate.NotAfter))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load CA from %s: %w", certDir, err)
+	}
+
+	return createCA(certPath, keyPath, caValidity)
+}
+
+func createCA(certPath, keyPath string, validity time.Duration) (*certificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key: %w", err)
+	}
+
+	template, err := newCertificateTemplate("Inzibat Local CA", validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate: %w", err)
+	}
+	if err := writeKeyPair(certPath, keyPath, der, key); err != nil {
+		return nil, err
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+
+	return &certificateAuthority{certificate: certificate, key: key}, nil
+}
+
+// loadOrCreateServerCertificate returns the cached server certificate when it
+// is signed by ca, covers every host and does not expire soon; otherwise it
+// issues and caches a new one.
+func loadOrCreateServerCertificate(certDir string, ca *certificateAuthority, hosts []string) (tls.Certificate, error) {
+	certPath := filepath.Join(certDir, serverCertFileName)
+	keyPath := filepath.Join(certDir, serverKeyFileName)
+	hosts = append(append([]string{}, defaultCertificateHosts...), hosts...)
+
+	if keyPair, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil && isUsableServerCertificate(keyPair, ca, hosts) {
+		return keyPair, nil
+	}
+
+	return ca.issue(certPath, keyPath, "Inzibat Server", x509.ExtKeyUsageServerAuth, hosts, leafValidity)
+}
+
+// loadOrCreateClientCertificate makes sure a client certificate signed by ca
+// that does not expire soon is cached next to it, for clients of mTLS routes.
+func loadOrCreateClientCertificate(certDir string, ca *certificateAuthority) error {
+	certPath := filepath.Join(certDir, clientCertFileName)
+	keyPath := filepath.Join(certDir, clientKeyFileName)
+
+	if keyPair, err := tls.LoadX509KeyPair(certPath, keyPath); err == nil &&
+		isSignedBy(keyPair, ca) && !expiresSoon(keyPair.Leaf) {
+		return nil
+	}
+
+	_, err := ca.issue(certPath, keyPath, "Inzibat Client", x509.ExtKeyUsageClientAuth, nil, leafValidity)
+	return err
+}
+
+func (ca *certificateAuthority) issue(
+	certPath, keyPath, commonName string,
+	usage x509.ExtKeyUsage,
+	hosts []string,
+	validity time.Duration,
+) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate key for %s: %w", commonName, err)
+	}
+
+	template, err := newCertificateTemplate(commonName, validity)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+			continue
+		}
+		template.DNSNames = append(template.DNSNames, host)
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create certificate for %s: %w", commonName, err)
+	}
+	if err := writeKeyPair(certPath, keyPath, der, key); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.LoadX509KeyPair(certPath, keyPath)
+}
+
+func isUsableServerCertificate(keyPair tls.Certificate, ca *certificateAuthority, hosts []string) bool {
+	if !isSignedBy(keyPair, ca) {
+		return false
+	}
+
+	certificate := keyPair.Leaf
+	if expiresSoon(certificate) {
+		return false
+	}
+	for _, host := range hosts {
+		if certificate.VerifyHostname(host) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func expiresSoon(certificate *x509.Certificate) bool {
+	return time.Until(certificate.NotAfter) < leafRenewBeforeExpiry
+}
+
+func isSignedBy(keyPair tls.Certificate, ca *certificateAuthority) bool {
+	if keyPair.Leaf == nil {
+		return false
+	}
+
+	return keyPair.Leaf.CheckSignatureFrom(ca.certificate) == nil
+}
+
+func newCertificateTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{Organization: []string{"Inzibat"}, CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func writeKeyPair(certPath, keyPath string, der []byte, key *ecdsa.PrivateKey) error {
+	if err := os.MkdirAll(filepath.Dir(certPath), certDirPerm); err != nil {
+		return fmt.Errorf("failed to create certificate directory: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to encode key: %w", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	// #nosec G306 - Certificates are public
+	if err := os.WriteFile(certPath, certPEM, certFilePerm); err != nil {
+		return fmt.Errorf("failed to write certificate %s: %w", certPath, err)
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(keyPath, keyPEM, keyFilePerm); err != nil {
+		return fmt.Errorf("failed to write key %s: %w", keyPath, err)
+	}
+
+	return nil
+}
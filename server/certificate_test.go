@@ -0,0 +1,173 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrCreateCA(t *testing.T) {
+	t.Run("happy path - generates a CA and reuses it", func(t *testing.T) {
+		certDir := filepath.Join(t.TempDir(), "certs")
+
+		ca, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+		assert.True(t, ca.certificate.IsCA)
+		assert.FileExists(t, filepath.Join(certDir, caCertFileName))
+
+		keyInfo, err := os.Stat(filepath.Join(certDir, caKeyFileName))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(keyFilePerm), keyInfo.Mode().Perm())
+
+		cachedCA, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+		assert.Equal(t, ca.certificate.SerialNumber, cachedCA.certificate.SerialNumber)
+	})
+
+	t.Run("happy path - renews a CA that expires soon", func(t *testing.T) {
+		certDir := t.TempDir()
+		expiringCA, err := createCA(
+			filepath.Join(certDir, caCertFileName),
+			filepath.Join(certDir, caKeyFileName),
+			24*time.Hour,
+		)
+		require.NoError(t, err)
+
+		ca, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, expiringCA.certificate.SerialNumber, ca.certificate.SerialNumber)
+		assert.Greater(t, time.Until(ca.certificate.NotAfter), caRenewBeforeExpiry)
+
+		cachedCA, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+		assert.Equal(t, ca.certificate.SerialNumber, cachedCA.certificate.SerialNumber)
+	})
+
+	t.Run("error path - unreadable CA files", func(t *testing.T) {
+		certDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(certDir, caCertFileName), []byte("garbage"), 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(certDir, caKeyFileName), []byte("garbage"), 0600))
+
+		_, err := loadOrCreateCA(certDir)
+
+		assert.ErrorContains(t, err, "failed to load CA")
+	})
+}
+
+func TestLoadOrCreateServerCertificate(t *testing.T) {
+	certDir := t.TempDir()
+	ca, err := loadOrCreateCA(certDir)
+	require.NoError(t, err)
+
+	t.Run("happy path - covers localhost and the configured hosts", func(t *testing.T) {
+		certificate, err := loadOrCreateServerCertificate(certDir, ca, []string{"api.local", "10.0.0.7"})
+		require.NoError(t, err)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.certificate)
+		for _, host := range []string{"localhost", "127.0.0.1", "::1", "api.local", "10.0.0.7"} {
+			_, err := certificate.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+			assert.NoError(t, err, host)
+		}
+	})
+
+	t.Run("happy path - reuses the cached certificate", func(t *testing.T) {
+		first, err := loadOrCreateServerCertificate(certDir, ca, []string{"api.local"})
+		require.NoError(t, err)
+
+		second, err := loadOrCreateServerCertificate(certDir, ca, []string{"api.local"})
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Leaf.SerialNumber, second.Leaf.SerialNumber)
+	})
+
+	t.Run("happy path - reissues the certificate when a host is added", func(t *testing.T) {
+		first, err := loadOrCreateServerCertificate(certDir, ca, nil)
+		require.NoError(t, err)
+
+		second, err := loadOrCreateServerCertificate(certDir, ca, []string{"new.local"})
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first.Leaf.SerialNumber, second.Leaf.SerialNumber)
+		assert.NoError(t, second.Leaf.VerifyHostname("new.local"))
+	})
+
+	t.Run("happy path - reissues the certificate for a new CA", func(t *testing.T) {
+		first, err := loadOrCreateServerCertificate(certDir, ca, nil)
+		require.NoError(t, err)
+
+		otherCA, err := loadOrCreateCA(t.TempDir())
+		require.NoError(t, err)
+		second, err := loadOrCreateServerCertificate(certDir, otherCA, nil)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first.Leaf.SerialNumber, second.Leaf.SerialNumber)
+		assert.NoError(t, second.Leaf.CheckSignatureFrom(otherCA.certificate))
+	})
+
+	t.Run("happy path - reissues a certificate that expires soon", func(t *testing.T) {
+		expiring, err := ca.issue(
+			filepath.Join(certDir, serverCertFileName),
+			filepath.Join(certDir, serverKeyFileName),
+			"Inzibat Server",
+			x509.ExtKeyUsageServerAuth,
+			defaultCertificateHosts,
+			24*time.Hour,
+		)
+		require.NoError(t, err)
+
+		renewed, err := loadOrCreateServerCertificate(certDir, ca, nil)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, expiring.Leaf.SerialNumber, renewed.Leaf.SerialNumber)
+		assert.Greater(t, time.Until(renewed.Leaf.NotAfter), leafRenewBeforeExpiry)
+	})
+}
+
+func TestLoadOrCreateClientCertificate(t *testing.T) {
+	t.Run("happy path - issues a client certificate signed by the CA", func(t *testing.T) {
+		certDir := t.TempDir()
+		ca, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+
+		require.NoError(t, loadOrCreateClientCertificate(certDir, ca))
+
+		clientCertificate, err := tls.LoadX509KeyPair(
+			filepath.Join(certDir, clientCertFileName),
+			filepath.Join(certDir, clientKeyFileName),
+		)
+		require.NoError(t, err)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.certificate)
+		_, err = clientCertificate.Leaf.Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("happy path - reissues a client certificate that expires soon", func(t *testing.T) {
+		certDir := t.TempDir()
+		ca, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+		certPath := filepath.Join(certDir, clientCertFileName)
+		keyPath := filepath.Join(certDir, clientKeyFileName)
+		expiring, err := ca.issue(certPath, keyPath, "Inzibat Client", x509.ExtKeyUsageClientAuth, nil, 24*time.Hour)
+		require.NoError(t, err)
+
+		require.NoError(t, loadOrCreateClientCertificate(certDir, ca))
+
+		renewed, err := tls.LoadX509KeyPair(certPath, keyPath)
+		require.NoError(t, err)
+		assert.NotEqual(t, expiring.Leaf.SerialNumber, renewed.Leaf.SerialNumber)
+		assert.Greater(t, time.Until(renewed.Leaf.NotAfter), leafRenewBeforeExpiry)
+	})
+}
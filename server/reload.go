@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/router"
+)
+
+const configReloadDebounce = 100 * time.Millisecond
+
+// watchConfig reloads the routes whenever the config file changes or the
+// process receives SIGHUP, until ctx is done.
+func watchConfig(ctx context.Context, configLoader *config.Reader, services []*service) error {
+	configPath := filepath.Clean(configLoader.Filepath)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+
+	// Editors often replace the file instead of writing to it, so the
+	// directory is watched rather than the file itself.
+	if err = watcher.Add(filepath.Dir(configPath)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	hangupSignal := make(chan os.Signal, 1)
+	signal.Notify(hangupSignal, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hangupSignal)
+		defer watcher.Close()
+
+		var reloadTimer <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Clean(event.Name) == configPath && event.Has(fsnotify.Write|fsnotify.Create) {
+					reloadTimer = time.After(configReloadDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				zap.L().Warn("config watcher error", zap.Error(err))
+			case <-hangupSignal:
+				reloadConfig(configLoader, services)
+			case <-reloadTimer:
+				reloadTimer = nil
+				reloadConfig(configLoader, services)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func reloadConfig(configLoader *config.Reader, services []*service) bool {
+	cfg, err := configLoader.Read()
+	if err != nil {
+		zap.L().Error("failed to reload config, keeping the previous routes", zap.Error(err))
+		return false
+	}
+
+	serviceConfigs := cfg.ServiceConfigs()
+	for _, serviceConfig := range serviceConfigs {
+		if !slices.ContainsFunc(services, func(inzibatService *service) bool {
+			return inzibatService.name == serviceConfig.Name
+		}) {
+			zap.L().Warn("new services need a restart to take effect", zap.String("service", serviceConfig.Name))
+		}
+	}
+
+	// Every service is built before any is swapped, so a bad service leaves
+	// all of them on their previous routes.
+	preparedReplaces := make([]*router.PreparedReplace, 0, len(services))
+	reloadedRoutes := 0
+	for _, inzibatService := range services {
+		serviceIndex := slices.IndexFunc(serviceConfigs, func(serviceConfig config.ServiceConfig) bool {
+			return serviceConfig.Name == inzibatService.name
+		})
+		if serviceIndex == -1 {
+			zap.L().Warn("removed services need a restart to take effect", zap.String("service", inzibatService.name))
+			continue
+		}
+
+		serviceCfg := serviceConfigs[serviceIndex].Cfg
+		if serviceCfg.ServerPort != inzibatService.port ||
+			!strings.EqualFold(serviceConfigs[serviceIndex].Host, inzibatService.host) {
+			zap.L().Warn("server port and host changes need a restart to take effect",
+				zap.String("service", inzibatService.name),
+				zap.Int("server_port", inzibatService.port),
+			)
+			serviceCfg.ServerPort = inzibatService.port
+		}
+
+		preparedReplace, err := inzibatService.server.RouteTable.PrepareReplace(serviceCfg)
+		if err != nil {
+			zap.L().Error("failed to reload config, keeping the previous routes",
+				zap.String("service", inzibatService.name),
+				zap.Error(err),
+			)
+			return false
+		}
+		preparedReplaces = append(preparedReplaces, preparedReplace)
+		reloadedRoutes += len(serviceCfg.Routes)
+	}
+
+	for _, preparedReplace := range preparedReplaces {
+		preparedReplace.Commit()
+	}
+
+	zap.L().Info("🔄 Config reloaded", zap.Int("open_routes", reloadedRoutes))
+	return true
+}
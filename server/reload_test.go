@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	validatorPkg "github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+)
+
+func newReloadTestServer(t *testing.T, routes ...config.Route) (string, *config.Reader, *fiber.App, []*service) {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "inzibat.json")
+	writeReloadTestConfig(t, configPath, routes...)
+
+	configLoader := config.NewLoader(validatorPkg.New(), false, configPath)
+	cfg, err := configLoader.Read()
+	require.NoError(t, err)
+
+	services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+	require.NoError(t, err)
+
+	return configPath, configLoader, services[0].server.App, services
+}
+
+func writeReloadTestConfig(t *testing.T, configPath string, routes ...config.Route) {
+	t.Helper()
+
+	require.NoError(t, config.WriteConfig(&config.Cfg{
+		ServerPort:  8080,
+		Concurrency: 1,
+		Routes:      routes,
+	}, configPath))
+}
+
+func reloadTestRoute(path, body string) config.Route {
+	return config.Route{
+		Method: fiber.MethodGet,
+		Path:   path,
+		FakeResponse: &config.FakeResponse{
+			StatusCode: fiber.StatusOK,
+			BodyString: body,
+		},
+	}
+}
+
+func sendReloadTestRequest(t *testing.T, fiberApp *fiber.App, target string) (int, string) {
+	t.Helper()
+
+	response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, target, nil), -1)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func waitForReloadTestBody(t *testing.T, fiberApp *fiber.App, target, expectedBody string) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		_, body := sendReloadTestRequest(t, fiberApp, target)
+		return body == expectedBody
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestReloadConfig(t *testing.T) {
+	t.Run("happy path - swaps in the routes from the file", func(t *testing.T) {
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, reloadTestRoute("/users", "before"))
+
+		writeReloadTestConfig(t, configPath, reloadTestRoute("/users", "after"))
+
+		assert.True(t, reloadConfig(configLoader, services))
+		_, body := sendReloadTestRequest(t, fiberApp, "/users")
+		assert.Equal(t, "after", body)
+	})
+
+	t.Run("error path - invalid file keeps the previous routes", func(t *testing.T) {
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, reloadTestRoute("/users", "before"))
+
+		require.NoError(t, os.WriteFile(configPath, []byte(`{"serverPort":8080,"routes":[{"method":"GET","path":"users"}]}`), 0o600))
+
+		assert.False(t, reloadConfig(configLoader, services))
+		_, body := sendReloadTestRequest(t, fiberApp, "/users")
+		assert.Equal(t, "before", body)
+	})
+
+	t.Run("happy path - keeps circuit breaker state for unchanged routes", func(t *testing.T) {
+		freePort, err := http.GetFreePort()
+		require.NoError(t, err)
+
+		proxyRoute := config.Route{
+			Method: fiber.MethodGet,
+			Path:   "/proxy",
+			RequestTo: &config.RequestTo{
+				Method:           fiber.MethodGet,
+				Host:             fmt.Sprintf("http://127.0.0.1:%d", freePort),
+				Path:             "/",
+				InErrorReturn500: true,
+				CircuitBreaker: &config.CircuitBreakerConfig{
+					Enabled:          config.BoolPointer(true),
+					FailureThreshold: 1,
+					MinimumRequests:  1,
+					OpenTimeoutMs:    60000,
+				},
+			},
+		}
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, proxyRoute)
+
+		statusCode, _ := sendReloadTestRequest(t, fiberApp, "/proxy")
+		require.Equal(t, fiber.StatusInternalServerError, statusCode)
+		statusCode, _ = sendReloadTestRequest(t, fiberApp, "/proxy")
+		require.Equal(t, fiber.StatusServiceUnavailable, statusCode)
+
+		writeReloadTestConfig(t, configPath, proxyRoute, reloadTestRoute("/users", "users"))
+		require.True(t, reloadConfig(configLoader, services))
+
+		statusCode, _ = sendReloadTestRequest(t, fiberApp, "/proxy")
+		assert.Equal(t, fiber.StatusServiceUnavailable, statusCode)
+	})
+
+	t.Run("happy path - keeps sequence positions and runtime routes of unchanged routes", func(t *testing.T) {
+		sequenceRoute := config.Route{
+			Method: fiber.MethodGet,
+			Path:   "/steps",
+			Sequence: &config.ResponseSequence{
+				Responses: []config.FakeResponse{
+					{StatusCode: fiber.StatusOK, BodyString: "first"},
+					{StatusCode: fiber.StatusOK, BodyString: "second"},
+				},
+			},
+		}
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, sequenceRoute)
+		_, body := sendReloadTestRequest(t, fiberApp, "/steps")
+		require.Equal(t, "first", body)
+		_, err := services[0].server.RouteTable.AddRoute(reloadTestRoute("/stub", "stubbed"), false)
+		require.NoError(t, err)
+
+		writeReloadTestConfig(t, configPath, sequenceRoute, reloadTestRoute("/users", "users"))
+		require.True(t, reloadConfig(configLoader, services))
+
+		_, body = sendReloadTestRequest(t, fiberApp, "/steps")
+		assert.Equal(t, "second", body)
+		_, body = sendReloadTestRequest(t, fiberApp, "/stub")
+		assert.Equal(t, "stubbed", body)
+	})
+
+	t.Run("happy path - restarts the sequences of changed routes", func(t *testing.T) {
+		sequenceRoute := func(firstBody string) config.Route {
+			return config.Route{
+				Method: fiber.MethodGet,
+				Path:   "/steps",
+				Sequence: &config.ResponseSequence{
+					Responses: []config.FakeResponse{
+						{StatusCode: fiber.StatusOK, BodyString: firstBody},
+						{StatusCode: fiber.StatusOK, BodyString: "second"},
+					},
+				},
+			}
+		}
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, sequenceRoute("first"))
+		_, body := sendReloadTestRequest(t, fiberApp, "/steps")
+		require.Equal(t, "first", body)
+
+		writeReloadTestConfig(t, configPath, sequenceRoute("new first"))
+		require.True(t, reloadConfig(configLoader, services))
+
+		_, body = sendReloadTestRequest(t, fiberApp, "/steps")
+		assert.Equal(t, "new first", body)
+	})
+}
+
+func TestWatchConfig(t *testing.T) {
+	t.Run("happy path - reloads when the file changes", func(t *testing.T) {
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, reloadTestRoute("/users", "before"))
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, watchConfig(ctx, configLoader, services))
+
+		writeReloadTestConfig(t, configPath, reloadTestRoute("/users", "after"))
+
+		waitForReloadTestBody(t, fiberApp, "/users", "after")
+	})
+
+	t.Run("error path - missing config directory", func(t *testing.T) {
+		configLoader := &config.Reader{Filepath: filepath.Join(t.TempDir(), "missing", "inzibat.json")}
+
+		err := watchConfig(context.Background(), configLoader, nil)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to watch config directory")
+	})
+}
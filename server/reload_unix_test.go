@@ -0,0 +1,28 @@
+//go:build !windows
+
+package server
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchConfig_Hangup(t *testing.T) {
+	t.Run("happy path - reloads on SIGHUP", func(t *testing.T) {
+		configPath, configLoader, fiberApp, services := newReloadTestServer(t, reloadTestRoute("/users", "before"))
+		writeReloadTestConfig(t, configPath, reloadTestRoute("/users", "after"))
+		time.Sleep(2 * configReloadDebounce)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, watchConfig(ctx, configLoader, services))
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+		waitForReloadTestBody(t, fiberApp, "/users", "after")
+	})
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+	"github.com/lynicis/inzibat/router"
+)
+
+type routeAppBuilder struct {
+	httpClient          *http.Client
+	scenarioStore       *handler.ScenarioStore
+	circuitBreakerStore *handler.CircuitBreakerStore
+	requestMethods      []string
+	// unmatchedHandlers answer the requests no route matched, in place of the
+	// default 404.
+	unmatchedHandlers []fiber.Handler
+}
+
+func (builder *routeAppBuilder) Build(cfg *config.Cfg) (*fiber.App, error) {
+	responseTemplates, err := handler.BuildResponseTemplates(cfg.Routes)
+	if err != nil {
+		return nil, err
+	}
+	fallbackTemplates, err := handler.BuildFallbackTemplates(cfg.Routes)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointHandler := &handler.EndpointHandler{
+		RouteConfig:       &cfg.Routes,
+		ResponseTemplates: responseTemplates,
+	}
+	builder.scenarioStore.Seed(cfg.Routes)
+	sequenceHandler := &handler.SequenceHandler{
+		RouteConfig:   &cfg.Routes,
+		ScenarioStore: builder.scenarioStore,
+	}
+
+	circuitBreakerRouteKeys := make(map[int]string)
+	circuitBreakerHostKeys := make(map[int][]string)
+	for routeIndex := range cfg.Routes {
+		route := cfg.Routes[routeIndex]
+		if route.RequestTo == nil || route.RequestTo.CircuitBreaker == nil {
+			continue
+		}
+
+		if route.RequestTo.CircuitBreaker.Enabled != nil && *route.RequestTo.CircuitBreaker.Enabled {
+			routeKeys := []string{handler.BuildCircuitBreakerRouteKey(route)}
+			if len(route.RequestTo.Hosts) > 0 {
+				routeKeys = handler.BuildCircuitBreakerHostKeys(route)
+				circuitBreakerHostKeys[routeIndex] = routeKeys
+			} else {
+				circuitBreakerRouteKeys[routeIndex] = routeKeys[0]
+			}
+
+			for _, routeKey := range routeKeys {
+				if err = builder.circuitBreakerStore.Seed(routeKey, *route.RequestTo.CircuitBreaker); err != nil {
+					return nil, fmt.Errorf("failed to seed circuit breaker store: %w", err)
+				}
+			}
+		}
+	}
+
+	clientHandler := &handler.ClientHandler{
+		Client:                  builder.httpClient,
+		RouteConfig:             &cfg.Routes,
+		CircuitBreakerStore:     builder.circuitBreakerStore,
+		CircuitBreakerRouteKeys: circuitBreakerRouteKeys,
+		CircuitBreakerHostKeys:  circuitBreakerHostKeys,
+		UpstreamBalancers:       handler.BuildUpstreamBalancers(cfg.Routes),
+		FallbackTemplates:       fallbackTemplates,
+	}
+
+	routeApp := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		JSONDecoder:           json.Unmarshal,
+		JSONEncoder:           json.Marshal,
+		RequestMethods:        builder.requestMethods,
+	})
+
+	mainRouter := &router.MainRouter{
+		Config:          cfg,
+		FiberApp:        routeApp,
+		EndpointHandler: endpointHandler,
+		ClientHandler:   clientHandler,
+		SequenceHandler: sequenceHandler,
+		StaticHandler:   &handler.StaticHandler{RouteConfig: &cfg.Routes},
+		ScenarioStore:   builder.scenarioStore,
+		Mirrors:         handler.BuildRequestMirrors(builder.httpClient, cfg.Routes),
+	}
+	if err = mainRouter.CreateRoutes(); err != nil {
+		return nil, fmt.Errorf("failed to create routes: %w", err)
+	}
+	for _, unmatchedHandler := range builder.unmatchedHandlers {
+		routeApp.Use(unmatchedHandler)
+	}
+
+	return routeApp, nil
+}
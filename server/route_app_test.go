@@ -0,0 +1,303 @@
+package server
+
+import (
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/handler"
+	"github.com/lynicis/inzibat/recorder"
+)
+
+func TestSetupServer(t *testing.T) {
+	newServer := func(t *testing.T, recordEnabled bool) *fiber.App {
+		cfg := &config.Cfg{
+			ServerPort:       8080,
+			Concurrency:      1,
+			HealthCheckRoute: true,
+			Routes: []config.Route{
+				{
+					Method: fiber.MethodGet,
+					Path:   "/users",
+					FakeResponse: &config.FakeResponse{
+						StatusCode: fiber.StatusOK,
+						BodyString: "users",
+					},
+				},
+			},
+		}
+
+		inzibatServer, err := setupServer(cfg, nil, RunOptions{RecordEnabled: recordEnabled}, nil)
+		require.NoError(t, err)
+
+		return inzibatServer.App
+	}
+
+	sendRequest := func(t *testing.T, fiberApp *fiber.App, method, target, body string) (int, string) {
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		response, err := fiberApp.Test(request)
+		require.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, string(responseBody)
+	}
+
+	t.Run("happy path - serves configured and health routes", func(t *testing.T) {
+		fiberApp := newServer(t, false)
+
+		statusCode, body := sendRequest(t, fiberApp, fiber.MethodGet, "/users", "")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "users", body)
+
+		statusCode, _ = sendRequest(t, fiberApp, fiber.MethodGet, "/health", "")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+	})
+
+	t.Run("happy path - routes added at runtime are served and recorded", func(t *testing.T) {
+		fiberApp := newServer(t, true)
+
+		statusCode, _ := sendRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/routes",
+			`{"method":"GET","path":"/orders","fakeResponse":{"statusCode":201,"bodyString":"orders"}}`)
+		require.Equal(t, fiber.StatusCreated, statusCode)
+
+		statusCode, body := sendRequest(t, fiberApp, fiber.MethodGet, "/orders", "")
+		assert.Equal(t, fiber.StatusCreated, statusCode)
+		assert.Equal(t, "orders", body)
+
+		_, entries := sendRequest(t, fiberApp, fiber.MethodGet, "/_inzibat/recorder/entries", "")
+		assert.Contains(t, entries, `"/orders"`)
+	})
+
+	t.Run("happy path - requests are journaled and can be verified", func(t *testing.T) {
+		fiberApp := newServer(t, false)
+
+		sendRequest(t, fiberApp, fiber.MethodGet, "/users", "")
+		sendRequest(t, fiberApp, fiber.MethodGet, "/users", "")
+
+		statusCode, body := sendRequest(t, fiberApp, fiber.MethodPost, "/_inzibat/verify",
+			`{"method":"GET","path":"/users"}`)
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Contains(t, body, `"count":2`)
+	})
+
+	t.Run("happy path - exposes request metrics by route", func(t *testing.T) {
+		fiberApp := newServer(t, false)
+
+		sendRequest(t, fiberApp, fiber.MethodGet, "/users", "")
+		sendRequest(t, fiberApp, fiber.MethodGet, "/missing", "")
+
+		statusCode, body := sendRequest(t, fiberApp, fiber.MethodGet, "/_inzibat/metrics", "")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Contains(t, body, `inzibat_http_requests_total{method="GET",route="/users",status_code="200"} 1`)
+		assert.Contains(t, body, `inzibat_http_requests_total{method="GET",route="unmatched",status_code="404"} 1`)
+		assert.Contains(t, body, `inzibat_http_request_duration_seconds_count{method="GET",route="/users"} 1`)
+		assert.NotContains(t, body, `route="/_inzibat/metrics"`)
+	})
+}
+
+func TestSetupServer_RecordSink(t *testing.T) {
+	cfg := &config.Cfg{
+		ServerPort:  8080,
+		Concurrency: 1,
+		Routes: []config.Route{
+			{
+				Method:       fiber.MethodGet,
+				Path:         "/users",
+				FakeResponse: &config.FakeResponse{StatusCode: fiber.StatusOK, BodyString: "users"},
+			},
+		},
+	}
+
+	t.Run("happy path - loads the entries of the previous run", func(t *testing.T) {
+		options := RunOptions{
+			RecordEnabled: true,
+			RecordSink:    &recorder.FileSinkConfig{Dir: t.TempDir()},
+		}
+
+		firstServer, err := setupServer(cfg, nil, options, nil)
+		require.NoError(t, err)
+		_, err = firstServer.App.Test(httptest.NewRequest(fiber.MethodGet, "/users", nil))
+		require.NoError(t, err)
+		require.NoError(t, firstServer.Recorder.Close())
+
+		options.LoadRecordSink = true
+		secondServer, err := setupServer(cfg, nil, options, nil)
+		require.NoError(t, err)
+		defer secondServer.Recorder.Close()
+
+		entries := secondServer.Recorder.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/users", entries[0].Request.Path)
+	})
+
+	t.Run("error path - fails when the directory cannot be created", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(filePath, nil, 0644))
+
+		_, err := setupServer(cfg, nil, RunOptions{
+			RecordEnabled: true,
+			RecordSink:    &recorder.FileSinkConfig{Dir: filepath.Join(filePath, "records")},
+		}, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSetupServer_ProxyRecordAndPlayback(t *testing.T) {
+	cfg := &config.Cfg{
+		ServerPort:  8080,
+		Concurrency: 1,
+		Routes: []config.Route{
+			{
+				Method: fiber.MethodGet,
+				Path:   "/users",
+				FakeResponse: &config.FakeResponse{
+					StatusCode: fiber.StatusOK,
+					BodyString: "mocked users",
+				},
+			},
+		},
+	}
+
+	upstream := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		_, _ = w.Write([]byte("upstream " + r.RequestURI))
+	}))
+	defer upstream.Close()
+
+	sendRequest := func(t *testing.T, fiberApp *fiber.App, target string) (int, string) {
+		response, err := fiberApp.Test(httptest.NewRequest(fiber.MethodGet, target, nil))
+		require.NoError(t, err)
+
+		responseBody, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+
+		return response.StatusCode, string(responseBody)
+	}
+
+	t.Run("happy path - proxies and records unmatched requests", func(t *testing.T) {
+		inzibatServer, err := setupServer(cfg, nil, RunOptions{ProxyRecordUpstream: upstream.URL}, nil)
+		require.NoError(t, err)
+
+		statusCode, body := sendRequest(t, inzibatServer.App, "/users")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "mocked users", body)
+
+		statusCode, body = sendRequest(t, inzibatServer.App, "/orders?page=2")
+		assert.Equal(t, fiber.StatusOK, statusCode)
+		assert.Equal(t, "upstream /orders?page=2", body)
+
+		entries := inzibatServer.Recorder.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/orders", entries[0].Request.Path)
+		assert.Equal(t, map[string][]string{"page": {"2"}}, entries[0].Request.Query)
+		assert.JSONEq(t, `"upstream /orders?page=2"`, string(entries[0].Response.Body))
+	})
+
+	t.Run("happy path - plays recorded requests back before proxying", func(t *testing.T) {
+		playbackRoutes := recorder.ConvertToInzibatConfig(recorder.RecordedSession{
+			Entries: []recorder.RecordedEntry{
+				{
+					Request:  recorder.RecordedRequest{Method: fiber.MethodGet, Path: "/orders", Query: map[string][]string{"page": {"2"}}},
+					Response: recorder.RecordedResponse{StatusCode: fiber.StatusOK, Body: []byte(`"recorded page 2"`)},
+				},
+				{
+					Request:  recorder.RecordedRequest{Method: fiber.MethodGet, Path: "/users"},
+					Response: recorder.RecordedResponse{StatusCode: fiber.StatusOK, Body: []byte(`"recorded users"`)},
+				},
+			},
+		}, 8080).Routes
+
+		inzibatServer, err := setupServer(cfg, nil, RunOptions{
+			ProxyRecordUpstream: upstream.URL,
+			Playback:            true,
+		}, playbackRoutes)
+		require.NoError(t, err)
+
+		_, body := sendRequest(t, inzibatServer.App, "/users")
+		assert.Equal(t, "mocked users", body)
+
+		_, body = sendRequest(t, inzibatServer.App, "/orders?page=2")
+		assert.Equal(t, "recorded page 2", body)
+
+		_, body = sendRequest(t, inzibatServer.App, "/orders?page=3")
+		assert.Equal(t, "upstream /orders?page=3", body)
+
+		entries := inzibatServer.Recorder.List()
+		require.Len(t, entries, 1)
+		assert.Equal(t, map[string][]string{"page": {"3"}}, entries[0].Request.Query)
+	})
+
+	t.Run("happy path - answers not found for unrecorded requests in playback", func(t *testing.T) {
+		playbackRoutes := []config.Route{
+			{
+				Method:       fiber.MethodGet,
+				Path:         "/orders",
+				FakeResponse: &config.FakeResponse{StatusCode: fiber.StatusOK, BodyString: "recorded orders"},
+			},
+		}
+
+		inzibatServer, err := setupServer(cfg, nil, RunOptions{Playback: true}, playbackRoutes)
+		require.NoError(t, err)
+
+		_, body := sendRequest(t, inzibatServer.App, "/orders")
+		assert.Equal(t, "recorded orders", body)
+
+		statusCode, _ := sendRequest(t, inzibatServer.App, "/missing")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+		assert.Nil(t, inzibatServer.Recorder)
+	})
+}
+
+func TestRouteAppBuilder_Build(t *testing.T) {
+	t.Run("happy path - seeds a circuit breaker per upstream host", func(t *testing.T) {
+		circuitBreakerStore, err := handler.NewCircuitBreakerStore()
+		require.NoError(t, err)
+
+		builder := &routeAppBuilder{
+			httpClient:          http.NewHttpClient(),
+			scenarioStore:       handler.NewScenarioStore(),
+			circuitBreakerStore: circuitBreakerStore,
+		}
+		cfg := &config.Cfg{
+			Concurrency: 1,
+			Routes: []config.Route{
+				{
+					Method: fiber.MethodGet,
+					Path:   "/users",
+					RequestTo: &config.RequestTo{
+						Method: fiber.MethodGet,
+						Hosts: []config.UpstreamHost{
+							{Url: "http://users-1:8080", Weight: 1},
+							{Url: "http://users-2:8080", Weight: 1},
+						},
+						LoadBalancing:  config.LoadBalancingRoundRobin,
+						Path:           "/users",
+						CircuitBreaker: config.MergeCircuitBreakerConfig(nil, &config.CircuitBreakerConfig{Enabled: config.BoolPointer(true)}),
+					},
+				},
+			},
+		}
+
+		_, err = builder.Build(cfg)
+		require.NoError(t, err)
+
+		records, err := circuitBreakerStore.List()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "GET /users -> GET http://users-1:8080/users", records[0].RouteKey)
+		assert.Equal(t, "GET /users -> GET http://users-2:8080/users", records[1].RouteKey)
+	})
+}
@@ -2,11 +2,13 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
-	"time"
 
 	validatorPkg "github.com/go-playground/validator/v10"
 	"github.com/goccy/go-json"
@@ -16,22 +18,41 @@ import (
 	"github.com/lynicis/inzibat/client/http"
 	"github.com/lynicis/inzibat/config"
 	"github.com/lynicis/inzibat/handler"
+	"github.com/lynicis/inzibat/journal"
 	_ "github.com/lynicis/inzibat/log"
+	"github.com/lynicis/inzibat/metrics"
 	"github.com/lynicis/inzibat/recorder"
 	"github.com/lynicis/inzibat/router"
 )
 
-func StartServer(configFile string, isGlobalConfig bool, recordEnabled bool) error {
+// RunOptions are the command line switches of a server run.
+type RunOptions struct {
+	RecordEnabled bool
+	// RecordSink also appends recorded requests to JSONL files. With
+	// LoadRecordSink, the entries already in those files are loaded into the
+	// recorder at startup.
+	RecordSink     *recorder.FileSinkConfig
+	LoadRecordSink bool
+	// ProxyRecordUpstream is where requests no route matches are sent. Their
+	// responses are recorded and saved to SessionFile on shutdown.
+	ProxyRecordUpstream string
+	// Playback serves the requests recorded in SessionFile as mocks, after
+	// the routes of the config.
+	Playback    bool
+	SessionFile string
+}
+
+func StartServer(configFile string, isGlobalConfig bool, options RunOptions) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	return StartServerWithContext(ctx, configFile, isGlobalConfig, recordEnabled)
+	return StartServerWithContext(ctx, configFile, isGlobalConfig, options)
 }
 
 func StartServerWithContext(
 	ctx context.Context,
 	configFile string,
 	isGlobalConfig bool,
-	recordEnabled bool,
+	options RunOptions,
 ) error {
 	var resolvedPath string
 	if configFile != "" {
@@ -42,107 +63,286 @@ func StartServerWithContext(
 		resolvedPath = absPath
 	}
 
-	cfg, err := loadConfig(resolvedPath, isGlobalConfig)
+	cfg, configLoader, err := loadConfig(resolvedPath, isGlobalConfig)
+	if err != nil {
+		return err
+	}
+
+	session, err := readRecordedSession(options)
 	if err != nil {
 		return err
 	}
 
-	fiberApp, err := setupServer(cfg, recordEnabled)
+	var playbackRoutes map[string][]config.Route
+	if options.Playback && session != nil {
+		playbackRoutes = playbackRoutesByService(*session, cfg.ServiceConfigs())
+	}
+
+	services, err := setupServices(cfg, configLoader, options, playbackRoutes)
 	if err != nil {
 		return err
 	}
 
-	return runServer(ctx, fiberApp, cfg)
+	if err = watchConfig(ctx, configLoader, services); err != nil {
+		zap.L().Warn("config hot reload disabled", zap.Error(err))
+	}
+
+	err = runServices(ctx, services, cfg.TLS)
+	if options.ProxyRecordUpstream != "" {
+		if saveErr := saveRecordedSession(options.SessionFile, session, services); saveErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to save recorded session: %w", saveErr))
+		}
+	}
+	if closeErr := closeRecorders(services); closeErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to close recorder files: %w", closeErr))
+	}
+
+	return err
 }
 
-func loadConfig(explicitPath string, isGlobalConfig bool) (*config.Cfg, error) {
+// readRecordedSession reads the session file when proxy recording or playback
+// needs it. A proxy recording run without a session file starts a new one.
+func readRecordedSession(options RunOptions) (*recorder.RecordedSession, error) {
+	if options.ProxyRecordUpstream != "" {
+		upstreamUrl, err := url.Parse(options.ProxyRecordUpstream)
+		if err != nil || upstreamUrl.Scheme == "" || upstreamUrl.Host == "" {
+			return nil, fmt.Errorf("invalid proxy record upstream %q", options.ProxyRecordUpstream)
+		}
+	}
+	if options.ProxyRecordUpstream == "" && !options.Playback {
+		return nil, nil
+	}
+
+	session, err := recorder.ReadSessionFile(options.SessionFile)
+	if errors.Is(err, os.ErrNotExist) && options.ProxyRecordUpstream != "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded session: %w", err)
+	}
+
+	return session, nil
+}
+
+// saveRecordedSession appends the requests the services recorded to the
+// session the run started with and writes it back to the session file.
+func saveRecordedSession(filePath string, session *recorder.RecordedSession, services []*service) error {
+	var savedSession recorder.RecordedSession
+	if session != nil {
+		savedSession = *session
+	}
+
+	for _, inzibatService := range services {
+		recordedSession := inzibatService.server.Recorder.Session()
+		if savedSession.StartedAt.IsZero() {
+			savedSession.StartedAt = recordedSession.StartedAt
+		}
+		for _, entry := range recordedSession.Entries {
+			entry.Service = inzibatService.name
+			savedSession.Entries = append(savedSession.Entries, entry)
+		}
+	}
+	savedSession.EntryCount = len(savedSession.Entries)
+
+	if err := recorder.WriteSessionFile(filePath, savedSession); err != nil {
+		return err
+	}
+
+	zap.L().Info("💾 Recorded session saved",
+		zap.String("file", filePath),
+		zap.Int("entries", savedSession.EntryCount),
+	)
+
+	return nil
+}
+
+func loadConfig(explicitPath string, isGlobalConfig bool) (*config.Cfg, *config.Reader, error) {
 	validator := validatorPkg.New()
 	configLoader := config.NewLoader(validator, isGlobalConfig, explicitPath)
 	cfg, err := configLoader.Read()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
 	}
-	return cfg, nil
+	return cfg, configLoader, nil
 }
 
-func setupServer(cfg *config.Cfg, recordEnabled bool) (*fiber.App, error) {
-	endpointHandler := &handler.EndpointHandler{
-		RouteConfig: &cfg.Routes,
+// attachRecordSink loads the entries of previous runs into store when asked
+// to, then writes every new entry to the files of the sink.
+func attachRecordSink(store *recorder.Store, sinkConfig recorder.FileSinkConfig, load bool) error {
+	if load {
+		entries, err := recorder.ReadFileSink(sinkConfig)
+		if err != nil {
+			return fmt.Errorf("failed to load recorded entries: %w", err)
+		}
+		store.Load(entries)
+		zap.L().Info("📂 Recorded entries loaded", zap.String("dir", sinkConfig.Dir), zap.Int("entries", len(entries)))
 	}
-	httpClient := http.NewHttpClient()
-	circuitBreakerStore, err := handler.NewCircuitBreakerStore()
+
+	sink, err := recorder.NewFileSink(sinkConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize circuit breaker store: %w", err)
+		return err
 	}
+	store.SetSink(sink, func(err error) {
+		zap.L().Warn("failed to write recorded entry", zap.Error(err))
+	})
+
+	return nil
+}
 
-	circuitBreakerRouteKeys := make(map[int]string)
-	for routeIndex := range cfg.Routes {
-		route := cfg.Routes[routeIndex]
-		if route.RequestTo == nil || route.RequestTo.CircuitBreaker == nil {
+func closeRecorders(services []*service) error {
+	var closeErrors []error
+	for _, inzibatService := range services {
+		if inzibatService.server.Recorder == nil {
 			continue
 		}
+		if err := inzibatService.server.Recorder.Close(); err != nil {
+			closeErrors = append(closeErrors, err)
+		}
+	}
 
-		if route.RequestTo.CircuitBreaker.Enabled != nil && *route.RequestTo.CircuitBreaker.Enabled {
-			routeKey := handler.BuildCircuitBreakerRouteKey(route)
-			if err = circuitBreakerStore.Seed(routeKey, *route.RequestTo.CircuitBreaker); err != nil {
-				return nil, fmt.Errorf("failed to seed circuit breaker store: %w", err)
-			}
+	return errors.Join(closeErrors...)
+}
+
+func setupServer(
+	cfg *config.Cfg,
+	configLoader *config.Reader,
+	options RunOptions,
+	playbackRoutes []config.Route,
+) (*Server, error) {
+	scenarioStore := handler.NewScenarioStore()
+	circuitBreakerStore, err := handler.NewCircuitBreakerStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize circuit breaker store: %w", err)
+	}
+
+	metricsRegistry := metrics.NewRegistry()
+	metricsRegistry.WatchCircuitBreakers(circuitBreakerStore)
+	httpClient := http.NewHttpClient()
+	httpClient.SetUpstreamObserver(metricsRegistry.ObserveUpstream)
 
-			circuitBreakerRouteKeys[routeIndex] = routeKey
+	requestMethods := router.RequestMethods(slices.Concat(cfg.Routes, playbackRoutes))
+	builder := &routeAppBuilder{
+		httpClient:          httpClient,
+		scenarioStore:       scenarioStore,
+		circuitBreakerStore: circuitBreakerStore,
+		requestMethods:      requestMethods,
+	}
+
+	var recordStore *recorder.Store
+	if options.RecordEnabled || options.ProxyRecordUpstream != "" {
+		recordStore = recorder.NewStore(recorder.DefaultStoreCapacity)
+		if options.RecordSink != nil {
+			if err = attachRecordSink(recordStore, *options.RecordSink, options.LoadRecordSink); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if options.ProxyRecordUpstream != "" {
+		// With --record every request is recorded already.
+		if !options.RecordEnabled {
+			builder.unmatchedHandlers = append(builder.unmatchedHandlers, recorder.NewRecorderMiddleware(recordStore))
 		}
+		builder.unmatchedHandlers = append(
+			builder.unmatchedHandlers,
+			handler.NewPassThroughHandler(httpClient, options.ProxyRecordUpstream),
+		)
+		zap.L().Info("🔁 Proxy recording enabled", zap.String("upstream", options.ProxyRecordUpstream))
 	}
+	if len(playbackRoutes) > 0 {
+		playbackApp, err := builder.Build(&config.Cfg{
+			ServerPort:  cfg.ServerPort,
+			Concurrency: max(cfg.Concurrency, 1),
+			Routes:      playbackRoutes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build playback routes: %w", err)
+		}
 
-	clientHandler := &handler.ClientHandler{
-		Client:                  httpClient,
-		RouteConfig:             &cfg.Routes,
-		CircuitBreakerStore:     circuitBreakerStore,
-		CircuitBreakerRouteKeys: circuitBreakerRouteKeys,
+		playbackHandler := playbackApp.Handler()
+		builder.unmatchedHandlers = []fiber.Handler{func(ctx *fiber.Ctx) error {
+			playbackHandler(ctx.Context())
+			return nil
+		}}
+		zap.L().Info("▶️ Playback enabled", zap.Int("recorded_routes", len(playbackRoutes)))
 	}
 
+	routeTable, err := router.NewRouteTable(cfg, configLoader, builder.Build)
+	if err != nil {
+		return nil, err
+	}
+	routeTable.SetSwapFunc(func(cfg *config.Cfg) {
+		scenarioStore.RetainSequences(sequenceKeys(cfg.Routes, playbackRoutes))
+	})
+
 	fiberApp := fiber.New(fiber.Config{
 		DisableStartupMessage: true,
 		JSONDecoder:           json.Unmarshal,
 		JSONEncoder:           json.Marshal,
 		ReadBufferSize:        4 * 1024 * 1024,
+		RequestMethods:        requestMethods,
 	})
 
-	if recordEnabled {
-		recordStore := recorder.NewStore(recorder.DefaultStoreCapacity)
+	fiberApp.Use(metrics.NewMiddleware(metricsRegistry))
+	metrics.RegisterAdminRoutes(fiberApp, metricsRegistry)
+
+	requestJournal := journal.New(cfg.JournalSize, cfg.JournalMaxBodyBytes)
+	fiberApp.Use(journal.NewMiddleware(requestJournal))
+	journal.RegisterAdminRoutes(fiberApp, requestJournal)
+
+	if options.RecordEnabled {
 		fiberApp.Use(recorder.NewRecorderMiddleware(recordStore))
 		recorder.RegisterAdminRoutes(fiberApp, recordStore)
 		zap.L().Info("🔴 Request recording enabled")
 	}
 
-	mainRouter := &router.MainRouter{
-		Config:          cfg,
-		FiberApp:        fiberApp,
-		EndpointHandler: endpointHandler,
-		ClientHandler:   clientHandler,
-	}
-	mainRouter.CreateRoutes()
+	handler.RegisterScenarioAdminRoutes(fiberApp, scenarioStore)
+	handler.RegisterCircuitBreakerAdminRoutes(fiberApp, circuitBreakerStore)
+	router.RegisterRouteAdminRoutes(fiberApp, routeTable)
+	fiberApp.Use(routeTable.Handler())
 
 	zap.L().Info("🫡 INZIBAT 🪖",
 		zap.Int("open_routes", len(cfg.Routes)),
 		zap.Int("server_port", cfg.ServerPort),
+		zap.Bool("tls", cfg.TLS != nil),
 	)
 
-	return fiberApp, nil
+	return &Server{
+		App:        fiberApp,
+		RouteTable: routeTable,
+		Journal:    requestJournal,
+		Metrics:    metricsRegistry,
+		Recorder:   recordStore,
+	}, nil
 }
 
-func runServer(ctx context.Context, fiberApp *fiber.App, cfg *config.Cfg) error {
-	var serverErr error
-	go func() {
-		if err := fiberApp.Listen(cfg.GetServerAddr()); err != nil {
-			zap.L().Fatal("failed to start http server", zap.Error(err))
-			serverErr = err
-		}
-	}()
+// Server is an Inzibat instance that is not bound to a port yet, for embedding
+// Inzibat in other programs.
+type Server struct {
+	App        *fiber.App
+	RouteTable *router.RouteTable
+	Journal    *journal.Journal
+	Metrics    *metrics.Registry
+	// Recorder holds the recorded requests, when recording is enabled.
+	Recorder *recorder.Store
+}
 
-	<-ctx.Done()
+// New builds a server for an already validated cfg. The config loader
+// validates routes added at runtime and may be nil.
+func New(cfg *config.Cfg, configLoader *config.Reader) (*Server, error) {
+	return setupServer(cfg, configLoader, RunOptions{}, nil)
+}
 
-	if err := fiberApp.ShutdownWithTimeout(5 * time.Second); err != nil {
-		return fmt.Errorf("failed to shutdown gracefully: %w", err)
+// sequenceKeys returns the keys the sequences of the served routes keep their
+// position under.
+func sequenceKeys(routes, playbackRoutes []config.Route) []string {
+	var keys []string
+	for _, routeGroup := range [][]config.Route{routes, playbackRoutes} {
+		for routeIndex, route := range routeGroup {
+			if route.Sequence != nil {
+				keys = append(keys, handler.SequenceKey(route, routeIndex))
+			}
+		}
 	}
 
-	return serverErr
+	return keys
 }
@@ -3,7 +3,9 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/lynicis/inzibat/client/http"
 	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/recorder"
 )
 
 func contains(s, substr string) bool {
@@ -50,7 +53,7 @@ func TestStartServer(t *testing.T) {
 
 		done := make(chan error, 1)
 		go func() {
-			done <- StartServerWithContext(ctx, configFile, false, false)
+			done <- StartServerWithContext(ctx, configFile, false, RunOptions{})
 		}()
 
 		time.Sleep(200 * time.Millisecond)
@@ -72,7 +75,7 @@ func TestStartServer(t *testing.T) {
 
 	t.Run("error path - config file path resolution fails", func(t *testing.T) {
 		invalidPath := "/nonexistent/path/to/config.json"
-		err := StartServer(invalidPath, false, false)
+		err := StartServer(invalidPath, false, RunOptions{})
 
 		assert.Error(t, err)
 		assert.True(t,
@@ -85,7 +88,7 @@ func TestStartServer(t *testing.T) {
 		tmpDir := t.TempDir()
 		nonExistentFile := filepath.Join(tmpDir, "nonexistent.json")
 
-		err := StartServer(nonExistentFile, false, false)
+		err := StartServer(nonExistentFile, false, RunOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read config")
@@ -133,7 +136,7 @@ func TestStartServer(t *testing.T) {
 
 		done := make(chan error, 1)
 		go func() {
-			done <- StartServerWithContext(ctx, "", false, false)
+			done <- StartServerWithContext(ctx, "", false, RunOptions{})
 		}()
 
 		time.Sleep(50 * time.Millisecond)
@@ -182,7 +185,7 @@ func TestStartServer(t *testing.T) {
 		err = os.WriteFile(invalidConfigFile, []byte("invalid json"), 0644)
 		require.NoError(t, err)
 
-		err = StartServer(invalidConfigFile, false, false)
+		err = StartServer(invalidConfigFile, false, RunOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read config")
@@ -218,7 +221,7 @@ func TestStartServer(t *testing.T) {
 
 		done := make(chan error, 1)
 		go func() {
-			done <- StartServerWithContext(ctx, "", false, false)
+			done <- StartServerWithContext(ctx, "", false, RunOptions{})
 		}()
 
 		time.Sleep(200 * time.Millisecond)
@@ -269,7 +272,7 @@ func TestStartServer(t *testing.T) {
 
 		done := make(chan error, 1)
 		go func() {
-			done <- StartServerWithContext(ctx, "", true, false)
+			done <- StartServerWithContext(ctx, "", true, RunOptions{})
 		}()
 
 		time.Sleep(200 * time.Millisecond)
@@ -317,7 +320,7 @@ func TestStartServer(t *testing.T) {
 
 		done := make(chan error, 1)
 		go func() {
-			done <- StartServerWithContext(ctx, configFile, false, false)
+			done <- StartServerWithContext(ctx, configFile, false, RunOptions{})
 		}()
 
 		time.Sleep(200 * time.Millisecond)
@@ -373,7 +376,7 @@ func TestStartServer(t *testing.T) {
 
 		serverDone := make(chan error, 1)
 		go func() {
-			serverDone <- StartServerWithContext(ctx, configFile, false, false)
+			serverDone <- StartServerWithContext(ctx, configFile, false, RunOptions{})
 		}()
 
 		time.Sleep(500 * time.Millisecond)
@@ -399,3 +402,134 @@ func TestStartServer(t *testing.T) {
 		}
 	})
 }
+
+func TestStartServer_ProxyRecord(t *testing.T) {
+	t.Run("happy path - saves proxied requests and plays them back", func(t *testing.T) {
+		upstream := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+			_, _ = w.Write([]byte("upstream " + r.RequestURI))
+		}))
+
+		tmpDir := t.TempDir()
+		configFile := filepath.Join(tmpDir, "inzibat.json")
+		sessionFile := filepath.Join(tmpDir, "session.json")
+		freePort, err := http.GetFreePort()
+		require.NoError(t, err)
+
+		cfg := &config.Cfg{
+			ServerPort:  freePort,
+			Concurrency: 1,
+			Routes: []config.Route{
+				{
+					Method:       "GET",
+					Path:         "/test",
+					FakeResponse: &config.FakeResponse{StatusCode: 200, BodyString: "test"},
+				},
+			},
+		}
+		require.NoError(t, config.WriteConfig(cfg, configFile))
+
+		runServer := func(options RunOptions, target string) string {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				done <- StartServerWithContext(ctx, configFile, false, options)
+			}()
+
+			client := &nethttp.Client{Timeout: 2 * time.Second}
+			var resp *nethttp.Response
+			require.Eventually(t, func() bool {
+				resp, err = client.Get(fmt.Sprintf("http://localhost:%d%s", freePort, target))
+				return err == nil
+			}, 3*time.Second, 50*time.Millisecond)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			cancel()
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+			case <-time.After(7 * time.Second):
+				t.Fatal("server did not shutdown within timeout")
+			}
+
+			return string(body)
+		}
+
+		body := runServer(RunOptions{ProxyRecordUpstream: upstream.URL, SessionFile: sessionFile}, "/orders?page=2")
+		assert.Equal(t, "upstream /orders?page=2", body)
+
+		session, err := recorder.ReadSessionFile(sessionFile)
+		require.NoError(t, err)
+		require.Len(t, session.Entries, 1)
+		assert.Equal(t, "/orders", session.Entries[0].Request.Path)
+
+		upstream.Close()
+
+		body = runServer(RunOptions{Playback: true, SessionFile: sessionFile}, "/orders?page=2")
+		assert.Equal(t, "upstream /orders?page=2", body)
+	})
+}
+
+func TestReadRecordedSession(t *testing.T) {
+	t.Run("happy path - nothing to read without proxy recording or playback", func(t *testing.T) {
+		session, err := readRecordedSession(RunOptions{SessionFile: "missing.json"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, session)
+	})
+
+	t.Run("happy path - proxy recording starts without a session file", func(t *testing.T) {
+		session, err := readRecordedSession(RunOptions{
+			ProxyRecordUpstream: "http://localhost:9090",
+			SessionFile:         filepath.Join(t.TempDir(), "missing.json"),
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, session)
+	})
+
+	t.Run("error path - playback needs a session file", func(t *testing.T) {
+		_, err := readRecordedSession(RunOptions{
+			Playback:    true,
+			SessionFile: filepath.Join(t.TempDir(), "missing.json"),
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error path - invalid proxy record upstream", func(t *testing.T) {
+		_, err := readRecordedSession(RunOptions{ProxyRecordUpstream: "localhost:9090"})
+
+		assert.ErrorContains(t, err, "invalid proxy record upstream")
+	})
+}
+
+func TestSaveRecordedSession(t *testing.T) {
+	t.Run("happy path - tags entries with the service that recorded them", func(t *testing.T) {
+		newRecordingService := func(name, path string) *service {
+			store := recorder.NewStore(recorder.DefaultStoreCapacity)
+			store.Add(recorder.RecordedEntry{
+				Request:  recorder.RecordedRequest{Method: "GET", Path: path},
+				Response: recorder.RecordedResponse{StatusCode: 200},
+			})
+			return &service{name: name, server: &Server{Recorder: store}}
+		}
+		sessionFile := filepath.Join(t.TempDir(), "session.json")
+
+		err := saveRecordedSession(sessionFile, nil, []*service{
+			newRecordingService("", "/orders"),
+			newRecordingService("users", "/profile"),
+		})
+		require.NoError(t, err)
+
+		session, err := recorder.ReadSessionFile(sessionFile)
+		require.NoError(t, err)
+		require.Len(t, session.Entries, 2)
+		assert.Equal(t, "", session.Entries[0].Service)
+		assert.Equal(t, "users", session.Entries[1].Service)
+		assert.Equal(t, "/profile", session.Entries[1].Request.Path)
+	})
+}
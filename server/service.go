@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/recorder"
+	"github.com/lynicis/inzibat/router"
+)
+
+// service is one mock service of the config, answered by its own server.
+type service struct {
+	name   string
+	host   string
+	port   int
+	server *Server
+}
+
+func setupServices(
+	cfg *config.Cfg,
+	configLoader *config.Reader,
+	options RunOptions,
+	playbackRoutes map[string][]config.Route,
+) ([]*service, error) {
+	serviceConfigs := cfg.ServiceConfigs()
+	services := make([]*service, 0, len(serviceConfigs))
+	for _, serviceConfig := range serviceConfigs {
+		serviceOptions := options
+		if options.RecordSink != nil && serviceConfig.Name != "" {
+			serviceSink := *options.RecordSink
+			serviceSink.Name = serviceConfig.Name
+			serviceOptions.RecordSink = &serviceSink
+		}
+
+		inzibatServer, err := setupServer(
+			serviceConfig.Cfg,
+			configLoader,
+			serviceOptions,
+			playbackRoutes[serviceConfig.Name],
+		)
+		if err != nil {
+			if serviceConfig.Name == "" {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to set up service %s: %w", serviceConfig.Name, err)
+		}
+
+		if len(cfg.Services) > 0 {
+			inzibatServer.RouteTable.SetPersistFunc(persistServiceRoutes(configLoader, serviceConfig.Name))
+		}
+
+		services = append(services, &service{
+			name:   serviceConfig.Name,
+			host:   strings.ToLower(serviceConfig.Host),
+			port:   serviceConfig.Cfg.ServerPort,
+			server: inzibatServer,
+		})
+	}
+
+	return services, nil
+}
+
+// playbackRoutesByService converts the entries of session into the playback
+// routes of the service that recorded them, so a service never plays back the
+// recordings of another host. Entries without a service, such as those of the
+// top-level routes, go to the first service. Entries of services that are not
+// in the config anymore are dropped.
+func playbackRoutesByService(
+	session recorder.RecordedSession,
+	serviceConfigs []config.ServiceConfig,
+) map[string][]config.Route {
+	if len(serviceConfigs) == 0 {
+		return nil
+	}
+
+	sessionByService := make(map[string]*recorder.RecordedSession, len(serviceConfigs))
+	for _, serviceConfig := range serviceConfigs {
+		sessionByService[serviceConfig.Name] = &recorder.RecordedSession{}
+	}
+	defaultSession := sessionByService[serviceConfigs[0].Name]
+
+	var droppedEntries int
+	for _, entry := range session.Entries {
+		serviceSession := sessionByService[entry.Service]
+		if entry.Service == "" {
+			serviceSession = defaultSession
+		}
+		if serviceSession == nil {
+			droppedEntries++
+			continue
+		}
+		serviceSession.Entries = append(serviceSession.Entries, entry)
+	}
+	if droppedEntries > 0 {
+		zap.L().Warn("recorded entries of unknown services are not played back",
+			zap.Int("entries", droppedEntries),
+		)
+	}
+
+	playbackRoutes := make(map[string][]config.Route, len(serviceConfigs))
+	for _, serviceConfig := range serviceConfigs {
+		serviceSession := sessionByService[serviceConfig.Name]
+		if len(serviceSession.Entries) == 0 {
+			continue
+		}
+		playbackRoutes[serviceConfig.Name] = recorder.ConvertToInzibatConfig(
+			*serviceSession,
+			serviceConfig.Cfg.ServerPort,
+		).Routes
+	}
+
+	return playbackRoutes
+}
+
+// persistServiceRoutes writes only the routes of the named service back, so
+// the other services in the config file are kept as they are.
+func persistServiceRoutes(configLoader *config.Reader, name string) router.PersistFunc {
+	return func(cfg *config.Cfg, filePath string) error {
+		fileCfg, err := configLoader.ConfigReader.Read(filePath)
+		if err != nil {
+			return err
+		}
+
+		if !fileCfg.SetServiceRoutes(name, cfg.Routes) {
+			return fmt.Errorf("service %s is not in the config file anymore", name)
+		}
+
+		return config.WriteConfig(fileCfg, filePath)
+	}
+}
+
+// runServices listens on the port of every service until ctx is done, then
+// shuts all of them down together.
+func runServices(ctx context.Context, services []*service, tlsCfg *config.TLS) error {
+	var tlsConfig *tls.Config
+	if tlsCfg != nil {
+		var err error
+		if tlsConfig, err = newTLSConfig(tlsCfg); err != nil {
+			return err
+		}
+	}
+
+	ports, appByPort := newPortApps(services)
+	listeners := make([]net.Listener, 0, len(ports))
+	for _, port := range ports {
+		listener, err := newListener(port, tlsConfig)
+		if err != nil {
+			for _, openListener := range listeners {
+				_ = openListener.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	var serverErr error
+	for portIndex, port := range ports {
+		go func() {
+			if err := appByPort[port].Listener(listeners[portIndex]); err != nil {
+				zap.L().Fatal("failed to start http server", zap.Error(err), zap.Int("server_port", port))
+				serverErr = err
+			}
+		}()
+	}
+
+	<-ctx.Done()
+
+	var shutdownErrors []error
+	for _, port := range ports {
+		if err := appByPort[port].ShutdownWithTimeout(5 * time.Second); err != nil {
+			shutdownErrors = append(shutdownErrors, err)
+		}
+	}
+	if err := errors.Join(shutdownErrors...); err != nil {
+		return fmt.Errorf("failed to shutdown gracefully: %w", err)
+	}
+
+	return serverErr
+}
+
+// newPortApps returns the app to serve on each port. A service alone on its
+// port is served as is; services sharing a port are picked by Host header.
+func newPortApps(services []*service) ([]int, map[int]*fiber.App) {
+	servicesByPort := make(map[int][]*service)
+	ports := make([]int, 0, len(services))
+	for _, inzibatService := range services {
+		if _, exists := servicesByPort[inzibatService.port]; !exists {
+			ports = append(ports, inzibatService.port)
+		}
+		servicesByPort[inzibatService.port] = append(servicesByPort[inzibatService.port], inzibatService)
+	}
+
+	appByPort := make(map[int]*fiber.App, len(ports))
+	for _, port := range ports {
+		portServices := servicesByPort[port]
+		if len(portServices) == 1 && portServices[0].host == "" {
+			appByPort[port] = portServices[0].server.App
+			continue
+		}
+
+		appByPort[port] = newVirtualHostApp(portServices)
+	}
+
+	return ports, appByPort
+}
+
+// newVirtualHostApp hands every request to the service whose host matches the
+// Host header, or to the service without a host when none does.
+func newVirtualHostApp(services []*service) *fiber.App {
+	var (
+		requestMethods  []string
+		fallbackHandler fasthttp.RequestHandler
+	)
+	handlerByHost := make(map[string]fasthttp.RequestHandler, len(services))
+	for _, inzibatService := range services {
+		for _, method := range inzibatService.server.RouteTable.RequestMethods() {
+			if !slices.Contains(requestMethods, method) {
+				requestMethods = append(requestMethods, method)
+			}
+		}
+
+		if inzibatService.host == "" {
+			fallbackHandler = inzibatService.server.App.Handler()
+			continue
+		}
+		handlerByHost[inzibatService.host] = inzibatService.server.App.Handler()
+	}
+
+	virtualHostApp := fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		JSONDecoder:           json.Unmarshal,
+		JSONEncoder:           json.Marshal,
+		ReadBufferSize:        4 * 1024 * 1024,
+		RequestMethods:        requestMethods,
+	})
+	virtualHostApp.Use(func(ctx *fiber.Ctx) error {
+		host := requestHost(ctx)
+		handle, exists := handlerByHost[host]
+		if !exists {
+			handle = fallbackHandler
+		}
+		if handle == nil {
+			return ctx.Status(fiber.StatusNotFound).SendString("no service for host " + host)
+		}
+
+		handle(ctx.Context())
+		return nil
+	})
+
+	return virtualHostApp
+}
+
+func requestHost(ctx *fiber.Ctx) string {
+	host := string(ctx.Context().Host())
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	return strings.ToLower(host)
+}
@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	validatorPkg "github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/client/http"
+	"github.com/lynicis/inzibat/config"
+	"github.com/lynicis/inzibat/recorder"
+)
+
+func newServiceTestConfig(t *testing.T, cfg *config.Cfg) (string, *config.Reader, *config.Cfg) {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "inzibat.json")
+	require.NoError(t, config.WriteConfig(cfg, configPath))
+
+	configLoader := config.NewLoader(validatorPkg.New(), false, configPath)
+	loadedCfg, err := configLoader.Read()
+	require.NoError(t, err)
+
+	return configPath, configLoader, loadedCfg
+}
+
+func sendServiceTestRequest(t *testing.T, fiberApp *fiber.App, host, target string) (int, string) {
+	t.Helper()
+
+	request := httptest.NewRequest(fiber.MethodGet, target, nil)
+	if host != "" {
+		request.Host = host
+	}
+	response, err := fiberApp.Test(request, -1)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	return response.StatusCode, string(body)
+}
+
+func TestSetupServices(t *testing.T) {
+	t.Run("happy path - sets up the top-level routes and every service", func(t *testing.T) {
+		_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{reloadTestRoute("/", "gateway")},
+			Services: []config.Service{
+				{Name: "users", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/", "users")}},
+				{Name: "orders", Host: "Orders.Local", Routes: []config.Route{reloadTestRoute("/", "orders")}},
+			},
+		})
+
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+		require.Len(t, services, 3)
+
+		assert.Equal(t, "", services[0].name)
+		assert.Equal(t, 8080, services[0].port)
+		assert.Equal(t, "users", services[1].name)
+		assert.Equal(t, 8081, services[1].port)
+		assert.Equal(t, "orders", services[2].name)
+		assert.Equal(t, 8080, services[2].port)
+		assert.Equal(t, "orders.local", services[2].host)
+
+		_, body := sendServiceTestRequest(t, services[1].server.App, "", "/")
+		assert.Equal(t, "users", body)
+	})
+
+	t.Run("happy path - every service records to files of its own", func(t *testing.T) {
+		_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{reloadTestRoute("/", "gateway")},
+			Services: []config.Service{
+				{Name: "users", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/", "users")}},
+			},
+		})
+		recordDir := t.TempDir()
+
+		services, err := setupServices(cfg, configLoader, RunOptions{
+			RecordEnabled: true,
+			RecordSink:    &recorder.FileSinkConfig{Dir: recordDir},
+		}, nil)
+		require.NoError(t, err)
+		require.Len(t, services, 2)
+
+		sendServiceTestRequest(t, services[0].server.App, "", "/")
+		sendServiceTestRequest(t, services[1].server.App, "", "/")
+		require.NoError(t, closeRecorders(services))
+
+		assert.FileExists(t, filepath.Join(recordDir, "recorder.jsonl"))
+		usersEntries, err := recorder.ReadFileSink(recorder.FileSinkConfig{Dir: recordDir, Name: "users"})
+		require.NoError(t, err)
+		require.Len(t, usersEntries, 1)
+		assert.Equal(t, "/", usersEntries[0].Request.Path)
+	})
+
+	t.Run("happy path - every service plays back only its own recordings", func(t *testing.T) {
+		_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{reloadTestRoute("/", "gateway")},
+			Services: []config.Service{
+				{Name: "users", Host: "users.local", Routes: []config.Route{reloadTestRoute("/", "users")}},
+			},
+		})
+		recordedEntry := func(service, path, body string) recorder.RecordedEntry {
+			return recorder.RecordedEntry{
+				Service:  service,
+				Request:  recorder.RecordedRequest{Method: fiber.MethodGet, Path: path},
+				Response: recorder.RecordedResponse{StatusCode: fiber.StatusOK, Body: []byte(body)},
+			}
+		}
+		session := recorder.RecordedSession{Entries: []recorder.RecordedEntry{
+			recordedEntry("", "/orders", `"gateway orders"`),
+			recordedEntry("users", "/profile", `"users profile"`),
+			recordedEntry("billing", "/invoices", `"billing invoices"`),
+		}}
+
+		services, err := setupServices(
+			cfg,
+			configLoader,
+			RunOptions{Playback: true},
+			playbackRoutesByService(session, cfg.ServiceConfigs()),
+		)
+		require.NoError(t, err)
+		require.Len(t, services, 2)
+
+		_, body := sendServiceTestRequest(t, services[0].server.App, "", "/orders")
+		assert.Equal(t, "gateway orders", body)
+		statusCode, _ := sendServiceTestRequest(t, services[0].server.App, "", "/profile")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+
+		_, body = sendServiceTestRequest(t, services[1].server.App, "", "/profile")
+		assert.Equal(t, "users profile", body)
+		statusCode, _ = sendServiceTestRequest(t, services[1].server.App, "", "/orders")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+		statusCode, _ = sendServiceTestRequest(t, services[1].server.App, "", "/invoices")
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+	})
+
+	t.Run("happy path - persisting a service keeps the rest of the config file", func(t *testing.T) {
+		configPath, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Services: []config.Service{
+				{Name: "users", Routes: []config.Route{reloadTestRoute("/users", "users")}},
+				{Name: "orders", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/orders", "orders")}},
+			},
+		})
+
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+
+		_, err = services[0].server.RouteTable.AddRoute(reloadTestRoute("/admins", "admins"), false)
+		require.NoError(t, err)
+		require.NoError(t, services[0].server.RouteTable.Persist())
+
+		persistedCfg, err := config.ReadOrCreateConfig(configPath)
+		require.NoError(t, err)
+		require.Len(t, persistedCfg.Services, 2)
+		assert.Len(t, persistedCfg.Services[0].Routes, 2)
+		assert.Equal(t, "/admins", persistedCfg.Services[0].Routes[1].Path)
+		assert.Len(t, persistedCfg.Services[1].Routes, 1)
+		assert.Equal(t, 8081, persistedCfg.Services[1].ServerPort)
+		assert.Empty(t, persistedCfg.Routes)
+	})
+}
+
+func TestNewPortApps(t *testing.T) {
+	_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+		ServerPort:  8080,
+		Concurrency: 1,
+		Routes:      []config.Route{reloadTestRoute("/", "gateway")},
+		Services: []config.Service{
+			{Name: "users", Host: "users.local", Routes: []config.Route{reloadTestRoute("/", "users")}},
+			{Name: "orders", Host: "orders.local", Routes: []config.Route{reloadTestRoute("/", "orders")}},
+			{Name: "billing", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/", "billing")}},
+		},
+	})
+	services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+	require.NoError(t, err)
+
+	ports, appByPort := newPortApps(services)
+
+	t.Run("happy path - groups services by port", func(t *testing.T) {
+		assert.Equal(t, []int{8080, 8081}, ports)
+		assert.Same(t, services[3].server.App, appByPort[8081])
+	})
+
+	t.Run("happy path - picks the service by host", func(t *testing.T) {
+		_, body := sendServiceTestRequest(t, appByPort[8080], "users.local:8080", "/")
+		assert.Equal(t, "users", body)
+
+		_, body = sendServiceTestRequest(t, appByPort[8080], "ORDERS.local", "/")
+		assert.Equal(t, "orders", body)
+	})
+
+	t.Run("happy path - unknown hosts go to the service without a host", func(t *testing.T) {
+		_, body := sendServiceTestRequest(t, appByPort[8080], "localhost:8080", "/")
+		assert.Equal(t, "gateway", body)
+	})
+
+	t.Run("error path - unknown host without a fallback service", func(t *testing.T) {
+		virtualHostApp := newVirtualHostApp(services[1:3])
+
+		statusCode, body := sendServiceTestRequest(t, virtualHostApp, "billing.local", "/")
+
+		assert.Equal(t, fiber.StatusNotFound, statusCode)
+		assert.Equal(t, "no service for host billing.local", body)
+	})
+}
+
+func TestRunServices(t *testing.T) {
+	t.Run("happy path - serves every port and shuts them down together", func(t *testing.T) {
+		firstPort, err := http.GetFreePort()
+		require.NoError(t, err)
+		secondPort, err := http.GetFreePort()
+		require.NoError(t, err)
+
+		_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  firstPort,
+			Concurrency: 1,
+			Services: []config.Service{
+				{Name: "users", Routes: []config.Route{reloadTestRoute("/", "users")}},
+				{Name: "orders", ServerPort: secondPort, Routes: []config.Route{reloadTestRoute("/", "orders")}},
+			},
+		})
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- runServices(ctx, services, nil)
+		}()
+
+		client := &nethttp.Client{Timeout: 2 * time.Second}
+		for port, expectedBody := range map[int]string{firstPort: "users", secondPort: "orders"} {
+			assert.Eventually(t, func() bool {
+				response, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+				if err != nil {
+					return false
+				}
+				defer response.Body.Close()
+
+				body, err := io.ReadAll(response.Body)
+				return err == nil && string(body) == expectedBody
+			}, 5*time.Second, 50*time.Millisecond)
+		}
+
+		cancel()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("services did not shut down")
+		}
+	})
+
+	t.Run("error path - port already in use", func(t *testing.T) {
+		listener, err := newListener(0, nil)
+		require.NoError(t, err)
+		defer listener.Close()
+
+		_, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Routes:      []config.Route{reloadTestRoute("/", "users")},
+		})
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+		services[0].port = listener.Addr().(*net.TCPAddr).Port
+
+		err = runServices(context.Background(), services, nil)
+
+		assert.ErrorContains(t, err, "failed to listen on")
+	})
+}
+
+func TestReloadConfig_Services(t *testing.T) {
+	t.Run("happy path - swaps the routes of every service", func(t *testing.T) {
+		serviceCfg := func(usersBody, ordersBody string) *config.Cfg {
+			return &config.Cfg{
+				ServerPort:  8080,
+				Concurrency: 1,
+				Services: []config.Service{
+					{Name: "users", Routes: []config.Route{reloadTestRoute("/", usersBody)}},
+					{Name: "orders", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/", ordersBody)}},
+				},
+			}
+		}
+		configPath, configLoader, cfg := newServiceTestConfig(t, serviceCfg("users", "orders"))
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, config.WriteConfig(serviceCfg("new users", "new orders"), configPath))
+
+		assert.True(t, reloadConfig(configLoader, services))
+		_, body := sendServiceTestRequest(t, services[0].server.App, "", "/")
+		assert.Equal(t, "new users", body)
+		_, body = sendServiceTestRequest(t, services[1].server.App, "", "/")
+		assert.Equal(t, "new orders", body)
+	})
+
+	t.Run("error path - an invalid service keeps every service on its previous routes", func(t *testing.T) {
+		configPath, configLoader, cfg := newServiceTestConfig(t, &config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Services: []config.Service{
+				{Name: "users", Routes: []config.Route{reloadTestRoute("/", "users")}},
+				{Name: "orders", ServerPort: 8081, Routes: []config.Route{reloadTestRoute("/", "orders")}},
+			},
+		})
+		services, err := setupServices(cfg, configLoader, RunOptions{}, nil)
+		require.NoError(t, err)
+
+		purgeRoute := reloadTestRoute("/", "new orders")
+		purgeRoute.Method = "PURGE"
+		require.NoError(t, config.WriteConfig(&config.Cfg{
+			ServerPort:  8080,
+			Concurrency: 1,
+			Services: []config.Service{
+				{Name: "users", Routes: []config.Route{reloadTestRoute("/", "new users")}},
+				{Name: "orders", ServerPort: 8081, Routes: []config.Route{purgeRoute}},
+			},
+		}, configPath))
+
+		assert.False(t, reloadConfig(configLoader, services))
+		_, body := sendServiceTestRequest(t, services[0].server.App, "", "/")
+		assert.Equal(t, "users", body)
+		_, body = sendServiceTestRequest(t, services[1].server.App, "", "/")
+		assert.Equal(t, "orders", body)
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+// newListener listens on serverPort, over TLS when tlsConfig is set.
+func newListener(serverPort int, tlsConfig *tls.Config) (net.Listener, error) {
+	address := fmt.Sprintf(":%d", serverPort)
+	listener, err := net.Listen(fiber.NetworkTCP4, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	if tlsConfig == nil {
+		return listener, nil
+	}
+
+	return tls.NewListener(listener, tlsConfig), nil
+}
+
+func newTLSConfig(tlsCfg *config.TLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	var generatedCA *certificateAuthority
+	if tlsCfg.CertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(tlsCfg.Path(tlsCfg.CertFile), tlsCfg.Path(tlsCfg.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	} else {
+		certDir, err := resolveCertDir(tlsCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if generatedCA, err = loadOrCreateCA(certDir); err != nil {
+			return nil, err
+		}
+		certificate, err := loadOrCreateServerCertificate(certDir, generatedCA, tlsCfg.Hosts)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+
+		if tlsCfg.RequireClientCert && tlsCfg.ClientCAFile == "" {
+			if err := loadOrCreateClientCertificate(certDir, generatedCA); err != nil {
+				return nil, err
+			}
+		}
+
+		zap.L().Info("🔒 serving HTTPS with a generated certificate",
+			zap.String("ca_certificate", filepath.Join(certDir, caCertFileName)),
+		)
+	}
+
+	if !tlsCfg.RequireClientCert {
+		return tlsConfig, nil
+	}
+
+	clientCAs := x509.NewCertPool()
+	if tlsCfg.ClientCAFile != "" {
+		// #nosec G304 - The client CA file is chosen by whoever writes the config
+		caPEM, err := os.ReadFile(tlsCfg.Path(tlsCfg.ClientCAFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("client CA file %s holds no PEM certificates", tlsCfg.ClientCAFile)
+		}
+	} else {
+		clientCAs.AddCert(generatedCA.certificate)
+	}
+
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	tlsConfig.ClientCAs = clientCAs
+
+	return tlsConfig, nil
+}
+
+// resolveCertDir returns where generated certificates are cached: the
+// configured certDir, or ~/.inzibat/certs.
+func resolveCertDir(tlsCfg *config.TLS) (string, error) {
+	if tlsCfg.CertDir != "" {
+		return tlsCfg.Path(tlsCfg.CertDir), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve certificate directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, ".inzibat", "certs"), nil
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lynicis/inzibat/config"
+)
+
+func serveTLS(t *testing.T, tlsCfg *config.TLS) string {
+	t.Helper()
+
+	tlsConfig, err := newTLSConfig(tlsCfg)
+	require.NoError(t, err)
+	listener, err := newListener(0, tlsConfig)
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/secure", func(ctx *fiber.Ctx) error {
+		return ctx.SendString("secure")
+	})
+	go func() {
+		_ = app.Listener(listener)
+	}()
+	t.Cleanup(func() {
+		_ = app.Shutdown()
+	})
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	return fmt.Sprintf("https://localhost:%s/secure", port)
+}
+
+func newTLSTestClient(t *testing.T, caPath string, certificates ...tls.Certificate) *nethttp.Client {
+	t.Helper()
+
+	caPEM, err := os.ReadFile(caPath)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caPEM))
+
+	return &nethttp.Client{Transport: &nethttp.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certificates},
+	}}
+}
+
+func getTLSBody(client *nethttp.Client, url string) (string, error) {
+	response, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	return string(body), err
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("happy path - serves HTTPS with a generated certificate", func(t *testing.T) {
+		certDir := t.TempDir()
+		url := serveTLS(t, &config.TLS{CertDir: certDir})
+
+		client := newTLSTestClient(t, filepath.Join(certDir, caCertFileName))
+		body, err := getTLSBody(client, url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secure", body)
+		assert.NoFileExists(t, filepath.Join(certDir, clientCertFileName))
+	})
+
+	t.Run("happy path - resolves certDir against the config directory", func(t *testing.T) {
+		baseDir := t.TempDir()
+		serveTLS(t, &config.TLS{CertDir: "certs", BaseDir: baseDir})
+
+		assert.FileExists(t, filepath.Join(baseDir, "certs", caCertFileName))
+	})
+
+	t.Run("happy path - serves HTTPS with a configured certificate", func(t *testing.T) {
+		certDir := t.TempDir()
+		ca, err := loadOrCreateCA(certDir)
+		require.NoError(t, err)
+		_, err = loadOrCreateServerCertificate(certDir, ca, nil)
+		require.NoError(t, err)
+
+		url := serveTLS(t, &config.TLS{
+			CertFile: serverCertFileName,
+			KeyFile:  serverKeyFileName,
+			BaseDir:  certDir,
+		})
+
+		client := newTLSTestClient(t, filepath.Join(certDir, caCertFileName))
+		body, err := getTLSBody(client, url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secure", body)
+	})
+
+	t.Run("happy path - mTLS accepts the generated client certificate", func(t *testing.T) {
+		certDir := t.TempDir()
+		url := serveTLS(t, &config.TLS{CertDir: certDir, RequireClientCert: true})
+
+		clientCertificate, err := tls.LoadX509KeyPair(
+			filepath.Join(certDir, clientCertFileName),
+			filepath.Join(certDir, clientKeyFileName),
+		)
+		require.NoError(t, err)
+
+		client := newTLSTestClient(t, filepath.Join(certDir, caCertFileName), clientCertificate)
+		body, err := getTLSBody(client, url)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secure", body)
+	})
+
+	t.Run("error path - mTLS rejects clients without a certificate", func(t *testing.T) {
+		certDir := t.TempDir()
+		url := serveTLS(t, &config.TLS{CertDir: certDir, RequireClientCert: true})
+
+		client := newTLSTestClient(t, filepath.Join(certDir, caCertFileName))
+		_, err := getTLSBody(client, url)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error path - mTLS rejects certificates from another CA", func(t *testing.T) {
+		certDir := t.TempDir()
+		url := serveTLS(t, &config.TLS{
+			CertDir:           certDir,
+			RequireClientCert: true,
+			ClientCAFile:      filepath.Join(certDir, caCertFileName),
+		})
+
+		strangerDir := t.TempDir()
+		strangerCA, err := loadOrCreateCA(strangerDir)
+		require.NoError(t, err)
+		require.NoError(t, loadOrCreateClientCertificate(strangerDir, strangerCA))
+		strangerCertificate, err := tls.LoadX509KeyPair(
+			filepath.Join(strangerDir, clientCertFileName),
+			filepath.Join(strangerDir, clientKeyFileName),
+		)
+		require.NoError(t, err)
+
+		client := newTLSTestClient(t, filepath.Join(certDir, caCertFileName), strangerCertificate)
+		_, err = getTLSBody(client, url)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error path - missing certificate files", func(t *testing.T) {
+		_, err := newTLSConfig(&config.TLS{CertFile: "missing.pem", KeyFile: "missing-key.pem"})
+
+		assert.ErrorContains(t, err, "failed to load TLS certificate")
+	})
+
+	t.Run("error path - client CA file without certificates", func(t *testing.T) {
+		certDir := t.TempDir()
+		clientCAPath := filepath.Join(certDir, "client-ca.pem")
+		require.NoError(t, os.WriteFile(clientCAPath, []byte("not a certificate"), 0600))
+
+		_, err := newTLSConfig(&config.TLS{
+			CertDir:           certDir,
+			RequireClientCert: true,
+			ClientCAFile:      clientCAPath,
+		})
+
+		assert.ErrorContains(t, err, "holds no PEM certificates")
+	})
+}